@@ -74,13 +74,18 @@ func run() int {
 		// init stderr log capturer
 		stdio.Stderr = stdio.NewCgoStdio(config.ServiceConfig.Logger.GetCaptureSharedOrDefault())
 		// schedule all configured backups
-		backends := service.NewBackupBackends(config)
+		backends, err := service.NewBackupBackends(config)
+		if err != nil {
+			return err
+		}
 		scheduler, err := service.ScheduleBackup(ctx, config, backends)
 		if err != nil {
 			return err
 		}
 		// run HTTP server
 		err = runHTTPServer(ctx, backends, config, scheduler)
+		// stop the background backup jobs
+		service.StopBackupJobs()
 		// shutdown shared resources
 		shared.Shutdown()
 		// stop the scheduler
@@ -124,7 +129,10 @@ func readConfiguration() (*model.Config, error) {
 
 func runHTTPServer(ctx context.Context, backends service.BackendsHolder,
 	config *model.Config, scheduler quartz.Scheduler) error {
-	httpServer := server.NewHTTPServer(backends, config, scheduler)
+	httpServer, err := server.NewHTTPServer(backends, config, scheduler)
+	if err != nil {
+		return err
+	}
 	go func() {
 		httpServer.Start()
 	}()
@@ -96,6 +96,47 @@ const docTemplate = `{
                 }
             }
         },
+        "/v1/backups/chains/{routine}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Backup"
+                ],
+                "summary": "Analyze the continuity of the incremental backup chains of the routine.",
+                "operationId": "analyzeChains",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Backup routine name",
+                        "name": "routine",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Gaps and overlaps in the incremental backup chains",
+                        "schema": {
+                            "$ref": "#/definitions/model.ChainReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/backups/full": {
             "get": {
                 "produces": [
@@ -202,7 +243,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/backups/incremental": {
+        "/v1/backups/full/{name}/{timestamp}": {
             "get": {
                 "produces": [
                     "application/json"
@@ -210,34 +251,32 @@ const docTemplate = `{
                 "tags": [
                     "Backup"
                 ],
-                "summary": "Get available incremental backups.",
-                "operationId": "getIncrementalBackups",
+                "summary": "Get the details of a full backup, including the backup manifests.",
+                "operationId": "getFullBackup",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "format": "int64",
-                        "description": "Lower bound timestamp filter",
-                        "name": "from",
-                        "in": "query"
+                        "type": "string",
+                        "description": "Backup routine name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "integer",
                         "format": "int64",
-                        "description": "Upper bound timestamp filter",
-                        "name": "to",
-                        "in": "query"
+                        "description": "Backup timestamp",
+                        "name": "timestamp",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Incremental backups by routine",
+                        "description": "The details of each namespace of the backup",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "array",
-                                "items": {
-                                    "$ref": "#/definitions/model.BackupDetails"
-                                }
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.BackupDetails"
                             }
                         }
                     },
@@ -246,20 +285,21 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
-            }
-        },
-        "/v1/backups/incremental/{name}": {
-            "get": {
-                "produces": [
-                    "application/json"
-                ],
+            },
+            "delete": {
                 "tags": [
                     "Backup"
                 ],
-                "summary": "Get incremental backups for routine.",
-                "operationId": "getIncrementalBackupsForRoutine",
+                "summary": "Delete a full backup.",
+                "operationId": "deleteFullBackup",
                 "parameters": [
                     {
                         "type": "string",
@@ -271,27 +311,21 @@ const docTemplate = `{
                     {
                         "type": "integer",
                         "format": "int64",
-                        "description": "Lower bound timestamp filter",
-                        "name": "from",
-                        "in": "query"
+                        "description": "Backup timestamp",
+                        "name": "timestamp",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "integer",
-                        "format": "int64",
-                        "description": "Upper bound timestamp filter",
-                        "name": "to",
+                        "type": "boolean",
+                        "description": "Delete the backup even if newer incremental backups depend on it",
+                        "name": "force",
                         "in": "query"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Incremental backups for routine",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.BackupDetails"
-                            }
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -304,17 +338,26 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
         },
-        "/v1/backups/schedule/{name}": {
+        "/v1/backups/full/{name}/{timestamp}/hold": {
             "post": {
+                "consumes": [
+                    "application/json"
+                ],
                 "tags": [
                     "Backup"
                 ],
-                "summary": "Schedule a full backup once per routine name.",
-                "operationId": "scheduleFullBackup",
+                "summary": "Place a legal hold on a full backup.",
+                "operationId": "placeFullBackupHold",
                 "parameters": [
                     {
                         "type": "string",
@@ -325,14 +368,25 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "description": "Delay interval in milliseconds",
-                        "name": "delay",
-                        "in": "query"
+                        "format": "int64",
+                        "description": "Backup timestamp",
+                        "name": "timestamp",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Hold details",
+                        "name": "hold",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.BackupHold"
+                        }
                     }
                 ],
                 "responses": {
-                    "202": {
-                        "description": "Accepted"
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -347,106 +401,135 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/v1/config": {
-            "get": {
-                "produces": [
-                    "application/json"
-                ],
+            },
+            "delete": {
                 "tags": [
-                    "Configuration"
+                    "Backup"
+                ],
+                "summary": "Release the legal hold of a full backup.",
+                "operationId": "releaseFullBackupHold",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Backup routine name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Backup timestamp",
+                        "name": "timestamp",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The person or system releasing the hold",
+                        "name": "actor",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Returns the configuration for the service.",
-                "operationId": "readConfig",
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/model.Config"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
-            },
-            "put": {
-                "consumes": [
+            }
+        },
+        "/v1/backups/garbage": {
+            "get": {
+                "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Configuration"
-                ],
-                "summary": "Updates the configuration for the service.",
-                "operationId": "updateConfig",
-                "parameters": [
-                    {
-                        "description": "Configuration details",
-                        "name": "config",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.Config"
-                        }
-                    }
+                    "Backup"
                 ],
+                "summary": "Get the report of the latest garbage collector run.",
+                "operationId": "getGarbageReport",
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "Folders left behind by failed backups",
+                        "schema": {
+                            "$ref": "#/definitions/model.GarbageReport"
+                        }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
-            }
-        },
-        "/v1/config/apply": {
+            },
             "post": {
-                "consumes": [
+                "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Configuration"
+                    "Backup"
                 ],
-                "summary": "Applies the configuration for the service.",
-                "operationId": "applyConfig",
+                "summary": "Run the garbage collector and return its report.",
+                "operationId": "collectGarbage",
                 "responses": {
                     "200": {
-                        "description": "OK"
-                    },
-                    "400": {
-                        "description": "Bad Request",
+                        "description": "Folders left behind by failed backups",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/model.GarbageReport"
                         }
                     }
                 }
             }
         },
-        "/v1/config/clusters": {
+        "/v1/backups/incremental": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Configuration"
+                    "Backup"
+                ],
+                "summary": "Get available incremental backups.",
+                "operationId": "getIncrementalBackups",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Lower bound timestamp filter",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Upper bound timestamp filter",
+                        "name": "to",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Reads all Aerospike clusters from the configuration.",
-                "operationId": "readAllClusters",
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Incremental backups by routine",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
-                                "$ref": "#/definitions/model.AerospikeCluster"
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/model.BackupDetails"
+                                }
                             }
                         }
                     },
@@ -459,30 +542,47 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/config/clusters/{name}": {
+        "/v1/backups/incremental/{name}": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Configuration"
+                    "Backup"
                 ],
-                "summary": "Reads a specific Aerospike cluster from the configuration given its name.",
-                "operationId": "readCluster",
+                "summary": "Get incremental backups for routine.",
+                "operationId": "getIncrementalBackupsForRoutine",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Aerospike cluster name",
+                        "description": "Backup routine name",
                         "name": "name",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Lower bound timestamp filter",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Upper bound timestamp filter",
+                        "name": "to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Incremental backups for routine",
                         "schema": {
-                            "$ref": "#/definitions/model.AerospikeCluster"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.BackupDetails"
+                            }
                         }
                     },
                     "400": {
@@ -492,104 +592,194 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "The specified cluster could not be found",
+                        "description": "Not Found",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
-            },
-            "put": {
-                "consumes": [
+            }
+        },
+        "/v1/backups/incremental/{name}/{timestamp}": {
+            "get": {
+                "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Configuration"
+                    "Backup"
                 ],
-                "summary": "Updates an existing Aerospike cluster in the configuration.",
-                "operationId": "updateCluster",
+                "summary": "Get the details of an incremental backup, including the backup manifests.",
+                "operationId": "getIncrementalBackup",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Aerospike cluster name",
+                        "description": "Backup routine name",
                         "name": "name",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Aerospike cluster details",
-                        "name": "cluster",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.AerospikeCluster"
-                        }
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Backup timestamp",
+                        "name": "timestamp",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "The details of each namespace of the backup",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.BackupDetails"
+                            }
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             },
+            "delete": {
+                "tags": [
+                    "Backup"
+                ],
+                "summary": "Delete an incremental backup.",
+                "operationId": "deleteIncrementalBackup",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Backup routine name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Backup timestamp",
+                        "name": "timestamp",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/backups/incremental/{name}/{timestamp}/hold": {
             "post": {
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Configuration"
+                    "Backup"
                 ],
-                "summary": "Adds an Aerospike cluster to the config.",
-                "operationId": "addCluster",
+                "summary": "Place a legal hold on an incremental backup.",
+                "operationId": "placeIncrementalBackupHold",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Aerospike cluster name",
+                        "description": "Backup routine name",
                         "name": "name",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Aerospike cluster details",
-                        "name": "cluster",
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Backup timestamp",
+                        "name": "timestamp",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Hold details",
+                        "name": "hold",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.AerospikeCluster"
+                            "$ref": "#/definitions/model.BackupHold"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created"
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             },
             "delete": {
                 "tags": [
-                    "Configuration"
+                    "Backup"
                 ],
-                "summary": "Deletes a cluster from the configuration by name.",
-                "operationId": "deleteCluster",
+                "summary": "Release the legal hold of an incremental backup.",
+                "operationId": "releaseIncrementalBackupHold",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Aerospike cluster name",
+                        "description": "Backup routine name",
                         "name": "name",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Backup timestamp",
+                        "name": "timestamp",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The person or system releasing the hold",
+                        "name": "actor",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -601,32 +791,9 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
-                    }
-                }
-            }
-        },
-        "/v1/config/policies": {
-            "get": {
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Configuration"
-                ],
-                "summary": "Reads all policies from the configuration.",
-                "operationId": "readPolicies",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "$ref": "#/definitions/model.BackupPolicy"
-                            }
-                        }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "string"
                         }
@@ -634,30 +801,30 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/config/policies/{name}": {
+        "/v1/backups/prune-preview/{routine}": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Configuration"
+                    "Backup"
                 ],
-                "summary": "Reads a backup policy from the configuration given its name.",
-                "operationId": "readPolicy",
+                "summary": "Preview the backups pruned by the retention policy of the routine.",
+                "operationId": "prunePreview",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Backup policy name",
-                        "name": "name",
+                        "description": "Backup routine name",
+                        "name": "routine",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Backups to be deleted and kept",
                         "schema": {
-                            "$ref": "#/definitions/model.BackupPolicy"
+                            "$ref": "#/definitions/model.PrunePreview"
                         }
                     },
                     "400": {
@@ -667,82 +834,201 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "The specified policy could not be found",
+                        "description": "Not Found",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
-            },
-            "put": {
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/v1/backups/schedule/{name}": {
+            "post": {
                 "tags": [
-                    "Configuration"
+                    "Backup"
                 ],
-                "summary": "Updates an existing policy in the configuration.",
-                "operationId": "updatePolicy",
+                "summary": "Schedule a full backup once per routine name.",
+                "operationId": "scheduleFullBackup",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Backup policy name",
+                        "description": "Backup routine name",
                         "name": "name",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Backup policy details",
-                        "name": "policy",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.BackupPolicy"
-                        }
+                        "type": "integer",
+                        "description": "Delay interval in milliseconds",
+                        "name": "delay",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Run regardless of the routine backup windows",
+                        "name": "override",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK"
+                    "202": {
+                        "description": "Accepted"
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
-            },
-            "post": {
-                "consumes": [
+            }
+        },
+        "/v1/backups/verify/status/{jobId}": {
+            "get": {
+                "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Configuration"
+                    "Backup"
                 ],
-                "summary": "Adds a policy to the config.",
-                "operationId": "addPolicy",
+                "summary": "Get the report of a verification job.",
+                "operationId": "getVerificationStatus",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Backup policy name",
-                        "name": "name",
+                        "type": "integer",
+                        "description": "Verification job id",
+                        "name": "jobId",
                         "in": "path",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Verification report",
+                        "schema": {
+                            "$ref": "#/definitions/model.VerificationReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/backups/verify/{routine}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Backup"
+                ],
+                "summary": "Get the report of the latest verification of the routine backups.",
+                "operationId": "getVerificationReport",
+                "parameters": [
                     {
-                        "description": "Backup policy details",
-                        "name": "policy",
-                        "in": "body",
-                        "required": true,
+                        "type": "string",
+                        "description": "Backup routine name",
+                        "name": "routine",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Verification report",
                         "schema": {
-                            "$ref": "#/definitions/model.BackupPolicy"
+                            "$ref": "#/definitions/model.VerificationReport"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "tags": [
+                    "Backup"
+                ],
+                "summary": "Trigger an asynchronous verification of the routine backups against their manifests.",
+                "operationId": "verifyBackups",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Backup routine name",
+                        "name": "routine",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Verify only the backups created at the given time (epoch millis)",
+                        "name": "timestamp",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Verify only the backup with the given key",
+                        "name": "key",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Verification job id",
+                        "schema": {
+                            "type": "int64"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
                         }
                     }
+                }
+            }
+        },
+        "/v1/config": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Configuration"
                 ],
+                "summary": "Returns the configuration for the service.",
+                "operationId": "readConfig",
                 "responses": {
-                    "201": {
-                        "description": "Created"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Config"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -752,24 +1038,29 @@ const docTemplate = `{
                     }
                 }
             },
-            "delete": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
                 "tags": [
                     "Configuration"
                 ],
-                "summary": "Deletes a policy from the configuration by name.",
-                "operationId": "deletePolicy",
+                "summary": "Updates the configuration for the service.",
+                "operationId": "updateConfig",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Backup policy name",
-                        "name": "name",
-                        "in": "path",
-                        "required": true
+                        "description": "Configuration details",
+                        "name": "config",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Config"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -780,7 +1071,30 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/config/routines": {
+        "/v1/config/apply": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Configuration"
+                ],
+                "summary": "Applies the configuration for the service.",
+                "operationId": "applyConfig",
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/config/clusters": {
             "get": {
                 "produces": [
                     "application/json"
@@ -788,15 +1102,15 @@ const docTemplate = `{
                 "tags": [
                     "Configuration"
                 ],
-                "summary": "Reads all routines from the configuration.",
-                "operationId": "readRoutines",
+                "summary": "Reads all Aerospike clusters from the configuration.",
+                "operationId": "readAllClusters",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
-                                "$ref": "#/definitions/model.BackupRoutine"
+                                "$ref": "#/definitions/model.AerospikeCluster"
                             }
                         }
                     },
@@ -809,7 +1123,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/config/routines/{name}": {
+        "/v1/config/clusters/{name}": {
             "get": {
                 "produces": [
                     "application/json"
@@ -817,12 +1131,12 @@ const docTemplate = `{
                 "tags": [
                     "Configuration"
                 ],
-                "summary": "Reads a specific routine from the configuration given its name.",
-                "operationId": "readRoutine",
+                "summary": "Reads a specific Aerospike cluster from the configuration given its name.",
+                "operationId": "readCluster",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Backup routine name",
+                        "description": "Aerospike cluster name",
                         "name": "name",
                         "in": "path",
                         "required": true
@@ -832,7 +1146,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.BackupRoutine"
+                            "$ref": "#/definitions/model.AerospikeCluster"
                         }
                     },
                     "400": {
@@ -856,23 +1170,23 @@ const docTemplate = `{
                 "tags": [
                     "Configuration"
                 ],
-                "summary": "Updates an existing routine in the configuration.",
-                "operationId": "updateRoutine",
+                "summary": "Updates an existing Aerospike cluster in the configuration.",
+                "operationId": "updateCluster",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Backup routine name",
+                        "description": "Aerospike cluster name",
                         "name": "name",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Backup routine details",
-                        "name": "routine",
+                        "description": "Aerospike cluster details",
+                        "name": "cluster",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.BackupRoutine"
+                            "$ref": "#/definitions/model.AerospikeCluster"
                         }
                     }
                 ],
@@ -895,23 +1209,23 @@ const docTemplate = `{
                 "tags": [
                     "Configuration"
                 ],
-                "summary": "Adds a backup routine to the config.",
-                "operationId": "addRoutine",
+                "summary": "Adds an Aerospike cluster to the config.",
+                "operationId": "addCluster",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Backup routine name",
+                        "description": "Aerospike cluster name",
                         "name": "name",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Backup routine details",
-                        "name": "routine",
+                        "description": "Aerospike cluster details",
+                        "name": "cluster",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.BackupRoutine"
+                            "$ref": "#/definitions/model.AerospikeCluster"
                         }
                     }
                 ],
@@ -931,12 +1245,12 @@ const docTemplate = `{
                 "tags": [
                     "Configuration"
                 ],
-                "summary": "Deletes a backup routine from the configuration by name.",
-                "operationId": "deleteRoutine",
+                "summary": "Deletes a cluster from the configuration by name.",
+                "operationId": "deleteCluster",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Backup routine name",
+                        "description": "Aerospike cluster name",
                         "name": "name",
                         "in": "path",
                         "required": true
@@ -955,7 +1269,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/config/storage": {
+        "/v1/config/policies": {
             "get": {
                 "produces": [
                     "application/json"
@@ -963,15 +1277,15 @@ const docTemplate = `{
                 "tags": [
                     "Configuration"
                 ],
-                "summary": "Reads all storage from the configuration.",
-                "operationId": "readAllStorage",
+                "summary": "Reads all policies from the configuration.",
+                "operationId": "readPolicies",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
-                                "$ref": "#/definitions/model.Storage"
+                                "$ref": "#/definitions/model.BackupPolicy"
                             }
                         }
                     },
@@ -984,7 +1298,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/config/storage/{name}": {
+        "/v1/config/policies/{name}": {
             "get": {
                 "produces": [
                     "application/json"
@@ -992,12 +1306,12 @@ const docTemplate = `{
                 "tags": [
                     "Configuration"
                 ],
-                "summary": "Reads a specific storage from the configuration given its name.",
-                "operationId": "readStorage",
+                "summary": "Reads a backup policy from the configuration given its name.",
+                "operationId": "readPolicy",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Backup storage name",
+                        "description": "Backup policy name",
                         "name": "name",
                         "in": "path",
                         "required": true
@@ -1007,7 +1321,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Storage"
+                            "$ref": "#/definitions/model.BackupPolicy"
                         }
                     },
                     "400": {
@@ -1017,7 +1331,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "The specified storage could not be found",
+                        "description": "The specified policy could not be found",
                         "schema": {
                             "type": "string"
                         }
@@ -1031,23 +1345,23 @@ const docTemplate = `{
                 "tags": [
                     "Configuration"
                 ],
-                "summary": "Updates an existing storage in the configuration.",
-                "operationId": "updateStorage",
+                "summary": "Updates an existing policy in the configuration.",
+                "operationId": "updatePolicy",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Backup storage name",
+                        "description": "Backup policy name",
                         "name": "name",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Backup storage details",
-                        "name": "storage",
+                        "description": "Backup policy details",
+                        "name": "policy",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.Storage"
+                            "$ref": "#/definitions/model.BackupPolicy"
                         }
                     }
                 ],
@@ -1070,23 +1384,23 @@ const docTemplate = `{
                 "tags": [
                     "Configuration"
                 ],
-                "summary": "Adds a storage to the config.",
-                "operationId": "addStorage",
+                "summary": "Adds a policy to the config.",
+                "operationId": "addPolicy",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Backup storage name",
+                        "description": "Backup policy name",
                         "name": "name",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Backup storage details",
-                        "name": "storage",
+                        "description": "Backup policy details",
+                        "name": "policy",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.Storage"
+                            "$ref": "#/definitions/model.BackupPolicy"
                         }
                     }
                 ],
@@ -1106,12 +1420,12 @@ const docTemplate = `{
                 "tags": [
                     "Configuration"
                 ],
-                "summary": "Deletes a storage from the configuration by name.",
-                "operationId": "deleteStorage",
+                "summary": "Deletes a policy from the configuration by name.",
+                "operationId": "deletePolicy",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Backup storage name",
+                        "description": "Backup policy name",
                         "name": "name",
                         "in": "path",
                         "required": true
@@ -1130,32 +1444,24 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/restore/full": {
-            "post": {
-                "consumes": [
+        "/v1/config/routines": {
+            "get": {
+                "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Restore"
-                ],
-                "summary": "Trigger an asynchronous full restore operation.",
-                "operationId": "restoreFull",
-                "parameters": [
-                    {
-                        "description": "Restore request details",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.RestoreRequest"
-                        }
-                    }
+                    "Configuration"
                 ],
+                "summary": "Reads all routines from the configuration.",
+                "operationId": "readRoutines",
                 "responses": {
-                    "202": {
-                        "description": "Restore operation job id",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "int64"
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/model.BackupRoutine"
+                            }
                         }
                     },
                     "400": {
@@ -1167,32 +1473,30 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/restore/incremental": {
-            "post": {
-                "consumes": [
+        "/v1/config/routines/{name}": {
+            "get": {
+                "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Restore"
+                    "Configuration"
                 ],
-                "summary": "Trigger an asynchronous incremental restore operation.",
-                "operationId": "restoreIncremental",
+                "summary": "Reads a specific routine from the configuration given its name.",
+                "operationId": "readRoutine",
                 "parameters": [
                     {
-                        "description": "Restore request details",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.RestoreRequest"
-                        }
+                        "type": "string",
+                        "description": "Backup routine name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "202": {
-                        "description": "Restore operation job id",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "int64"
+                            "$ref": "#/definitions/model.BackupRoutine"
                         }
                     },
                     "400": {
@@ -1200,36 +1504,45 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "404": {
+                        "description": "The specified cluster could not be found",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
-            }
-        },
-        "/v1/restore/status/{jobId}": {
-            "get": {
-                "produces": [
+            },
+            "put": {
+                "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Restore"
+                    "Configuration"
                 ],
-                "summary": "Retrieve status for a restore job.",
-                "operationId": "restoreStatus",
+                "summary": "Updates an existing routine in the configuration.",
+                "operationId": "updateRoutine",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "format": "int64",
-                        "description": "Job ID to retrieve the status",
-                        "name": "jobId",
+                        "type": "string",
+                        "description": "Backup routine name",
+                        "name": "name",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Backup routine details",
+                        "name": "routine",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.BackupRoutine"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Restore job status details",
-                        "schema": {
-                            "$ref": "#/definitions/model.RestoreJobStatus"
-                        }
+                        "description": "OK"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -1238,36 +1551,64 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/v1/restore/timestamp": {
+            },
             "post": {
-                "description": "Restores backup from the given point in time.",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Restore"
+                    "Configuration"
                 ],
-                "summary": "Trigger an asynchronous restore operation to specific point in time.",
-                "operationId": "restoreTimestamp",
+                "summary": "Adds a backup routine to the config.",
+                "operationId": "addRoutine",
                 "parameters": [
                     {
-                        "description": "Restore request details",
-                        "name": "request",
+                        "type": "string",
+                        "description": "Backup routine name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Backup routine details",
+                        "name": "routine",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.RestoreTimestampRequest"
+                            "$ref": "#/definitions/model.BackupRoutine"
                         }
                     }
                 ],
                 "responses": {
-                    "202": {
-                        "description": "Restore operation job id",
+                    "201": {
+                        "description": "Created"
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "type": "int64"
+                            "type": "string"
                         }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "Configuration"
+                ],
+                "summary": "Deletes a backup routine from the configuration by name.",
+                "operationId": "deleteRoutine",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Backup routine name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -1278,38 +1619,59 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/retrieve/configuration/{name}/{timestamp}": {
+        "/v1/config/storage": {
             "get": {
                 "produces": [
-                    "application/zip"
+                    "application/json"
                 ],
                 "tags": [
-                    "Restore"
+                    "Configuration"
                 ],
-                "summary": "Retrieve Aerospike cluster configuration backup",
-                "operationId": "retrieveConfiguration",
+                "summary": "Reads all storage from the configuration.",
+                "operationId": "readAllStorage",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/model.Storage"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/config/storage/{name}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Configuration"
+                ],
+                "summary": "Reads a specific storage from the configuration given its name.",
+                "operationId": "readStorage",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Backup routine name",
+                        "description": "Backup storage name",
                         "name": "name",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "format": "int64",
-                        "description": "Backup timestamp",
-                        "name": "timestamp",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "file"
+                            "$ref": "#/definitions/model.Storage"
                         }
                     },
                     "400": {
@@ -1317,29 +1679,585 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "404": {
+                        "description": "The specified storage could not be found",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
-            }
-        },
-        "/version": {
-            "get": {
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
                 "tags": [
-                    "System"
+                    "Configuration"
+                ],
+                "summary": "Updates an existing storage in the configuration.",
+                "operationId": "updateStorage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Backup storage name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Backup storage details",
+                        "name": "storage",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Storage"
+                        }
+                    }
                 ],
-                "summary": "Returns application version.",
-                "operationId": "version",
                 "responses": {
                     "200": {
-                        "description": "version",
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Configuration"
+                ],
+                "summary": "Adds a storage to the config.",
+                "operationId": "addStorage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Backup storage name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Backup storage details",
+                        "name": "storage",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Storage"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "Configuration"
+                ],
+                "summary": "Deletes a storage from the configuration by name.",
+                "operationId": "deleteStorage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Backup storage name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/config/storage/{name}/test": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Configuration"
+                ],
+                "summary": "Checks the connectivity and permissions of a storage.",
+                "operationId": "testStorage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Backup storage name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The result of each step of the check",
+                        "schema": {
+                            "$ref": "#/definitions/model.StorageCheck"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "The specified storage could not be found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/jobs/queue": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Backup"
+                ],
+                "summary": "Get the backup and restore jobs in the job queue.",
+                "operationId": "getJobQueue",
+                "responses": {
+                    "200": {
+                        "description": "Running jobs, followed by the waiting ones in the order they will run",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.QueuedJob"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/restore/drills/{name}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Restore"
+                ],
+                "summary": "Get the restore drill history of the routine.",
+                "operationId": "getRestoreDrills",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Backup routine name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Restore drill results, the latest first",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.RestoreDrillResult"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/restore/full": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Restore"
+                ],
+                "summary": "Trigger an asynchronous full restore operation.",
+                "operationId": "restoreFull",
+                "parameters": [
+                    {
+                        "description": "Restore request details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.RestoreRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Restore operation job id",
+                        "schema": {
+                            "type": "int64"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/restore/incremental": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Restore"
+                ],
+                "summary": "Trigger an asynchronous incremental restore operation.",
+                "operationId": "restoreIncremental",
+                "parameters": [
+                    {
+                        "description": "Restore request details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.RestoreRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Restore operation job id",
+                        "schema": {
+                            "type": "int64"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/restore/status/{jobId}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Restore"
+                ],
+                "summary": "Retrieve status for a restore job.",
+                "operationId": "restoreStatus",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Job ID to retrieve the status",
+                        "name": "jobId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Restore job status details",
+                        "schema": {
+                            "$ref": "#/definitions/model.RestoreJobStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/restore/timestamp": {
+            "post": {
+                "description": "Restores backup from the given point in time.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Restore"
+                ],
+                "summary": "Trigger an asynchronous restore operation to specific point in time.",
+                "operationId": "restoreTimestamp",
+                "parameters": [
+                    {
+                        "description": "Restore request details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.RestoreTimestampRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Restore operation job id",
+                        "schema": {
+                            "type": "int64"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/retrieve/configuration/{name}/{timestamp}": {
+            "get": {
+                "produces": [
+                    "application/zip"
+                ],
+                "tags": [
+                    "Restore"
+                ],
+                "summary": "Retrieve Aerospike cluster configuration backup",
+                "operationId": "retrieveConfiguration",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Backup routine name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Backup timestamp",
+                        "name": "timestamp",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/routines/{name}/pause": {
+            "post": {
+                "tags": [
+                    "Backup"
+                ],
+                "summary": "Pause the scheduled backups of the routine.",
+                "operationId": "pauseRoutine",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Backup routine name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/routines/{name}/resume": {
+            "post": {
+                "tags": [
+                    "Backup"
+                ],
+                "summary": "Resume the scheduled backups of the paused routine.",
+                "operationId": "resumeRoutine",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Backup routine name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/routines/{name}/status": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Backup"
+                ],
+                "summary": "Get the scheduling status of the routine.",
+                "operationId": "getRoutineStatus",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Backup routine name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Routine status",
+                        "schema": {
+                            "$ref": "#/definitions/model.RoutineStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/storage/{name}/usage": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Backup"
+                ],
+                "summary": "Get the estimated usage of a storage.",
+                "operationId": "getStorageUsage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Backup storage name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Estimated storage usage",
+                        "schema": {
+                            "$ref": "#/definitions/model.StorageUsage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/version": {
+            "get": {
+                "tags": [
+                    "System"
+                ],
+                "summary": "Returns application version.",
+                "operationId": "version",
+                "responses": {
+                    "200": {
+                        "description": "version",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
         "model.AerospikeCluster": {
             "description": "AerospikeCluster represents the configuration for an Aerospike cluster for backup.",
             "type": "object",
@@ -1409,11 +2327,44 @@ const docTemplate = `{
                     "type": "string",
                     "example": "2023-03-19T14:50:00Z"
                 },
+                "hold": {
+                    "description": "The legal hold placed on the backup, if any.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.BackupHold"
+                        }
+                    ]
+                },
+                "hold-history": {
+                    "description": "The holds released from the backup, the oldest first.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.BackupHold"
+                    }
+                },
                 "key": {
                     "description": "The path to the backup files.",
                     "type": "string",
                     "example": "storage/daily/backup/1707915600000/source-ns1"
                 },
+                "key-id": {
+                    "description": "The ID of the key the backup is signed with, if signed.",
+                    "type": "string",
+                    "example": "2024-01"
+                },
+                "locked-until": {
+                    "description": "The time the backup objects are locked until by the storage, if any.",
+                    "type": "string",
+                    "example": "2023-04-20T14:50:00Z"
+                },
+                "manifest": {
+                    "description": "The checksums of the backup files, if recorded.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.BackupManifest"
+                        }
+                    ]
+                },
                 "namespace": {
                     "description": "The namespace of a backup.",
                     "type": "string",
@@ -1425,12 +2376,32 @@ const docTemplate = `{
                     "format": "int64",
                     "example": 100
                 },
+                "replication": {
+                    "description": "The status of the replication to the secondary storages, if any.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ReplicationStatus"
+                    }
+                },
                 "secondary-index-count": {
                     "description": "The number of secondary indexes backed up.",
                     "type": "integer",
                     "format": "int64",
                     "example": 5
                 },
+                "signature": {
+                    "description": "The base64 encoded Ed25519 signature of the backup metadata and manifest, if signed.\nThe hold, the replication status and the lock time are not signed, as they change over time.",
+                    "type": "string"
+                },
+                "signature-valid": {
+                    "description": "Whether the backup signature is valid, if the backup signing is configured.\nFalse for the unsigned backups.",
+                    "type": "boolean"
+                },
+                "storage": {
+                    "description": "The name of the storage the backup is located in.",
+                    "type": "string",
+                    "example": "local"
+                },
                 "udf-count": {
                     "description": "The number of UDF files backed up.",
                     "type": "integer",
@@ -1439,6 +2410,55 @@ const docTemplate = `{
                 }
             }
         },
+        "model.BackupHold": {
+            "description": "BackupHold is a legal hold placed on a backup.",
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "description": "The person or system that placed the hold.",
+                    "type": "string",
+                    "example": "jane.doe"
+                },
+                "created": {
+                    "description": "The time the hold was placed in the ISO 8601 format.",
+                    "type": "string",
+                    "example": "2023-03-20T14:50:00Z"
+                },
+                "reason": {
+                    "description": "The reason the backup is held.",
+                    "type": "string",
+                    "example": "Audit 2024-Q1"
+                },
+                "released": {
+                    "description": "The time the hold was released in the ISO 8601 format, for the released holds.",
+                    "type": "string",
+                    "example": "2023-04-20T14:50:00Z"
+                },
+                "released-by": {
+                    "description": "The person or system that released the hold, for the released holds.",
+                    "type": "string",
+                    "example": "john.doe"
+                }
+            }
+        },
+        "model.BackupManifest": {
+            "description": "BackupManifest contains the checksums of the backup files.",
+            "type": "object",
+            "properties": {
+                "algorithm": {
+                    "description": "The checksum algorithm.",
+                    "type": "string",
+                    "example": "sha256"
+                },
+                "files": {
+                    "description": "The backup files with their checksums.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ManifestFile"
+                    }
+                }
+            }
+        },
         "model.BackupPolicy": {
             "description": "BackupPolicy represents a scheduled backup policy.",
             "type": "object",
@@ -1522,6 +2542,14 @@ const docTemplate = `{
                         }
                     ]
                 },
+                "retention": {
+                    "description": "Retention rules for pruning old full backups and their incremental backups (optional).\nApplied after each successful full backup.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.RetentionPolicy"
+                        }
+                    ]
+                },
                 "retry-delay": {
                     "description": "RetryDelay defines the delay in milliseconds before retrying a failed operation.",
                     "type": "integer",
@@ -1536,6 +2564,14 @@ const docTemplate = `{
                     "type": "integer",
                     "example": 1000
                 },
+                "tiering": {
+                    "description": "Tiering rule for moving aging backups to a colder storage (optional).\nApplied after each successful full backup.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.TieringPolicy"
+                        }
+                    ]
+                },
                 "total-timeout": {
                     "description": "Total socket timeout in milliseconds. Default is 0, that is, no timeout.",
                     "type": "integer",
@@ -1553,6 +2589,13 @@ const docTemplate = `{
                 "storage"
             ],
             "properties": {
+                "allowed-windows": {
+                    "description": "The time windows the backups are allowed to run in (optional, any time if not set).\nThe backups due outside the windows, including the retries, are deferred until a window opens.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.TimeWindow"
+                    }
+                },
                 "backup-policy": {
                     "description": "The name of the corresponding backup policy.",
                     "type": "string",
@@ -1568,6 +2611,13 @@ const docTemplate = `{
                         "dataBin"
                     ]
                 },
+                "blackout": {
+                    "description": "The time windows the backups are not allowed to run in (optional).\nBlackout periods take precedence over the allowed windows.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.TimeWindow"
+                    }
+                },
                 "incr-interval-cron": {
                     "description": "The interval for incremental backup as a cron expression string (optional).",
                     "type": "string",
@@ -1603,6 +2653,24 @@ const docTemplate = `{
                         0
                     ]
                 },
+                "replicate-to": {
+                    "description": "The names of the secondary storages to copy the completed backups to (optional).",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "s3-dr"
+                    ]
+                },
+                "restore-drill": {
+                    "description": "The scheduled test restore of the routine backups (optional).",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.RestoreDrill"
+                        }
+                    ]
+                },
                 "secret-agent": {
                     "description": "The Secret Agent configuration for the routine (optional).",
                     "type": "string",
@@ -1627,6 +2695,11 @@ const docTemplate = `{
                     "description": "The name of the corresponding storage provider configuration.",
                     "type": "string",
                     "example": "aws"
+                },
+                "verify-cron": {
+                    "description": "The interval for the verification of the routine backups against their manifests\nas a cron expression string (optional).",
+                    "type": "string",
+                    "example": "0 0 3 * * *"
                 }
             }
         },
@@ -1634,6 +2707,14 @@ const docTemplate = `{
             "description": "BackupServiceConfig represents the backup service configuration properties.",
             "type": "object",
             "properties": {
+                "garbage-collector": {
+                    "description": "GarbageCollector is the configuration of the partial backup folders collector.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.GarbageCollectorConfig"
+                        }
+                    ]
+                },
                 "http": {
                     "description": "HTTPServer is the backup service HTTP server configuration.",
                     "allOf": [
@@ -1642,6 +2723,14 @@ const docTemplate = `{
                         }
                     ]
                 },
+                "job-queue": {
+                    "description": "JobQueue is the configuration of the queue the backup and restore jobs run through.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.JobQueueConfig"
+                        }
+                    ]
+                },
                 "logger": {
                     "description": "Logger is the backup service logger configuration.",
                     "allOf": [
@@ -1649,6 +2738,87 @@ const docTemplate = `{
                             "$ref": "#/definitions/model.LoggerConfig"
                         }
                     ]
+                },
+                "signing": {
+                    "description": "Signing is the configuration of the backup signing, disabled if not set.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.SigningConfig"
+                        }
+                    ]
+                }
+            }
+        },
+        "model.ChainIssue": {
+            "description": "ChainIssue is a continuity issue of an incremental backup chain.",
+            "type": "object",
+            "properties": {
+                "created": {
+                    "description": "The creation time of the incremental backup in the ISO 8601 format.",
+                    "type": "string",
+                    "example": "2023-03-20T14:50:00Z"
+                },
+                "from": {
+                    "description": "The lower time bound of the incremental backup in the ISO 8601 format.",
+                    "type": "string",
+                    "example": "2023-03-20T14:40:00Z"
+                },
+                "key": {
+                    "description": "The key of the incremental backup.",
+                    "type": "string",
+                    "example": "storage/daily/incremental/1707915600000/data/source-ns1"
+                },
+                "namespace": {
+                    "description": "The namespace of the backups.",
+                    "type": "string",
+                    "example": "source-ns1"
+                },
+                "previous": {
+                    "description": "The creation time of the previous backup of the chain in the ISO 8601 format, if any.",
+                    "type": "string",
+                    "example": "2023-03-20T14:30:00Z"
+                },
+                "type": {
+                    "description": "The type of the issue.",
+                    "type": "string",
+                    "enum": [
+                        "gap",
+                        "overlap",
+                        "missing-full-backup"
+                    ]
+                }
+            }
+        },
+        "model.ChainReport": {
+            "description": "ChainReport is the result of the continuity analysis of the incremental backup chains.",
+            "type": "object",
+            "properties": {
+                "created": {
+                    "description": "The time of the analysis in the ISO 8601 format.",
+                    "type": "string",
+                    "example": "2023-03-20T14:50:00Z"
+                },
+                "full-backup-count": {
+                    "description": "The number of analyzed full backups, one per namespace.",
+                    "type": "integer",
+                    "example": 2
+                },
+                "incremental-backup-count": {
+                    "description": "The number of analyzed incremental backups, one per namespace.",
+                    "type": "integer",
+                    "example": 10
+                },
+                "issues": {
+                    "description": "The continuity issues found.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ChainIssue"
+                    }
+                },
+                "routine": {
+                    "description": "The backup routine name.",
+                    "type": "string",
+                    "example": "daily"
                 }
             }
         },
@@ -1769,35 +2939,137 @@ const docTemplate = `{
                 }
             }
         },
-        "model.FileLoggerConfig": {
-            "description": "FileLoggerConfig represents the configuration for the file logger writer.",
+        "model.FileLoggerConfig": {
+            "description": "FileLoggerConfig represents the configuration for the file logger writer.",
+            "type": "object",
+            "properties": {
+                "compress": {
+                    "description": "Compress determines if the rotated log files should be compressed\nusing gzip. The default is not to perform compression.",
+                    "type": "boolean",
+                    "default": false
+                },
+                "filename": {
+                    "description": "Filename is the file to write logs to.",
+                    "type": "string",
+                    "example": "log.txt"
+                },
+                "maxage": {
+                    "description": "MaxAge is the maximum number of days to retain old log files based on the\ntimestamp encoded in their filename. The default is not to remove old log files\nbased on age.",
+                    "type": "integer",
+                    "default": 0
+                },
+                "maxbackups": {
+                    "description": "MaxBackups is the maximum number of old log files to retain. The default\nis to retain all old log files.",
+                    "type": "integer",
+                    "default": 0
+                },
+                "maxsize": {
+                    "description": "MaxSize is the maximum size in megabytes of the log file before it gets rotated.",
+                    "type": "integer",
+                    "default": 100,
+                    "example": 100
+                }
+            }
+        },
+        "model.GFSRetention": {
+            "description": "GFSRetention defines grandfather-father-son retention tiers.",
+            "type": "object",
+            "properties": {
+                "daily": {
+                    "description": "The number of daily full backups to keep.",
+                    "type": "integer",
+                    "example": 7
+                },
+                "monthly": {
+                    "description": "The number of monthly full backups to keep.",
+                    "type": "integer",
+                    "example": 12
+                },
+                "weekly": {
+                    "description": "The number of weekly full backups to keep.",
+                    "type": "integer",
+                    "example": 4
+                },
+                "yearly": {
+                    "description": "The number of yearly full backups to keep.",
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "model.GarbageCollectorConfig": {
+            "description": "GarbageCollectorConfig represents the configuration of the garbage collector.",
             "type": "object",
             "properties": {
-                "compress": {
-                    "description": "Compress determines if the rotated log files should be compressed\nusing gzip. The default is not to perform compression.",
+                "delete": {
+                    "description": "Whether to delete the garbage found. When false, the garbage is only reported.",
                     "type": "boolean",
                     "default": false
                 },
-                "filename": {
-                    "description": "Filename is the file to write logs to.",
-                    "type": "string",
-                    "example": "log.txt"
+                "enabled": {
+                    "description": "Whether to run the garbage collector periodically.",
+                    "type": "boolean",
+                    "default": true
                 },
-                "maxage": {
-                    "description": "MaxAge is the maximum number of days to retain old log files based on the\ntimestamp encoded in their filename. The default is not to remove old log files\nbased on age.",
+                "grace-period": {
+                    "description": "The minimum age in milliseconds of a backup folder to be considered garbage.",
                     "type": "integer",
-                    "default": 0
+                    "default": 86400000,
+                    "example": 86400000
                 },
-                "maxbackups": {
-                    "description": "MaxBackups is the maximum number of old log files to retain. The default\nis to retain all old log files.",
+                "interval": {
+                    "description": "The interval between garbage collector runs in milliseconds.",
                     "type": "integer",
-                    "default": 0
+                    "default": 3600000,
+                    "example": 3600000
+                }
+            }
+        },
+        "model.GarbageFolder": {
+            "description": "GarbageFolder is a folder left behind by a failed backup.",
+            "type": "object",
+            "properties": {
+                "created": {
+                    "description": "The backup time of the folder in the ISO 8601 format.",
+                    "type": "string",
+                    "example": "2023-03-20T14:50:00Z"
                 },
-                "maxsize": {
-                    "description": "MaxSize is the maximum size in megabytes of the log file before it gets rotated.",
-                    "type": "integer",
-                    "default": 100,
-                    "example": 100
+                "deleted": {
+                    "description": "Whether the folder was deleted.",
+                    "type": "boolean"
+                },
+                "path": {
+                    "description": "The path to the folder.",
+                    "type": "string",
+                    "example": "storage/daily/backup/1707915600000/data/source-ns1"
+                },
+                "reason": {
+                    "description": "The reason the folder is considered garbage.",
+                    "type": "string",
+                    "example": "missing metadata"
+                },
+                "routine": {
+                    "description": "The backup routine name.",
+                    "type": "string",
+                    "example": "daily"
+                }
+            }
+        },
+        "model.GarbageReport": {
+            "description": "GarbageReport is the result of a garbage collector run.",
+            "type": "object",
+            "properties": {
+                "created": {
+                    "description": "The time of the run in the ISO 8601 format.",
+                    "type": "string",
+                    "example": "2023-03-20T14:50:00Z"
+                },
+                "folders": {
+                    "description": "The garbage folders found.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.GarbageFolder"
+                    }
                 }
             }
         },
@@ -1832,6 +3104,56 @@ const docTemplate = `{
                 }
             }
         },
+        "model.JobPriorities": {
+            "description": "JobPriorities represents the priorities of the job types in the job queue.",
+            "type": "object",
+            "properties": {
+                "full": {
+                    "description": "The priority of the full backup jobs.",
+                    "type": "integer",
+                    "default": 1,
+                    "example": 1
+                },
+                "incremental": {
+                    "description": "The priority of the incremental backup jobs.",
+                    "type": "integer",
+                    "default": 2,
+                    "example": 2
+                },
+                "restore": {
+                    "description": "The priority of the restore jobs.",
+                    "type": "integer",
+                    "default": 3,
+                    "example": 3
+                }
+            }
+        },
+        "model.JobQueueConfig": {
+            "description": "JobQueueConfig represents the configuration of the service-wide job queue.",
+            "type": "object",
+            "properties": {
+                "max-concurrent-jobs": {
+                    "description": "The maximum number of the backup and restore jobs running at the same time, at most 2:\na backup and a restore job, as the shared libraries are not reentrant.",
+                    "type": "integer",
+                    "default": 2,
+                    "example": 2
+                },
+                "max-concurrent-jobs-per-cluster": {
+                    "description": "The maximum number of the backup and restore jobs running on the same cluster at the same time.",
+                    "type": "integer",
+                    "default": 1,
+                    "example": 1
+                },
+                "priorities": {
+                    "description": "The priorities of the job types, the jobs of a higher priority run first.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.JobPriorities"
+                        }
+                    ]
+                }
+            }
+        },
         "model.JobStatus": {
             "type": "string",
             "enum": [
@@ -1854,43 +3176,296 @@ const docTemplate = `{
                     "type": "boolean",
                     "default": false
                 },
-                "file-writer": {
-                    "description": "File writer logging configuration.",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/model.FileLoggerConfig"
-                        }
-                    ]
+                "file-writer": {
+                    "description": "File writer logging configuration.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.FileLoggerConfig"
+                        }
+                    ]
+                },
+                "format": {
+                    "description": "Format is the logger format (PLAIN, JSON).",
+                    "type": "string",
+                    "default": "PLAIN",
+                    "enum": [
+                        "PLAIN",
+                        "JSON"
+                    ]
+                },
+                "level": {
+                    "description": "Level is the logger level.",
+                    "type": "string",
+                    "default": "DEBUG",
+                    "enum": [
+                        "TRACE",
+                        "DEBUG",
+                        "INFO",
+                        "WARN",
+                        "WARNING",
+                        "ERROR"
+                    ]
+                },
+                "stdout-writer": {
+                    "description": "Whether to enable logging to the standard output.",
+                    "type": "boolean",
+                    "default": true
+                }
+            }
+        },
+        "model.ManifestFile": {
+            "description": "ManifestFile represents a backup file in the manifest.",
+            "type": "object",
+            "properties": {
+                "checksum": {
+                    "description": "The hex-encoded checksum of the file.",
+                    "type": "string",
+                    "example": "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+                },
+                "path": {
+                    "description": "The path of the file relative to the backup folder.",
+                    "type": "string",
+                    "example": "data/source-ns1/source-ns1_1.asb"
+                },
+                "size": {
+                    "description": "The size of the file in bytes.",
+                    "type": "integer",
+                    "format": "int64",
+                    "example": 2000
+                }
+            }
+        },
+        "model.PruneCandidate": {
+            "description": "PruneCandidate is a backup evaluated by the retention policy.",
+            "type": "object",
+            "properties": {
+                "byte-count": {
+                    "description": "The size of the backup in bytes.",
+                    "type": "integer",
+                    "format": "int64",
+                    "example": 2000
+                },
+                "created": {
+                    "description": "The backup time in the ISO 8601 format.",
+                    "type": "string",
+                    "example": "2023-03-20T14:50:00Z"
+                },
+                "file-count": {
+                    "description": "The number of backup files created.",
+                    "type": "integer",
+                    "format": "int64",
+                    "example": 1
+                },
+                "from": {
+                    "description": "The lower time bound of backup entities in the ISO 8601 format (for incremental backups).",
+                    "type": "string",
+                    "example": "2023-03-19T14:50:00Z"
+                },
+                "hold": {
+                    "description": "The legal hold placed on the backup, if any.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.BackupHold"
+                        }
+                    ]
+                },
+                "hold-history": {
+                    "description": "The holds released from the backup, the oldest first.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.BackupHold"
+                    }
+                },
+                "key": {
+                    "description": "The path to the backup files.",
+                    "type": "string",
+                    "example": "storage/daily/backup/1707915600000/source-ns1"
+                },
+                "key-id": {
+                    "description": "The ID of the key the backup is signed with, if signed.",
+                    "type": "string",
+                    "example": "2024-01"
+                },
+                "locked-until": {
+                    "description": "The time the backup objects are locked until by the storage, if any.",
+                    "type": "string",
+                    "example": "2023-04-20T14:50:00Z"
+                },
+                "manifest": {
+                    "description": "The checksums of the backup files, if recorded.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.BackupManifest"
+                        }
+                    ]
+                },
+                "namespace": {
+                    "description": "The namespace of a backup.",
+                    "type": "string",
+                    "example": "testNamespace"
+                },
+                "reasons": {
+                    "description": "The reasons to keep or delete the backup.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "keep-full"
+                    ]
+                },
+                "record-count": {
+                    "description": "The total number of records backed up.",
+                    "type": "integer",
+                    "format": "int64",
+                    "example": 100
+                },
+                "replication": {
+                    "description": "The status of the replication to the secondary storages, if any.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ReplicationStatus"
+                    }
+                },
+                "secondary-index-count": {
+                    "description": "The number of secondary indexes backed up.",
+                    "type": "integer",
+                    "format": "int64",
+                    "example": 5
+                },
+                "signature": {
+                    "description": "The base64 encoded Ed25519 signature of the backup metadata and manifest, if signed.\nThe hold, the replication status and the lock time are not signed, as they change over time.",
+                    "type": "string"
+                },
+                "signature-valid": {
+                    "description": "Whether the backup signature is valid, if the backup signing is configured.\nFalse for the unsigned backups.",
+                    "type": "boolean"
+                },
+                "storage": {
+                    "description": "The name of the storage the backup is located in.",
+                    "type": "string",
+                    "example": "local"
+                },
+                "type": {
+                    "description": "The backup type.",
+                    "type": "string",
+                    "enum": [
+                        "full",
+                        "incremental"
+                    ],
+                    "example": "full"
+                },
+                "udf-count": {
+                    "description": "The number of UDF files backed up.",
+                    "type": "integer",
+                    "format": "int64",
+                    "example": 2
+                }
+            }
+        },
+        "model.PrunePreview": {
+            "description": "PrunePreview is the result of evaluating the retention policy of a routine.",
+            "type": "object",
+            "properties": {
+                "delete": {
+                    "description": "The backups that would be deleted.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.PruneCandidate"
+                    }
+                },
+                "keep": {
+                    "description": "The backups that would be kept.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.PruneCandidate"
+                    }
+                },
+                "reclaimed-bytes": {
+                    "description": "The total size in bytes of the backups that would be deleted.",
+                    "type": "integer",
+                    "format": "int64",
+                    "example": 2000
+                }
+            }
+        },
+        "model.QueuedJob": {
+            "description": "QueuedJob represents a backup or restore job in the service-wide job queue.",
+            "type": "object",
+            "properties": {
+                "cluster": {
+                    "description": "The seed nodes of the cluster the job runs on.",
+                    "type": "string",
+                    "example": "localhost:3000"
+                },
+                "job-id": {
+                    "description": "The restore job id, for the restore jobs.",
+                    "type": "integer",
+                    "example": 123
+                },
+                "namespace": {
+                    "description": "The namespace of the job.",
+                    "type": "string",
+                    "example": "source-ns1"
+                },
+                "position": {
+                    "description": "The position of the waiting job in the queue, starting from 1. Zero for the running jobs.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "priority": {
+                    "description": "The priority of the job, the jobs of a higher priority run first.",
+                    "type": "integer",
+                    "example": 2
                 },
-                "format": {
-                    "description": "Format is the logger format (PLAIN, JSON).",
+                "queued": {
+                    "description": "The time the job was queued at in the ISO 8601 format.",
+                    "type": "string",
+                    "example": "2023-03-20T14:50:00Z"
+                },
+                "routine": {
+                    "description": "The backup routine name, if the job belongs to a routine.",
+                    "type": "string",
+                    "example": "daily"
+                },
+                "started": {
+                    "description": "The time the job started running at in the ISO 8601 format.",
+                    "type": "string",
+                    "example": "2023-03-20T14:55:00Z"
+                },
+                "state": {
+                    "description": "The state of the job.",
                     "type": "string",
-                    "default": "PLAIN",
                     "enum": [
-                        "PLAIN",
-                        "JSON"
+                        "waiting",
+                        "running"
                     ]
                 },
-                "level": {
-                    "description": "Level is the logger level.",
+                "type": {
+                    "description": "The type of the job.",
                     "type": "string",
-                    "default": "DEBUG",
                     "enum": [
-                        "TRACE",
-                        "DEBUG",
-                        "INFO",
-                        "WARN",
-                        "WARNING",
-                        "ERROR"
+                        "full",
+                        "incremental",
+                        "restore"
                     ]
-                },
-                "stdout-writer": {
-                    "description": "Whether to enable logging to the standard output.",
-                    "type": "boolean",
-                    "default": true
                 }
             }
         },
+        "model.QuotaAction": {
+            "description": "QuotaAction represents the action taken when a full backup would exceed the storage quota.",
+            "type": "string",
+            "enum": [
+                "prune",
+                "skip",
+                "warn"
+            ],
+            "x-enum-varnames": [
+                "QuotaActionPrune",
+                "QuotaActionSkip",
+                "QuotaActionWarn"
+            ]
+        },
         "model.RateLimiterConfig": {
             "description": "RateLimiterConfig is the HTTP server rate limiter configuration.",
             "type": "object",
@@ -1930,6 +3505,152 @@ const docTemplate = `{
                 "RemoveIncremental"
             ]
         },
+        "model.ReplicationState": {
+            "description": "ReplicationState represents the state of the replication of a backup.",
+            "type": "string",
+            "enum": [
+                "pending",
+                "done",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "ReplicationPending",
+                "ReplicationDone",
+                "ReplicationFailed"
+            ]
+        },
+        "model.ReplicationStatus": {
+            "description": "ReplicationStatus represents the status of the replication of a backup to a secondary storage.",
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "description": "The number of replication attempts.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "bytes": {
+                    "description": "The number of bytes copied.",
+                    "type": "integer",
+                    "format": "int64",
+                    "example": 2000
+                },
+                "error": {
+                    "description": "The error of the last failed attempt.",
+                    "type": "string"
+                },
+                "state": {
+                    "description": "The state of the replication.",
+                    "enum": [
+                        "pending",
+                        "done",
+                        "failed"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.ReplicationState"
+                        }
+                    ],
+                    "example": "done"
+                },
+                "storage": {
+                    "description": "The name of the secondary storage.",
+                    "type": "string",
+                    "example": "s3-dr"
+                },
+                "updated": {
+                    "description": "The time of the last update of the status.",
+                    "type": "string",
+                    "example": "2023-03-20T14:50:00Z"
+                }
+            }
+        },
+        "model.RestoreDrill": {
+            "description": "RestoreDrill represents a scheduled test restore of the routine backups.",
+            "type": "object",
+            "required": [
+                "destination-cluster",
+                "interval-cron",
+                "namespace"
+            ],
+            "properties": {
+                "destination-cluster": {
+                    "description": "The name of the Aerospike cluster to restore the backups to.",
+                    "type": "string",
+                    "example": "scratchCluster"
+                },
+                "interval-cron": {
+                    "description": "The interval for the restore drill as a cron expression string.",
+                    "type": "string",
+                    "example": "0 0 4 * * 1"
+                },
+                "namespace": {
+                    "description": "The namespace to restore and the scratch namespace to restore it to.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.RestoreNamespace"
+                        }
+                    ]
+                }
+            }
+        },
+        "model.RestoreDrillResult": {
+            "description": "RestoreDrillResult is the result of a restore drill.",
+            "type": "object",
+            "properties": {
+                "backup-count": {
+                    "description": "The number of applied backups, the full backup and the incremental ones.",
+                    "type": "integer",
+                    "example": 3
+                },
+                "backup-time": {
+                    "description": "The creation time of the restored full backup in the ISO 8601 format.",
+                    "type": "string",
+                    "example": "2023-03-20T00:00:00Z"
+                },
+                "error": {
+                    "description": "The reason the drill failed, if any.",
+                    "type": "string"
+                },
+                "expected-records": {
+                    "description": "The number of records of the applied backups according to their metadata.",
+                    "type": "integer",
+                    "format": "int64",
+                    "example": 100
+                },
+                "finished": {
+                    "description": "The end time of the drill in the ISO 8601 format.",
+                    "type": "string",
+                    "example": "2023-03-20T14:55:00Z"
+                },
+                "namespace": {
+                    "description": "The restored namespace.",
+                    "type": "string",
+                    "example": "source-ns1"
+                },
+                "passed": {
+                    "description": "Whether the drill passed.",
+                    "type": "boolean"
+                },
+                "result": {
+                    "description": "The result of the restore operation.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.RestoreResult"
+                        }
+                    ]
+                },
+                "routine": {
+                    "description": "The backup routine name.",
+                    "type": "string",
+                    "example": "daily"
+                },
+                "started": {
+                    "description": "The start time of the drill in the ISO 8601 format.",
+                    "type": "string",
+                    "example": "2023-03-20T14:50:00Z"
+                }
+            }
+        },
         "model.RestoreJobStatus": {
             "description": "RestoreJobStatus represents a restore job status.",
             "type": "object",
@@ -1967,6 +3688,11 @@ const docTemplate = `{
                     "format": "int64",
                     "example": 8
                 },
+                "queue-position": {
+                    "description": "The position of the restore in the job queue while it waits to run.",
+                    "type": "integer",
+                    "example": 2
+                },
                 "skipped-records": {
                     "type": "integer",
                     "format": "int64",
@@ -2154,6 +3880,61 @@ const docTemplate = `{
                 }
             }
         },
+        "model.RestoreResult": {
+            "type": "object",
+            "properties": {
+                "existed-records": {
+                    "type": "integer",
+                    "format": "int64",
+                    "example": 15
+                },
+                "expired-records": {
+                    "type": "integer",
+                    "format": "int64",
+                    "example": 2
+                },
+                "fresher-records": {
+                    "type": "integer",
+                    "format": "int64",
+                    "example": 5
+                },
+                "ignored-records": {
+                    "type": "integer",
+                    "format": "int64",
+                    "example": 12
+                },
+                "index-count": {
+                    "type": "integer",
+                    "format": "int64",
+                    "example": 3
+                },
+                "inserted-records": {
+                    "type": "integer",
+                    "format": "int64",
+                    "example": 8
+                },
+                "skipped-records": {
+                    "type": "integer",
+                    "format": "int64",
+                    "example": 4
+                },
+                "total-bytes": {
+                    "type": "integer",
+                    "format": "int64",
+                    "example": 2000
+                },
+                "total-records": {
+                    "type": "integer",
+                    "format": "int64",
+                    "example": 10
+                },
+                "udf-count": {
+                    "type": "integer",
+                    "format": "int64",
+                    "example": 1
+                }
+            }
+        },
         "model.RestoreTimestampRequest": {
             "description": "RestoreTimestampRequest represents a restore by timestamp operation request.",
             "type": "object",
@@ -2164,6 +3945,11 @@ const docTemplate = `{
                 "time"
             ],
             "properties": {
+                "allow-gaps": {
+                    "description": "Whether to restore past a gap in the incremental backup chain, logging a warning.\nBy default, such restore is refused, as the records changed during the gap would be missing.",
+                    "type": "boolean",
+                    "example": false
+                },
                 "destination": {
                     "description": "The details of the Aerospike destination cluster.",
                     "allOf": [
@@ -2180,24 +3966,143 @@ const docTemplate = `{
                         }
                     ]
                 },
-                "routine": {
-                    "description": "The backup routine name.",
+                "routine": {
+                    "description": "The backup routine name.",
+                    "type": "string",
+                    "example": "daily"
+                },
+                "secret-agent": {
+                    "description": "Secret Agent configuration (optional).",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.SecretAgent"
+                        }
+                    ]
+                },
+                "time": {
+                    "description": "Required epoch time for recovery. The closest backup before the timestamp will be applied.",
+                    "type": "integer",
+                    "format": "int64",
+                    "example": 1739538000000
+                }
+            }
+        },
+        "model.RetentionPolicy": {
+            "description": "RetentionPolicy defines which backups are kept when the old backups of a routine are pruned.",
+            "type": "object",
+            "properties": {
+                "gfs": {
+                    "description": "Grandfather-father-son retention tiers (optional).",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.GFSRetention"
+                        }
+                    ]
+                },
+                "keep-days": {
+                    "description": "Keep full backups created within the given number of days.",
+                    "type": "integer",
+                    "example": 30
+                },
+                "keep-full": {
+                    "description": "The number of the latest full backups to keep.",
+                    "type": "integer",
+                    "example": 7
+                },
+                "prune-incrementals": {
+                    "description": "Whether to delete incremental backups that belong to pruned full backups (default: true).\nWhen false, incremental backups are kept regardless of their full backup.",
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.RoutineStatus": {
+            "description": "RoutineStatus represents the scheduling status of a backup routine.",
+            "type": "object",
+            "properties": {
+                "last-full-run": {
+                    "description": "The time of the last full backup in the ISO 8601 format.",
+                    "type": "string",
+                    "example": "2023-12-14T10:08:54Z"
+                },
+                "last-incremental-run": {
+                    "description": "The time of the last incremental backup in the ISO 8601 format.",
+                    "type": "string",
+                    "example": "2023-12-15T12:00:00Z"
+                },
+                "next-full-run": {
+                    "description": "The next scheduled full backup time in the ISO 8601 format, unless paused.",
+                    "type": "string",
+                    "example": "2023-12-16T10:00:00Z"
+                },
+                "next-incremental-run": {
+                    "description": "The next scheduled incremental backup time in the ISO 8601 format, unless paused.",
+                    "type": "string",
+                    "example": "2023-12-15T13:00:00Z"
+                },
+                "paused": {
+                    "description": "Whether the scheduled backups of the routine are paused.",
+                    "type": "boolean"
+                },
+                "paused-at": {
+                    "description": "The time the scheduled backups were paused at in the ISO 8601 format.",
+                    "type": "string",
+                    "example": "2023-12-15T13:00:00Z"
+                },
+                "routine": {
+                    "description": "The backup routine name.",
+                    "type": "string",
+                    "example": "daily"
+                }
+            }
+        },
+        "model.S3Credentials": {
+            "description": "S3Credentials represents the explicit credentials of an S3 storage.",
+            "type": "object",
+            "properties": {
+                "access-key-id-env": {
+                    "description": "The name of the environment variable containing the access key ID.",
+                    "type": "string",
+                    "example": "AWS_ACCESS_KEY_ID"
+                },
+                "access-key-id-file": {
+                    "description": "The path to the file containing the access key ID.",
+                    "type": "string",
+                    "example": "/var/run/secrets/s3/access-key-id"
+                },
+                "external-id": {
+                    "description": "The external ID to pass when assuming the role (optional).",
+                    "type": "string",
+                    "example": "aerospike-backup"
+                },
+                "role-arn": {
+                    "description": "The ARN of the role to assume.",
                     "type": "string",
-                    "example": "daily"
+                    "example": "arn:aws:iam::111122223333:role/backup"
                 },
-                "secret-agent": {
-                    "description": "Secret Agent configuration (optional).",
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/model.SecretAgent"
-                        }
-                    ]
+                "role-session-name": {
+                    "description": "The session name of the assumed role (optional).",
+                    "type": "string",
+                    "example": "aerospike-backup-service"
                 },
-                "time": {
-                    "description": "Required epoch time for recovery. The closest backup before the timestamp will be applied.",
-                    "type": "integer",
-                    "format": "int64",
-                    "example": 1739538000000
+                "secret-access-key-env": {
+                    "description": "The name of the environment variable containing the secret access key.",
+                    "type": "string",
+                    "example": "AWS_SECRET_ACCESS_KEY"
+                },
+                "secret-access-key-file": {
+                    "description": "The path to the file containing the secret access key.",
+                    "type": "string",
+                    "example": "/var/run/secrets/s3/secret-access-key"
+                },
+                "sts-endpoint-override": {
+                    "description": "The STS endpoint to assume the role with (optional), such as a VPC endpoint.\nThe default STS endpoint of the region is used if not set.",
+                    "type": "string",
+                    "example": "https://sts.eu-central-1.amazonaws.com"
+                },
+                "web-identity-token-file": {
+                    "description": "The path to the web identity token file to assume the role with,\nsuch as the projected service account token in Kubernetes.",
+                    "type": "string",
+                    "example": "/var/run/secrets/eks.amazonaws.com/serviceaccount/token"
                 }
             }
         },
@@ -2256,6 +4161,49 @@ const docTemplate = `{
                 }
             }
         },
+        "model.SigningConfig": {
+            "description": "SigningConfig represents the configuration of the backup signing.",
+            "type": "object",
+            "required": [
+                "key-id",
+                "keys"
+            ],
+            "properties": {
+                "key-id": {
+                    "description": "The ID of the key the new backups are signed with, one of the configured keys.",
+                    "type": "string",
+                    "example": "2024-01"
+                },
+                "keys": {
+                    "description": "The signing keys by key ID.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/model.SigningKey"
+                    }
+                },
+                "require-signature": {
+                    "description": "Whether to refuse to restore the unsigned backups and the ones with invalid signature.",
+                    "type": "boolean",
+                    "default": false
+                }
+            }
+        },
+        "model.SigningKey": {
+            "description": "SigningKey represents an Ed25519 key pair used for the backup signing.",
+            "type": "object",
+            "properties": {
+                "private-key-file": {
+                    "description": "The path to the PEM encoded PKCS #8 private key file. Required for the active key only.",
+                    "type": "string",
+                    "example": "/etc/aerospike-backup-service/signing.pem"
+                },
+                "public-key-file": {
+                    "description": "The path to the PEM encoded PKIX public key file. Derived from the private key if not set.",
+                    "type": "string",
+                    "example": "/etc/aerospike-backup-service/signing.pub"
+                }
+            }
+        },
         "model.Storage": {
             "description": "Storage represents the configuration for a backup storage details.",
             "type": "object",
@@ -2264,11 +4212,95 @@ const docTemplate = `{
                 "type"
             ],
             "properties": {
+                "azure-account-key-env": {
+                    "description": "The name of the environment variable containing the shared key of the storage account (Azure Blob optional).",
+                    "type": "string",
+                    "example": "AZURE_STORAGE_KEY"
+                },
+                "azure-account-key-file": {
+                    "description": "The path to the file containing the shared key of the storage account (Azure Blob optional).",
+                    "type": "string",
+                    "example": "/var/run/secrets/azure/account-key"
+                },
+                "azure-account-name": {
+                    "description": "The Azure storage account name (Azure Blob required).",
+                    "type": "string",
+                    "example": "asbackupaccount"
+                },
+                "azure-client-id": {
+                    "description": "The client ID of the service principal (Azure Blob optional).",
+                    "type": "string"
+                },
+                "azure-client-secret-env": {
+                    "description": "The name of the environment variable containing the client secret of the service principal (Azure Blob optional).",
+                    "type": "string",
+                    "example": "AZURE_CLIENT_SECRET"
+                },
+                "azure-client-secret-file": {
+                    "description": "The path to the file containing the client secret of the service principal (Azure Blob optional).\nThe default Azure credential is used if no SAS token, shared key or client secret is set.",
+                    "type": "string",
+                    "example": "/var/run/secrets/azure/client-secret"
+                },
+                "azure-container-name": {
+                    "description": "The Azure Blob container name (Azure Blob required).",
+                    "type": "string",
+                    "example": "as-backup-container"
+                },
+                "azure-endpoint-override": {
+                    "description": "An alternative service URL for the Azure SDK to communicate (Azure Blob optional).",
+                    "type": "string",
+                    "example": "http://127.0.0.1:10000/devstoreaccount1"
+                },
+                "azure-sas-token-env": {
+                    "description": "The name of the environment variable containing the shared access signature token (Azure Blob optional).",
+                    "type": "string",
+                    "example": "AZURE_SAS_TOKEN"
+                },
+                "azure-sas-token-file": {
+                    "description": "The path to the file containing the shared access signature token (Azure Blob optional).",
+                    "type": "string",
+                    "example": "/var/run/secrets/azure/sas-token"
+                },
+                "azure-tenant-id": {
+                    "description": "The tenant ID of the service principal (Azure Blob optional).",
+                    "type": "string"
+                },
+                "gcp-bucket": {
+                    "description": "The GCS bucket name (GCP GCS required).",
+                    "type": "string",
+                    "example": "as-backup-bucket"
+                },
+                "gcp-credentials-file": {
+                    "description": "The path to the service account credentials JSON file (GCP GCS optional).\nApplication default credentials are used if not set.",
+                    "type": "string",
+                    "example": "/etc/aerospike-backup-service/gcp-credentials.json"
+                },
+                "gcp-endpoint-override": {
+                    "description": "An alternative endpoint for the GCS client to communicate (GCP GCS optional).",
+                    "type": "string",
+                    "example": "http://localhost:4443/storage/v1/"
+                },
                 "path": {
-                    "description": "The root path for the backup repository.",
+                    "description": "The root path for the backup repository. For GCP GCS and Azure Blob, the prefix within the bucket or container.\nFor SFTP, the absolute path on the host.",
                     "type": "string",
                     "example": "backups"
                 },
+                "quota": {
+                    "description": "The capacity quota of the storage (optional).",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.StorageQuota"
+                        }
+                    ]
+                },
+                "s3-credentials": {
+                    "description": "The explicit credentials of the S3 storage (AWS S3 optional).\nThe credentials of the S3 profile are used if not set.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.S3Credentials"
+                        }
+                    ]
+                },
                 "s3-endpoint-override": {
                     "description": "An alternative endpoint for the S3 SDK to communicate (AWS S3 optional).",
                     "type": "string",
@@ -2279,6 +4311,20 @@ const docTemplate = `{
                     "type": "string",
                     "default": "FATAL"
                 },
+                "s3-object-lock-days": {
+                    "description": "The number of days the backup objects are locked for after they are written (AWS S3, required for Object Lock).",
+                    "type": "integer",
+                    "example": 30
+                },
+                "s3-object-lock-mode": {
+                    "description": "The Object Lock retention mode of the backup objects (AWS S3 optional).\nThe bucket should have Object Lock enabled.",
+                    "type": "string",
+                    "enum": [
+                        "GOVERNANCE",
+                        "COMPLIANCE"
+                    ],
+                    "example": "COMPLIANCE"
+                },
                 "s3-profile": {
                     "description": "The S3 profile name (AWS S3 optional).",
                     "type": "string",
@@ -2289,11 +4335,88 @@ const docTemplate = `{
                     "type": "string",
                     "example": "eu-central-1"
                 },
+                "s3-sse": {
+                    "description": "The server-side encryption of the S3 objects (AWS S3 optional).",
+                    "type": "string",
+                    "enum": [
+                        "SSE-S3",
+                        "SSE-KMS",
+                        "SSE-C"
+                    ],
+                    "example": "SSE-KMS"
+                },
+                "s3-sse-customer-key-env": {
+                    "description": "The name of the environment variable containing the base64-encoded 256-bit customer key for SSE-C\n(AWS S3, the variable or the file is required for SSE-C).",
+                    "type": "string",
+                    "example": "S3_SSE_CUSTOMER_KEY"
+                },
+                "s3-sse-customer-key-file": {
+                    "description": "The path to the file containing the base64-encoded 256-bit customer key for SSE-C\n(AWS S3, the variable or the file is required for SSE-C).",
+                    "type": "string",
+                    "example": "/var/run/secrets/s3/sse-customer-key"
+                },
+                "s3-sse-kms-key-id": {
+                    "description": "The KMS key ID for SSE-KMS (AWS S3 optional). The AWS managed key is used if not set.",
+                    "type": "string",
+                    "example": "arn:aws:kms:eu-central-1:111122223333:key/1234abcd-12ab-34cd-56ef-1234567890ab"
+                },
+                "s3-storage-class": {
+                    "description": "The storage class of the S3 objects (AWS S3 optional).",
+                    "type": "string",
+                    "enum": [
+                        "STANDARD",
+                        "REDUCED_REDUNDANCY",
+                        "STANDARD_IA",
+                        "ONEZONE_IA",
+                        "INTELLIGENT_TIERING",
+                        "GLACIER_IR"
+                    ],
+                    "example": "STANDARD_IA"
+                },
+                "sftp-host": {
+                    "description": "The SFTP host name (SFTP required).",
+                    "type": "string",
+                    "example": "archive.example.com"
+                },
+                "sftp-key-file": {
+                    "description": "The path to the private key file to authenticate (SFTP optional).",
+                    "type": "string",
+                    "example": "/etc/aerospike-backup-service/id_ed25519"
+                },
+                "sftp-known-hosts-file": {
+                    "description": "The path to the known_hosts file to verify the host key (SFTP required).",
+                    "type": "string",
+                    "example": "/etc/aerospike-backup-service/known_hosts"
+                },
+                "sftp-password-env": {
+                    "description": "The name of the environment variable containing the password to authenticate (SFTP optional).",
+                    "type": "string",
+                    "example": "SFTP_PASSWORD"
+                },
+                "sftp-password-file": {
+                    "description": "The path to the file containing the password to authenticate (SFTP optional).\nEither the key file or the password is required.",
+                    "type": "string",
+                    "example": "/var/run/secrets/sftp/password"
+                },
+                "sftp-port": {
+                    "description": "The SFTP port (SFTP optional).",
+                    "type": "integer",
+                    "default": 22,
+                    "example": 22
+                },
+                "sftp-user": {
+                    "description": "The SFTP user name (SFTP required).",
+                    "type": "string",
+                    "example": "backup"
+                },
                 "type": {
-                    "description": "The type of the storage provider (0 - Local, 1 - AWS S3).",
+                    "description": "The type of the storage provider",
                     "enum": [
                         "local",
-                        "aws-s3"
+                        "aws-s3",
+                        "gcp-gcs",
+                        "azure-blob",
+                        "sftp"
                     ],
                     "allOf": [
                         {
@@ -2303,18 +4426,125 @@ const docTemplate = `{
                 }
             }
         },
+        "model.StorageCheck": {
+            "description": "StorageCheck is the result of the connectivity and permission check of a storage.",
+            "type": "object",
+            "properties": {
+                "steps": {
+                    "description": "The steps of the check in the order they were run.\nThe check stops at the first failed step, apart from the cleanup.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.StorageCheckStep"
+                    }
+                },
+                "success": {
+                    "description": "Whether all the steps of the check succeeded.",
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "model.StorageCheckStep": {
+            "description": "StorageCheckStep is a step of the storage check.",
+            "type": "object",
+            "properties": {
+                "duration-millis": {
+                    "description": "The duration of the step in milliseconds.",
+                    "type": "integer",
+                    "example": 20
+                },
+                "error": {
+                    "description": "The error of the failed step, such as a permission error.",
+                    "type": "string",
+                    "example": "access denied"
+                },
+                "name": {
+                    "description": "The name of the step.",
+                    "type": "string",
+                    "enum": [
+                        "connect",
+                        "write",
+                        "read",
+                        "list",
+                        "delete"
+                    ],
+                    "example": "write"
+                }
+            }
+        },
+        "model.StorageQuota": {
+            "description": "StorageQuota represents the capacity quota of a storage.",
+            "type": "object",
+            "required": [
+                "max-bytes"
+            ],
+            "properties": {
+                "action": {
+                    "description": "The action taken when a full backup would exceed the quota (default: warn).",
+                    "default": "warn",
+                    "enum": [
+                        "prune",
+                        "skip",
+                        "warn"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.QuotaAction"
+                        }
+                    ]
+                },
+                "max-bytes": {
+                    "description": "The maximum total size of the backups in the storage in bytes.",
+                    "type": "integer",
+                    "example": 1099511627776
+                }
+            }
+        },
         "model.StorageType": {
             "description": "StorageType represents the type of the backup storage.",
             "type": "string",
             "enum": [
                 "local",
-                "aws-s3"
+                "aws-s3",
+                "gcp-gcs",
+                "azure-blob",
+                "sftp"
             ],
             "x-enum-varnames": [
                 "Local",
-                "S3"
+                "S3",
+                "GcpGCS",
+                "AzureBlob",
+                "SFTP"
             ]
         },
+        "model.StorageUsage": {
+            "description": "StorageUsage represents the estimated usage of a storage.",
+            "type": "object",
+            "properties": {
+                "quota": {
+                    "description": "The storage quota, if configured.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.StorageQuota"
+                        }
+                    ]
+                },
+                "routines": {
+                    "description": "The size of the backups in bytes by routine.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "used-bytes": {
+                    "description": "The total size of the backups in the storage in bytes.",
+                    "type": "integer",
+                    "format": "int64",
+                    "example": 2000
+                }
+            }
+        },
         "model.TLS": {
             "description": "TLS represents the Aerospike cluster TLS configuration options.",
             "type": "object",
@@ -2360,6 +4590,147 @@ const docTemplate = `{
                     "example": "TLSv1.2"
                 }
             }
+        },
+        "model.TieringPolicy": {
+            "description": "TieringPolicy defines when the backups of a routine are moved to a colder storage.",
+            "type": "object",
+            "properties": {
+                "after-days": {
+                    "description": "Move full backups and their incremental backups older than the given number of days.",
+                    "type": "integer",
+                    "example": 14
+                },
+                "storage": {
+                    "description": "The name of the storage to move the backups to.",
+                    "type": "string",
+                    "example": "s3-archive"
+                }
+            }
+        },
+        "model.TimeWindow": {
+            "description": "TimeWindow represents a daily time range, optionally limited to some days of the week.",
+            "type": "object",
+            "required": [
+                "end",
+                "start"
+            ],
+            "properties": {
+                "days": {
+                    "description": "The days of the week the window starts on (optional, all days if not set).",
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "enum": [
+                            "Mon",
+                            "Tue",
+                            "Wed",
+                            "Thu",
+                            "Fri",
+                            "Sat",
+                            "Sun"
+                        ]
+                    },
+                    "example": [
+                        "Sat",
+                        "Sun"
+                    ]
+                },
+                "end": {
+                    "description": "The end time of the window in the HH:MM format.",
+                    "type": "string",
+                    "example": "06:00"
+                },
+                "start": {
+                    "description": "The start time of the window in the HH:MM format.",
+                    "type": "string",
+                    "example": "22:00"
+                },
+                "timezone": {
+                    "description": "The IANA time zone of the window (optional, UTC if not set).",
+                    "type": "string",
+                    "example": "Europe/Berlin"
+                }
+            }
+        },
+        "model.VerificationFailure": {
+            "description": "VerificationFailure is a failed check of a backup.",
+            "type": "object",
+            "properties": {
+                "file": {
+                    "description": "The path of the file relative to the backup folder, if the failure concerns a file.",
+                    "type": "string",
+                    "example": "data/source-ns1/source-ns1_1.asb"
+                },
+                "path": {
+                    "description": "The path to the backup.",
+                    "type": "string",
+                    "example": "storage/daily/backup/1707915600000/data/source-ns1"
+                },
+                "reason": {
+                    "description": "The reason of the failure.",
+                    "type": "string",
+                    "example": "checksum mismatch"
+                }
+            }
+        },
+        "model.VerificationReport": {
+            "description": "VerificationReport is the result of a backup verification job.",
+            "type": "object",
+            "properties": {
+                "backup-count": {
+                    "description": "The number of verified backups, one per namespace.",
+                    "type": "integer",
+                    "example": 2
+                },
+                "error": {
+                    "description": "The error that prevented the verification, if any.",
+                    "type": "string"
+                },
+                "failures": {
+                    "description": "The verification failures.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.VerificationFailure"
+                    }
+                },
+                "file-count": {
+                    "description": "The number of verified files.",
+                    "type": "integer",
+                    "example": 10
+                },
+                "finished": {
+                    "description": "The end time of the verification in the ISO 8601 format.",
+                    "type": "string",
+                    "example": "2023-03-20T14:55:00Z"
+                },
+                "routine": {
+                    "description": "The backup routine name.",
+                    "type": "string",
+                    "example": "daily"
+                },
+                "started": {
+                    "description": "The start time of the verification in the ISO 8601 format.",
+                    "type": "string",
+                    "example": "2023-03-20T14:50:00Z"
+                },
+                "status": {
+                    "description": "The status of the verification job.",
+                    "enum": [
+                        "Running",
+                        "Done",
+                        "Failed"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.JobStatus"
+                        }
+                    ]
+                },
+                "success": {
+                    "description": "Whether all the verified backups are intact.",
+                    "type": "boolean"
+                }
+            }
         }
     },
     "externalDocs": {
@@ -2,6 +2,8 @@ package server
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"strconv"
@@ -156,11 +158,368 @@ func backupsReadFunction(
 	return backend.IncrementalBackupList
 }
 
+// @Summary  Get the details of a full backup, including the backup manifests.
+// @ID       getFullBackup
+// @Tags     Backup
+// @Produce  json
+// @Param    name path string true "Backup routine name"
+// @Param    timestamp path int true "Backup timestamp" format(int64)
+// @Router   /v1/backups/full/{name}/{timestamp} [get]
+// @Success  200 {object} []model.BackupDetails "The details of each namespace of the backup"
+// @Response 400 {string} string
+// @Failure  404 {string} string
+func (ws *HTTPServer) getFullBackup(w http.ResponseWriter, r *http.Request) {
+	ws.readBackup(w, r, true)
+}
+
+// @Summary  Get the details of an incremental backup, including the backup manifests.
+// @ID       getIncrementalBackup
+// @Tags     Backup
+// @Produce  json
+// @Param    name path string true "Backup routine name"
+// @Param    timestamp path int true "Backup timestamp" format(int64)
+// @Router   /v1/backups/incremental/{name}/{timestamp} [get]
+// @Success  200 {object} []model.BackupDetails "The details of each namespace of the backup"
+// @Response 400 {string} string
+// @Failure  404 {string} string
+func (ws *HTTPServer) getIncrementalBackup(w http.ResponseWriter, r *http.Request) {
+	ws.readBackup(w, r, false)
+}
+
+func (ws *HTTPServer) readBackup(w http.ResponseWriter, r *http.Request, isFullBackup bool) {
+	routine := r.PathValue("name")
+	if routine == "" {
+		http.Error(w, "routine name required", http.StatusBadRequest)
+		return
+	}
+	timestamp, err := strconv.ParseInt(r.PathValue("timestamp"), 10, 64)
+	if err != nil {
+		http.Error(w, "timestamp incorrect", http.StatusBadRequest)
+		return
+	}
+	backend, found := ws.backupBackends.Get(routine)
+	if !found {
+		http.Error(w, "routine name not found: "+routine, http.StatusNotFound)
+		return
+	}
+	backups, err := backend.BackupDetails(timestamp, isFullBackup)
+	switch {
+	case errors.Is(err, service.ErrBackupNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case err != nil:
+		http.Error(w, "failed to retrieve backup: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	response, err := json.Marshal(backups)
+	if err != nil {
+		http.Error(w, "failed to parse backup details", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(response)
+	if err != nil {
+		slog.Error("failed to write response", "err", err)
+	}
+}
+
+// @Summary  Delete a full backup.
+// @ID       deleteFullBackup
+// @Tags     Backup
+// @Param    name path string true "Backup routine name"
+// @Param    timestamp path int true "Backup timestamp" format(int64)
+// @Param    force query bool false "Delete the backup even if newer incremental backups depend on it"
+// @Router   /v1/backups/full/{name}/{timestamp} [delete]
+// @Success  204
+// @Response 400 {string} string
+// @Failure  404 {string} string
+// @Failure  409 {string} string
+func (ws *HTTPServer) deleteFullBackup(w http.ResponseWriter, r *http.Request) {
+	ws.deleteBackup(w, r, true)
+}
+
+// @Summary  Delete an incremental backup.
+// @ID       deleteIncrementalBackup
+// @Tags     Backup
+// @Param    name path string true "Backup routine name"
+// @Param    timestamp path int true "Backup timestamp" format(int64)
+// @Router   /v1/backups/incremental/{name}/{timestamp} [delete]
+// @Success  204
+// @Response 400 {string} string
+// @Failure  404 {string} string
+// @Failure  409 {string} string
+func (ws *HTTPServer) deleteIncrementalBackup(w http.ResponseWriter, r *http.Request) {
+	ws.deleteBackup(w, r, false)
+}
+
+func (ws *HTTPServer) deleteBackup(w http.ResponseWriter, r *http.Request, isFullBackup bool) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	routine := r.PathValue("name")
+	if routine == "" {
+		http.Error(w, "routine name required", http.StatusBadRequest)
+		return
+	}
+	timestamp, err := strconv.ParseInt(r.PathValue("timestamp"), 10, 64)
+	if err != nil {
+		http.Error(w, "timestamp incorrect", http.StatusBadRequest)
+		return
+	}
+	var force bool
+	if forceParameter := r.URL.Query().Get("force"); forceParameter != "" {
+		force, err = strconv.ParseBool(forceParameter)
+		if err != nil {
+			http.Error(w, "invalid force query parameter", http.StatusBadRequest)
+			return
+		}
+	}
+	backend, found := ws.backupBackends.Get(routine)
+	if !found {
+		http.Error(w, "routine name not found: "+routine, http.StatusNotFound)
+		return
+	}
+
+	if isFullBackup {
+		err = backend.DeleteFullBackup(timestamp, force)
+	} else {
+		err = backend.DeleteIncrementalBackup(timestamp)
+	}
+	switch {
+	case errors.Is(err, service.ErrBackupNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, service.ErrBackupInUse), errors.Is(err, service.ErrBackupHeld),
+		errors.Is(err, service.ErrBackupLocked):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case err != nil:
+		http.Error(w, "failed to delete backup: "+err.Error(), http.StatusInternalServerError)
+	default:
+		slog.Info("Deleted backup", "routine", routine, "timestamp", timestamp, "full", isFullBackup)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// @Summary  Place a legal hold on a full backup.
+// @ID       placeFullBackupHold
+// @Tags     Backup
+// @Accept   json
+// @Param    name path string true "Backup routine name"
+// @Param    timestamp path int true "Backup timestamp" format(int64)
+// @Param    hold body model.BackupHold true "Hold details"
+// @Router   /v1/backups/full/{name}/{timestamp}/hold [post]
+// @Success  204
+// @Response 400 {string} string
+// @Failure  404 {string} string
+func (ws *HTTPServer) placeFullBackupHold(w http.ResponseWriter, r *http.Request) {
+	ws.placeHold(w, r, true)
+}
+
+// @Summary  Release the legal hold of a full backup.
+// @ID       releaseFullBackupHold
+// @Tags     Backup
+// @Param    name path string true "Backup routine name"
+// @Param    timestamp path int true "Backup timestamp" format(int64)
+// @Param    actor query string false "The person or system releasing the hold"
+// @Router   /v1/backups/full/{name}/{timestamp}/hold [delete]
+// @Success  204
+// @Response 400 {string} string
+// @Failure  404 {string} string
+func (ws *HTTPServer) releaseFullBackupHold(w http.ResponseWriter, r *http.Request) {
+	ws.releaseHold(w, r, true)
+}
+
+// @Summary  Place a legal hold on an incremental backup.
+// @ID       placeIncrementalBackupHold
+// @Tags     Backup
+// @Accept   json
+// @Param    name path string true "Backup routine name"
+// @Param    timestamp path int true "Backup timestamp" format(int64)
+// @Param    hold body model.BackupHold true "Hold details"
+// @Router   /v1/backups/incremental/{name}/{timestamp}/hold [post]
+// @Success  204
+// @Response 400 {string} string
+// @Failure  404 {string} string
+func (ws *HTTPServer) placeIncrementalBackupHold(w http.ResponseWriter, r *http.Request) {
+	ws.placeHold(w, r, false)
+}
+
+// @Summary  Release the legal hold of an incremental backup.
+// @ID       releaseIncrementalBackupHold
+// @Tags     Backup
+// @Param    name path string true "Backup routine name"
+// @Param    timestamp path int true "Backup timestamp" format(int64)
+// @Param    actor query string false "The person or system releasing the hold"
+// @Router   /v1/backups/incremental/{name}/{timestamp}/hold [delete]
+// @Success  204
+// @Response 400 {string} string
+// @Failure  404 {string} string
+func (ws *HTTPServer) releaseIncrementalBackupHold(w http.ResponseWriter, r *http.Request) {
+	ws.releaseHold(w, r, false)
+}
+
+func (ws *HTTPServer) placeHold(w http.ResponseWriter, r *http.Request, isFullBackup bool) {
+	var hold model.BackupHold
+	if err := json.NewDecoder(r.Body).Decode(&hold); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := hold.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	hold.Created = time.Now()
+	ws.updateHold(w, r, func(backend *service.BackupBackend, routine string, timestamp int64) error {
+		err := backend.PlaceHold(timestamp, isFullBackup, hold)
+		if err == nil {
+			slog.Info("Placed backup hold", "routine", routine, "timestamp", timestamp,
+				"full", isFullBackup, "reason", hold.Reason, "actor", hold.Actor)
+		}
+		return err
+	})
+}
+
+func (ws *HTTPServer) releaseHold(w http.ResponseWriter, r *http.Request, isFullBackup bool) {
+	ws.updateHold(w, r, func(backend *service.BackupBackend, routine string, timestamp int64) error {
+		actor := r.URL.Query().Get("actor")
+		err := backend.ReleaseHold(timestamp, isFullBackup, actor)
+		if err == nil {
+			slog.Info("Released backup hold", "routine", routine, "timestamp", timestamp,
+				"full", isFullBackup, "actor", actor)
+		}
+		return err
+	})
+}
+
+func (ws *HTTPServer) updateHold(w http.ResponseWriter, r *http.Request,
+	update func(backend *service.BackupBackend, routine string, timestamp int64) error) {
+	routine := r.PathValue("name")
+	if routine == "" {
+		http.Error(w, "routine name required", http.StatusBadRequest)
+		return
+	}
+	timestamp, err := strconv.ParseInt(r.PathValue("timestamp"), 10, 64)
+	if err != nil {
+		http.Error(w, "timestamp incorrect", http.StatusBadRequest)
+		return
+	}
+	backend, found := ws.backupBackends.Get(routine)
+	if !found {
+		http.Error(w, "routine name not found: "+routine, http.StatusNotFound)
+		return
+	}
+	err = update(backend, routine, timestamp)
+	switch {
+	case errors.Is(err, service.ErrBackupNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case err != nil:
+		http.Error(w, "failed to update backup hold: "+err.Error(), http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// @Summary  Get the report of the latest garbage collector run.
+// @ID       getGarbageReport
+// @Tags     Backup
+// @Produce  json
+// @Router   /v1/backups/garbage [get]
+// @Success  200 {object} model.GarbageReport "Folders left behind by failed backups"
+// @Failure  404 {string} string
+func (ws *HTTPServer) getGarbageReport(w http.ResponseWriter, _ *http.Request) {
+	report := service.LastGarbageReport()
+	if report == nil {
+		http.Error(w, "garbage collector has not run yet", http.StatusNotFound)
+		return
+	}
+	ws.writeGarbageReport(w, report)
+}
+
+// @Summary  Run the garbage collector and return its report.
+// @ID       collectGarbage
+// @Tags     Backup
+// @Produce  json
+// @Router   /v1/backups/garbage [post]
+// @Success  200 {object} model.GarbageReport "Folders left behind by failed backups"
+func (ws *HTTPServer) collectGarbage(w http.ResponseWriter, _ *http.Request) {
+	ws.writeGarbageReport(w, service.CollectGarbage(ws.config, ws.backupBackends, time.Now()))
+}
+
+func (ws *HTTPServer) writeGarbageReport(w http.ResponseWriter, report *model.GarbageReport) {
+	response, err := json.Marshal(report)
+	if err != nil {
+		http.Error(w, "failed to parse garbage report", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(response)
+	if err != nil {
+		slog.Error("failed to write response", "err", err)
+	}
+}
+
+// @Summary  Preview the backups pruned by the retention policy of the routine.
+// @ID       prunePreview
+// @Tags     Backup
+// @Produce  json
+// @Param    routine path string true "Backup routine name"
+// @Router   /v1/backups/prune-preview/{routine} [get]
+// @Success  200 {object} model.PrunePreview "Backups to be deleted and kept"
+// @Response 400 {string} string
+// @Failure  404 {string} string
+func (ws *HTTPServer) prunePreview(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	routineName := r.PathValue("routine")
+	if routineName == "" {
+		http.Error(w, "routine name required", http.StatusBadRequest)
+		return
+	}
+	routine, found := ws.config.BackupRoutines[routineName]
+	if !found {
+		http.Error(w, "routine name not found: "+routineName, http.StatusNotFound)
+		return
+	}
+	backend, found := ws.backupBackends.Get(routineName)
+	if !found {
+		http.Error(w, "routine name not found: "+routineName, http.StatusNotFound)
+		return
+	}
+	var retention *model.RetentionPolicy
+	if policy, found := ws.config.BackupPolicies[routine.BackupPolicy]; found {
+		retention = policy.Retention
+	}
+	preview, err := backend.PrunePreview(retention, time.Now())
+	if err != nil {
+		http.Error(w, "failed to evaluate retention policy: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	response, err := json.Marshal(preview)
+	if err != nil {
+		http.Error(w, "failed to parse prune preview", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(response)
+	if err != nil {
+		slog.Error("failed to write response", "err", err)
+	}
+}
+
 // @Summary  Schedule a full backup once per routine name.
 // @ID       scheduleFullBackup
 // @Tags     Backup
 // @Param    name path string true "Backup routine name"
 // @Param    delay query int false "Delay interval in milliseconds"
+// @Param    override query bool false "Run regardless of the routine backup windows"
 // @Router   /v1/backups/schedule/{name} [post]
 // @Success  202
 // @Response 400 {string} string
@@ -185,7 +544,16 @@ func (ws *HTTPServer) scheduleFullBackup(w http.ResponseWriter, r *http.Request)
 		http.Error(w, "nonpositive delay query parameter", http.StatusBadRequest)
 		return
 	}
-	fullBackupJobDetail := service.NewAdHocFullBackupJobForRoutine(routineName)
+	var override bool
+	if overrideParameter := r.URL.Query().Get("override"); overrideParameter != "" {
+		var err error
+		override, err = strconv.ParseBool(overrideParameter)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	fullBackupJobDetail := service.NewAdHocFullBackupJobForRoutine(routineName, override)
 	if fullBackupJobDetail == nil {
 		http.Error(w, "unknown routine name", http.StatusNotFound)
 		return
@@ -198,3 +566,168 @@ func (ws *HTTPServer) scheduleFullBackup(w http.ResponseWriter, r *http.Request)
 	}
 	w.WriteHeader(http.StatusAccepted)
 }
+
+// @Summary  Analyze the continuity of the incremental backup chains of the routine.
+// @ID       analyzeChains
+// @Tags     Backup
+// @Produce  json
+// @Param    routine path string true "Backup routine name"
+// @Router   /v1/backups/chains/{routine} [get]
+// @Success  200 {object} model.ChainReport "Gaps and overlaps in the incremental backup chains"
+// @Response 400 {string} string
+// @Failure  404 {string} string
+func (ws *HTTPServer) analyzeChains(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	routineName := r.PathValue("routine")
+	if routineName == "" {
+		http.Error(w, "routine name required", http.StatusBadRequest)
+		return
+	}
+	backend, found := ws.backupBackends.Get(routineName)
+	if !found {
+		http.Error(w, "routine name not found: "+routineName, http.StatusNotFound)
+		return
+	}
+	report, err := service.AnalyzeChains(backend, routineName)
+	if err != nil {
+		http.Error(w, "failed to analyze backup chains: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	response, err := json.Marshal(report)
+	if err != nil {
+		http.Error(w, "failed to parse chain report", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(response)
+	if err != nil {
+		slog.Error("failed to write response", "err", err)
+	}
+}
+
+// @Summary  Trigger an asynchronous verification of the routine backups against their manifests.
+// @ID       verifyBackups
+// @Tags     Backup
+// @Param    routine path string true "Backup routine name"
+// @Param    timestamp query int false "Verify only the backups created at the given time (epoch millis)"
+// @Param    key query string false "Verify only the backup with the given key"
+// @Router   /v1/backups/verify/{routine} [post]
+// @Success  202 {int64} int64 "Verification job id"
+// @Response 400 {string} string
+// @Failure  404 {string} string
+func (ws *HTTPServer) verifyBackups(w http.ResponseWriter, r *http.Request) {
+	routineName := r.PathValue("routine")
+	if routineName == "" {
+		http.Error(w, "routine name required", http.StatusBadRequest)
+		return
+	}
+	backend, found := ws.backupBackends.Get(routineName)
+	if !found {
+		http.Error(w, "routine name not found: "+routineName, http.StatusNotFound)
+		return
+	}
+	filter := service.VerificationFilter{}
+	if timestampParameter := r.URL.Query().Get("timestamp"); timestampParameter != "" {
+		timestamp, err := strconv.ParseInt(timestampParameter, 10, 64)
+		if err != nil {
+			http.Error(w, "invalid timestamp: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		filter.Timestamp = &timestamp
+	}
+	if key := r.URL.Query().Get("key"); key != "" {
+		filter.Key = &key
+	}
+
+	jobID := service.StartVerification(backend, routineName, filter)
+	slog.Info("Verify backups", "jobID", jobID, "routine", routineName)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	_, _ = fmt.Fprint(w, jobID)
+}
+
+// @Summary  Get the report of the latest verification of the routine backups.
+// @ID       getVerificationReport
+// @Tags     Backup
+// @Produce  json
+// @Param    routine path string true "Backup routine name"
+// @Router   /v1/backups/verify/{routine} [get]
+// @Success  200 {object} model.VerificationReport "Verification report"
+// @Failure  404 {string} string
+func (ws *HTTPServer) getVerificationReport(w http.ResponseWriter, r *http.Request) {
+	routineName := r.PathValue("routine")
+	report := service.LastVerificationReport(routineName)
+	if report == nil {
+		http.Error(w, "backups of the routine have not been verified yet: "+routineName, http.StatusNotFound)
+		return
+	}
+	writeVerificationReport(w, report)
+}
+
+// @Summary  Get the report of a verification job.
+// @ID       getVerificationStatus
+// @Tags     Backup
+// @Produce  json
+// @Param    jobId path int true "Verification job id"
+// @Router   /v1/backups/verify/status/{jobId} [get]
+// @Success  200 {object} model.VerificationReport "Verification report"
+// @Response 400 {string} string
+// @Failure  404 {string} string
+func (ws *HTTPServer) getVerificationStatus(w http.ResponseWriter, r *http.Request) {
+	jobID, err := strconv.Atoi(r.PathValue("jobId"))
+	if err != nil {
+		http.Error(w, "invalid job id", http.StatusBadRequest)
+		return
+	}
+	report, err := service.VerificationStatus(jobID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	writeVerificationReport(w, report)
+}
+
+func writeVerificationReport(w http.ResponseWriter, report *model.VerificationReport) {
+	response, err := json.Marshal(report)
+	if err != nil {
+		http.Error(w, "failed to parse verification report", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(response)
+	if err != nil {
+		slog.Error("failed to write response", "err", err)
+	}
+}
+
+// @Summary  Get the backup and restore jobs in the job queue.
+// @ID       getJobQueue
+// @Tags     Backup
+// @Produce  json
+// @Router   /v1/jobs/queue [get]
+// @Success  200 {array} model.QueuedJob "Running jobs, followed by the waiting ones in the order they will run"
+func (ws *HTTPServer) getJobQueue(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	response, err := json.Marshal(service.QueuedJobs())
+	if err != nil {
+		http.Error(w, "failed to parse job queue", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(response)
+	if err != nil {
+		slog.Error("failed to write response", "err", err)
+	}
+}
@@ -3,6 +3,7 @@ package server
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/aerospike/backup/pkg/model"
@@ -51,6 +52,8 @@ func (ws *HTTPServer) updateConfig(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "invalid configuration: "+err.Error(), http.StatusBadRequest)
 		return
 	}
+	ws.configLock.Lock()
+	defer ws.configLock.Unlock()
 	ws.config = &newConfig
 	err = ConfigurationManager.WriteConfiguration(&newConfig)
 	if err != nil {
@@ -61,6 +64,8 @@ func (ws *HTTPServer) updateConfig(w http.ResponseWriter, r *http.Request) {
 }
 
 // applyConfig
+// The added and changed storages and the signing keys are checked first, and the
+// configuration is not applied if any of the checks fails.
 // @Summary     Applies the configuration for the service.
 // @ID          applyConfig
 // @Tags        Configuration
@@ -69,10 +74,22 @@ func (ws *HTTPServer) updateConfig(w http.ResponseWriter, r *http.Request) {
 // @Success     200
 // @Failure     400 {string} string
 func (ws *HTTPServer) applyConfig(w http.ResponseWriter, _ *http.Request) {
-	err := service.ApplyNewConfig(ws.scheduler, ws.config, ws.backupBackends)
+	ws.configLock.Lock()
+	defer ws.configLock.Unlock()
+	err := service.CheckChangedStorages(ws.appliedStorage, ws.config.Storage)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	err = service.ApplyNewConfig(ws.scheduler, ws.config, ws.backupBackends)
+	if errors.Is(err, service.ErrSigningKeys) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	ws.appliedStorage = service.SnapshotStorages(ws.config.Storage)
 	w.WriteHeader(http.StatusOK)
 }
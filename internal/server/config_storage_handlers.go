@@ -168,3 +168,43 @@ func (ws *HTTPServer) deleteStorage(w http.ResponseWriter, r *http.Request) {
 	}
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// testStorage checks the connectivity and permissions of a storage by writing a temporary
+// file to it, reading it back, listing it and deleting it.
+// @Summary     Checks the connectivity and permissions of a storage.
+// @ID	        testStorage
+// @Tags        Configuration
+// @Router      /v1/config/storage/{name}/test [post]
+// @Param       name path string true "Backup storage name"
+// @Produce     json
+// @Success     200 {object} model.StorageCheck "The result of each step of the check"
+// @Response    400 {string} string
+// @Failure     404 {string} string "The specified storage could not be found"
+func (ws *HTTPServer) testStorage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	storageName := r.PathValue("name")
+	if storageName == "" {
+		http.Error(w, storageNameNotSpecifiedMsg, http.StatusBadRequest)
+		return
+	}
+	storage, ok := ws.config.Storage[storageName]
+	if !ok {
+		http.Error(w, fmt.Sprintf("Storage %s could not be found", storageName), http.StatusNotFound)
+		return
+	}
+	check := service.CheckStorage(storage)
+	jsonResponse, err := json.Marshal(check)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(jsonResponse)
+	if err != nil {
+		slog.Error("failed to write response", "err", err)
+	}
+}
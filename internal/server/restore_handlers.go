@@ -9,6 +9,7 @@ import (
 	"strconv"
 
 	"github.com/aerospike/backup/pkg/model"
+	"github.com/aerospike/backup/pkg/service"
 )
 
 // @Summary     Trigger an asynchronous full restore operation.
@@ -210,3 +211,44 @@ func (ws *HTTPServer) retrieveConfig(w http.ResponseWriter, r *http.Request) {
 		slog.Error("failed to write response", "err", err)
 	}
 }
+
+// @Summary     Get the restore drill history of the routine.
+// @ID          getRestoreDrills
+// @Tags        Restore
+// @Produce     json
+// @Param       name path string true "Backup routine name"
+// @Router      /v1/restore/drills/{name} [get]
+// @Success     200 {array} model.RestoreDrillResult "Restore drill results, the latest first"
+// @Failure     404 {string} string
+func (ws *HTTPServer) getRestoreDrills(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	name := r.PathValue("name")
+	routine, found := ws.config.BackupRoutines[name]
+	if !found {
+		http.Error(w, "routine name not found: "+name, http.StatusNotFound)
+		return
+	}
+	if routine.RestoreDrill == nil {
+		http.Error(w, "restore drill is not configured for routine "+name, http.StatusNotFound)
+		return
+	}
+	history := service.RestoreDrillHistory(name)
+	if history == nil {
+		history = []model.RestoreDrillResult{}
+	}
+	response, err := json.Marshal(history)
+	if err != nil {
+		http.Error(w, "failed to parse restore drill history", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(response)
+	if err != nil {
+		slog.Error("failed to write response", "err", err)
+	}
+}
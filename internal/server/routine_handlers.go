@@ -0,0 +1,95 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/aerospike/backup/pkg/service"
+	"github.com/reugn/go-quartz/quartz"
+)
+
+// @Summary  Pause the scheduled backups of the routine.
+// @ID       pauseRoutine
+// @Tags     Backup
+// @Param    name path string true "Backup routine name"
+// @Router   /v1/routines/{name}/pause [post]
+// @Success  204
+// @Response 400 {string} string
+// @Failure  404 {string} string
+func (ws *HTTPServer) pauseRoutine(w http.ResponseWriter, r *http.Request) {
+	ws.updateRoutineSchedule(w, r, service.PauseRoutine)
+}
+
+// @Summary  Resume the scheduled backups of the paused routine.
+// @ID       resumeRoutine
+// @Tags     Backup
+// @Param    name path string true "Backup routine name"
+// @Router   /v1/routines/{name}/resume [post]
+// @Success  204
+// @Response 400 {string} string
+// @Failure  404 {string} string
+func (ws *HTTPServer) resumeRoutine(w http.ResponseWriter, r *http.Request) {
+	ws.updateRoutineSchedule(w, r, service.ResumeRoutine)
+}
+
+func (ws *HTTPServer) updateRoutineSchedule(w http.ResponseWriter, r *http.Request,
+	update func(scheduler quartz.Scheduler, routineName string) error) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	routineName := r.PathValue("name")
+	if routineName == "" {
+		http.Error(w, routineNameNotSpecifiedMsg, http.StatusBadRequest)
+		return
+	}
+	err := update(ws.scheduler, routineName)
+	switch {
+	case errors.Is(err, service.ErrRoutineNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// @Summary  Get the scheduling status of the routine.
+// @ID       getRoutineStatus
+// @Tags     Backup
+// @Produce  json
+// @Param    name path string true "Backup routine name"
+// @Router   /v1/routines/{name}/status [get]
+// @Success  200 {object} model.RoutineStatus "Routine status"
+// @Response 400 {string} string
+// @Failure  404 {string} string
+func (ws *HTTPServer) getRoutineStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	routineName := r.PathValue("name")
+	if routineName == "" {
+		http.Error(w, routineNameNotSpecifiedMsg, http.StatusBadRequest)
+		return
+	}
+	status, err := service.GetRoutineStatus(ws.scheduler, routineName)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	response, err := json.Marshal(status)
+	if err != nil {
+		http.Error(w, "failed to parse routine status", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(response)
+	if err != nil {
+		slog.Error("failed to write response", "err", err)
+	}
+}
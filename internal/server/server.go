@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/netip"
 	"strings"
+	"sync"
 
 	"github.com/aerospike/backup/pkg/model"
 	"github.com/aerospike/backup/pkg/service"
@@ -83,11 +84,15 @@ type HTTPServer struct {
 	scheduler      quartz.Scheduler
 	restoreService service.RestoreService
 	backupBackends service.BackendsHolder
+	// configLock serializes the configuration updates and applies
+	configLock sync.Mutex
+	// the storages of the applied configuration, to check the changed ones on apply
+	appliedStorage map[string]model.Storage
 }
 
 // NewHTTPServer returns a new instance of HTTPServer.
 func NewHTTPServer(backends service.BackendsHolder, config *model.Config,
-	scheduler quartz.Scheduler) *HTTPServer {
+	scheduler quartz.Scheduler) (*HTTPServer, error) {
 	serverConfig := config.ServiceConfig.HTTPServer
 	addr := fmt.Sprintf("%s:%d", serverConfig.GetAddressOrDefault(), serverConfig.GetPortOrDefault())
 
@@ -95,6 +100,10 @@ func NewHTTPServer(backends service.BackendsHolder, config *model.Config,
 		rate.Limit(serverConfig.GetRateOrDefault().GetTpsOrDefault()),
 		serverConfig.GetRateOrDefault().GetSizeOrDefault(),
 	)
+	restoreService, err := service.NewRestoreMemory(backends, config)
+	if err != nil {
+		return nil, err
+	}
 	return &HTTPServer{
 		config: config,
 		server: &http.Server{
@@ -103,9 +112,10 @@ func NewHTTPServer(backends service.BackendsHolder, config *model.Config,
 		rateLimiter:    rateLimiter,
 		whiteList:      newIPWhiteList(serverConfig.GetRateOrDefault().GetWhiteListOrDefault()),
 		scheduler:      scheduler,
-		restoreService: service.NewRestoreMemory(backends, config),
+		restoreService: restoreService,
 		backupBackends: backends,
-	}
+		appliedStorage: service.SnapshotStorages(config.Storage),
+	}, nil
 }
 
 func (ws *HTTPServer) rateLimiterMiddleware(next http.Handler) http.Handler {
@@ -148,6 +158,7 @@ func (ws *HTTPServer) Start() {
 
 	// storage config routes
 	mux.HandleFunc(ws.api("/config/storage/{name}"), ws.configStorageActionHandler)
+	mux.HandleFunc(ws.api("/config/storage/{name}/test"), ws.testStorage)
 	mux.HandleFunc(ws.api("/config/storage"), ws.readAllStorage)
 
 	// policy config routes
@@ -189,15 +200,50 @@ func (ws *HTTPServer) Start() {
 	// Return backed up Aerospike configuration
 	mux.HandleFunc(ws.api("/retrieve/configuration/{name}/{timestamp}"), ws.retrieveConfig)
 
+	// Restore drill history
+	mux.HandleFunc(ws.api("/restore/drills/{name}"), ws.getRestoreDrills)
+
 	// Read available backups
 	mux.HandleFunc(ws.api("/backups/full/{name}"), ws.getFullBackupsForRoutine)
 	mux.HandleFunc(ws.api("/backups/full"), ws.getAllFullBackups)
 	mux.HandleFunc(ws.api("/backups/incremental/{name}"), ws.getIncrementalBackupsForRoutine)
 	mux.HandleFunc(ws.api("/backups/incremental"), ws.getAllIncrementalBackups)
 
+	// Read and delete backups
+	mux.HandleFunc(ws.api("/backups/full/{name}/{timestamp}"), ws.fullBackupActionHandler)
+	mux.HandleFunc(ws.api("/backups/incremental/{name}/{timestamp}"), ws.incrementalBackupActionHandler)
+
+	// Place and release backup holds
+	mux.HandleFunc(ws.api("/backups/full/{name}/{timestamp}/hold"), ws.fullBackupHoldActionHandler)
+	mux.HandleFunc(ws.api("/backups/incremental/{name}/{timestamp}/hold"), ws.incrementalBackupHoldActionHandler)
+
+	// Preview the backups pruned by the retention policy
+	mux.HandleFunc(ws.api("/backups/prune-preview/{routine}"), ws.prunePreview)
+
+	// Garbage collector report
+	mux.HandleFunc(ws.api("/backups/garbage"), ws.garbageActionHandler)
+
+	// Incremental backup chain analysis
+	mux.HandleFunc(ws.api("/backups/chains/{routine}"), ws.analyzeChains)
+
+	// Backup verification
+	mux.HandleFunc(ws.api("/backups/verify/{routine}"), ws.verificationActionHandler)
+	mux.HandleFunc(ws.api("/backups/verify/status/{jobId}"), ws.getVerificationStatus)
+
+	// Storage usage
+	mux.HandleFunc(ws.api("/storage/{name}/usage"), ws.getStorageUsage)
+
 	// Schedules a full backup operation
 	mux.HandleFunc(ws.api("/backups/schedule/{name}"), ws.scheduleFullBackup)
 
+	// Pause and resume the scheduled backups of a routine
+	mux.HandleFunc(ws.api("/routines/{name}/pause"), ws.pauseRoutine)
+	mux.HandleFunc(ws.api("/routines/{name}/resume"), ws.resumeRoutine)
+	mux.HandleFunc(ws.api("/routines/{name}/status"), ws.getRoutineStatus)
+
+	// Backup and restore job queue
+	mux.HandleFunc(ws.api("/jobs/queue"), ws.getJobQueue)
+
 	ws.server.Handler = ws.rateLimiterMiddleware(mux)
 	err := ws.server.ListenAndServe()
 	if err != nil && strings.Contains(err.Error(), "Server closed") {
@@ -301,3 +347,69 @@ func (ws *HTTPServer) configRoutineActionHandler(w http.ResponseWriter, r *http.
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 	}
 }
+
+func (ws *HTTPServer) fullBackupActionHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		ws.getFullBackup(w, r)
+	case http.MethodDelete:
+		ws.deleteFullBackup(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (ws *HTTPServer) incrementalBackupActionHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		ws.getIncrementalBackup(w, r)
+	case http.MethodDelete:
+		ws.deleteIncrementalBackup(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (ws *HTTPServer) fullBackupHoldActionHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		ws.placeFullBackupHold(w, r)
+	case http.MethodDelete:
+		ws.releaseFullBackupHold(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (ws *HTTPServer) incrementalBackupHoldActionHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		ws.placeIncrementalBackupHold(w, r)
+	case http.MethodDelete:
+		ws.releaseIncrementalBackupHold(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (ws *HTTPServer) garbageActionHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		ws.getGarbageReport(w, r)
+	case http.MethodPost:
+		ws.collectGarbage(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (ws *HTTPServer) verificationActionHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		ws.getVerificationReport(w, r)
+	case http.MethodPost:
+		ws.verifyBackups(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,51 @@
+package server
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/aerospike/backup/pkg/service"
+)
+
+// @Summary  Get the estimated usage of a storage.
+// @ID       getStorageUsage
+// @Tags     Backup
+// @Produce  json
+// @Param    name path string true "Backup storage name"
+// @Router   /v1/storage/{name}/usage [get]
+// @Success  200 {object} model.StorageUsage "Estimated storage usage"
+// @Response 400 {string} string
+// @Failure  404 {string} string
+func (ws *HTTPServer) getStorageUsage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	storageName := r.PathValue("name")
+	if storageName == "" {
+		http.Error(w, storageNameNotSpecifiedMsg, http.StatusBadRequest)
+		return
+	}
+	if _, found := ws.config.Storage[storageName]; !found {
+		http.Error(w, "storage not found: "+storageName, http.StatusNotFound)
+		return
+	}
+	usage, err := service.StorageUsage(ws.config, ws.backupBackends, storageName)
+	if err != nil {
+		http.Error(w, "failed to estimate storage usage: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	response, err := json.Marshal(usage)
+	if err != nil {
+		http.Error(w, "failed to parse storage usage", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(response)
+	if err != nil {
+		slog.Error("failed to write response", "err", err)
+	}
+}
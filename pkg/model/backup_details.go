@@ -11,6 +11,13 @@ type BackupDetails struct {
 	BackupMetadata
 	// The path to the backup files.
 	Key *string `yaml:"key,omitempty" json:"key,omitempty" example:"storage/daily/backup/1707915600000/source-ns1"`
+	// The name of the storage the backup is located in.
+	Storage *string `yaml:"storage,omitempty" json:"storage,omitempty" example:"local"`
+	// The checksums of the backup files, if recorded.
+	Manifest *BackupManifest `yaml:"manifest,omitempty" json:"manifest,omitempty"`
+	// Whether the backup signature is valid, if the backup signing is configured.
+	// False for the unsigned backups.
+	SignatureValid *bool `yaml:"signature-valid,omitempty" json:"signature-valid,omitempty"`
 }
 
 // String satisfies the fmt.Stringer interface.
@@ -42,4 +49,27 @@ type BackupMetadata struct {
 	SecondaryIndexCount uint64 `yaml:"secondary-index-count,omitempty" json:"secondary-index-count,omitempty" format:"int64" example:"5"`
 	// The number of UDF files backed up.
 	UDFCount uint64 `yaml:"udf-count,omitempty" json:"udf-count,omitempty" format:"int64" example:"2"`
+	// The legal hold placed on the backup, if any.
+	Hold *BackupHold `yaml:"hold,omitempty" json:"hold,omitempty"`
+	// The holds released from the backup, the oldest first.
+	HoldHistory []BackupHold `yaml:"hold-history,omitempty" json:"hold-history,omitempty"`
+	// The status of the replication to the secondary storages, if any.
+	Replication []ReplicationStatus `yaml:"replication,omitempty" json:"replication,omitempty"`
+	// The time the backup objects are locked until by the storage, if any.
+	LockedUntil *time.Time `yaml:"locked-until,omitempty" json:"locked-until,omitempty" example:"2023-04-20T14:50:00Z"`
+	// The ID of the key the backup is signed with, if signed.
+	KeyID string `yaml:"key-id,omitempty" json:"key-id,omitempty" example:"2024-01"`
+	// The base64 encoded Ed25519 signature of the backup metadata and manifest, if signed.
+	// The hold, the replication status and the lock time are not signed, as they change over time.
+	Signature string `yaml:"signature,omitempty" json:"signature,omitempty"`
+}
+
+// IsHeld returns true if the backup is on hold.
+func (metadata BackupMetadata) IsHeld() bool {
+	return metadata.Hold != nil
+}
+
+// IsLocked returns true if the backup objects are locked at the given time.
+func (metadata BackupMetadata) IsLocked(now time.Time) bool {
+	return metadata.LockedUntil != nil && metadata.LockedUntil.After(now)
 }
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+// BackupHold is a legal hold placed on a backup.
+// A backup on hold is never deleted or overwritten until the hold is released.
+// @Description BackupHold is a legal hold placed on a backup.
+type BackupHold struct {
+	// The reason the backup is held.
+	Reason string `yaml:"reason" json:"reason" example:"Audit 2024-Q1"`
+	// The person or system that placed the hold.
+	Actor string `yaml:"actor" json:"actor" example:"jane.doe"`
+	// The time the hold was placed in the ISO 8601 format.
+	Created time.Time `yaml:"created,omitempty" json:"created,omitempty" example:"2023-03-20T14:50:00Z"`
+	// The person or system that released the hold, for the released holds.
+	ReleasedBy string `yaml:"released-by,omitempty" json:"released-by,omitempty" example:"john.doe"`
+	// The time the hold was released in the ISO 8601 format, for the released holds.
+	Released *time.Time `yaml:"released,omitempty" json:"released,omitempty" example:"2023-04-20T14:50:00Z"`
+}
+
+// Validate validates the backup hold.
+func (h *BackupHold) Validate() error {
+	if h.Reason == "" {
+		return errors.New("hold reason is not specified")
+	}
+	if h.Actor == "" {
+		return errors.New("hold actor is not specified")
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package model
+
+// ManifestAlgorithm is the checksum algorithm of the backup manifests.
+const ManifestAlgorithm = "sha256"
+
+// BackupManifest contains the checksums of the backup files, to detect
+// corruption or tampering of a backup.
+// @Description BackupManifest contains the checksums of the backup files.
+type BackupManifest struct {
+	// The checksum algorithm.
+	Algorithm string `yaml:"algorithm" json:"algorithm" example:"sha256"`
+	// The backup files with their checksums.
+	Files []ManifestFile `yaml:"files" json:"files"`
+}
+
+// ManifestFile represents a backup file in the manifest.
+// @Description ManifestFile represents a backup file in the manifest.
+//
+//nolint:lll
+type ManifestFile struct {
+	// The path of the file relative to the backup folder.
+	Path string `yaml:"path" json:"path" example:"data/source-ns1/source-ns1_1.asb"`
+	// The size of the file in bytes.
+	Size int64 `yaml:"size" json:"size" format:"int64" example:"2000"`
+	// The hex-encoded checksum of the file.
+	Checksum string `yaml:"checksum" json:"checksum" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+}
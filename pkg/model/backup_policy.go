@@ -64,6 +64,12 @@ type BackupPolicy struct {
 	// When true, the backup contains only records that last modified before backup started.
 	// When false (default), records updated during backup might be included in the backup, but it's not guaranteed.
 	Sealed *bool `yaml:"sealed,omitempty" json:"sealed,omitempty"`
+	// Retention rules for pruning old full backups and their incremental backups (optional).
+	// Applied after each successful full backup.
+	Retention *RetentionPolicy `yaml:"retention,omitempty" json:"retention,omitempty"`
+	// Tiering rule for moving aging backups to a colder storage (optional).
+	// Applied after each successful full backup.
+	Tiering *TieringPolicy `yaml:"tiering,omitempty" json:"tiering,omitempty"`
 }
 
 // GetMaxRetriesOrDefault returns the value of the MaxRetries property.
@@ -113,6 +119,8 @@ func (p *BackupPolicy) CopySMDDisabled() *BackupPolicy {
 		RecordsPerSecond: p.RecordsPerSecond,
 		FileLimit:        p.FileLimit,
 		Sealed:           p.Sealed,
+		Retention:        p.Retention,
+		Tiering:          p.Tiering,
 	}
 }
 
@@ -168,5 +176,17 @@ func (p *BackupPolicy) Validate() error {
 	if err := p.CompressionPolicy.Validate(); err != nil {
 		return err
 	}
+	if p.Retention != nil && p.RemoveFiles.RemoveFullBackup() {
+		return fmt.Errorf("retention policy cannot be used with RemoveFiles: %s", RemoveAll)
+	}
+	if err := p.Retention.Validate(); err != nil {
+		return err
+	}
+	if p.Tiering != nil && p.RemoveFiles.RemoveFullBackup() {
+		return fmt.Errorf("tiering policy cannot be used with RemoveFiles: %s", RemoveAll)
+	}
+	if err := p.Tiering.Validate(); err != nil {
+		return err
+	}
 	return nil
 }
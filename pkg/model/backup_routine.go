@@ -1,7 +1,10 @@
 package model
 
 import (
+	"errors"
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/reugn/go-quartz/quartz"
 )
@@ -35,6 +38,19 @@ type BackupRoutine struct {
 	// or records after a specific digest within a single partition.
 	// Default number of partitions to back up: 0 to 4095: all partitions.
 	PartitionList *string `yaml:"partition-list,omitempty" json:"partition-list,omitempty" example:"0-1000"`
+	// The names of the secondary storages to copy the completed backups to (optional).
+	ReplicateTo []string `yaml:"replicate-to,omitempty" json:"replicate-to,omitempty" example:"s3-dr"`
+	// The interval for the verification of the routine backups against their manifests
+	// as a cron expression string (optional).
+	VerifyCron string `yaml:"verify-cron,omitempty" json:"verify-cron,omitempty" example:"0 0 3 * * *"`
+	// The scheduled test restore of the routine backups (optional).
+	RestoreDrill *RestoreDrill `yaml:"restore-drill,omitempty" json:"restore-drill,omitempty"`
+	// The time windows the backups are allowed to run in (optional, any time if not set).
+	// The backups due outside the windows, including the retries, are deferred until a window opens.
+	AllowedWindows []*TimeWindow `yaml:"allowed-windows,omitempty" json:"allowed-windows,omitempty"`
+	// The time windows the backups are not allowed to run in (optional).
+	// Blackout periods take precedence over the allowed windows.
+	Blackout []*TimeWindow `yaml:"blackout,omitempty" json:"blackout,omitempty"`
 }
 
 // Validate validates the backup routine configuration.
@@ -58,6 +74,27 @@ func (r *BackupRoutine) Validate(c *Config) error {
 		return notFoundValidationError("storage", r.Storage)
 	}
 
+	for i, storage := range r.ReplicateTo {
+		if _, exists := c.Storage[storage]; !exists {
+			return notFoundValidationError("replication storage", storage)
+		}
+		if storage == r.Storage {
+			return fmt.Errorf("replication storage %s should differ from the routine storage", storage)
+		}
+		if slices.Contains(r.ReplicateTo[:i], storage) {
+			return fmt.Errorf("replication storage %s is duplicated", storage)
+		}
+	}
+
+	if tiering := c.BackupPolicies[r.BackupPolicy].Tiering; tiering != nil && tiering.Storage != nil {
+		if _, exists := c.Storage[*tiering.Storage]; !exists {
+			return notFoundValidationError("tiering storage", *tiering.Storage)
+		}
+		if *tiering.Storage == r.Storage {
+			return fmt.Errorf("tiering storage %s should differ from the routine storage", *tiering.Storage)
+		}
+	}
+
 	if err := quartz.ValidateCronExpression(r.IntervalCron); err != nil {
 		return fmt.Errorf("backup interval string '%s' invalid: %v", r.IntervalCron, err)
 	}
@@ -66,6 +103,29 @@ func (r *BackupRoutine) Validate(c *Config) error {
 			return fmt.Errorf("incremental backup interval string '%s' invalid: %v", r.IntervalCron, err)
 		}
 	}
+	if r.VerifyCron != "" {
+		if err := quartz.ValidateCronExpression(r.VerifyCron); err != nil {
+			return fmt.Errorf("verification interval string '%s' invalid: %v", r.VerifyCron, err)
+		}
+	}
+	if r.RestoreDrill != nil {
+		if err := r.RestoreDrill.Validate(r, c); err != nil {
+			return err
+		}
+	}
+	for _, window := range r.AllowedWindows {
+		if err := window.Validate(); err != nil {
+			return fmt.Errorf("allowed backup window invalid: %w", err)
+		}
+	}
+	for _, window := range r.Blackout {
+		if err := window.Validate(); err != nil {
+			return fmt.Errorf("blackout window invalid: %w", err)
+		}
+	}
+	if len(r.AllowedWindows)+len(r.Blackout) > 0 && r.NextAllowedTime(time.Now()).IsZero() {
+		return errors.New("backup windows do not allow backups at any time")
+	}
 	for _, rack := range r.PreferRacks {
 		if rack < 0 {
 			return fmt.Errorf("rack id %d invalid, should be positive number", rack)
@@ -7,12 +7,19 @@ type BackupServiceConfig struct {
 	HTTPServer *HTTPServerConfig `yaml:"http,omitempty" json:"http,omitempty"`
 	// Logger is the backup service logger configuration.
 	Logger *LoggerConfig `yaml:"logger,omitempty" json:"logger,omitempty"`
+	// GarbageCollector is the configuration of the partial backup folders collector.
+	GarbageCollector *GarbageCollectorConfig `yaml:"garbage-collector,omitempty" json:"garbage-collector,omitempty"`
+	// Signing is the configuration of the backup signing, disabled if not set.
+	Signing *SigningConfig `yaml:"signing,omitempty" json:"signing,omitempty"`
+	// JobQueue is the configuration of the queue the backup and restore jobs run through.
+	JobQueue *JobQueueConfig `yaml:"job-queue,omitempty" json:"job-queue,omitempty"`
 }
 
 // NewBackupServiceConfigWithDefaultValues returns a new BackupServiceConfig with default values.
 func NewBackupServiceConfigWithDefaultValues() *BackupServiceConfig {
 	return &BackupServiceConfig{
-		HTTPServer: &HTTPServerConfig{},
-		Logger:     &LoggerConfig{},
+		HTTPServer:       &HTTPServerConfig{},
+		Logger:           &LoggerConfig{},
+		GarbageCollector: &GarbageCollectorConfig{},
 	}
 }
@@ -16,6 +16,11 @@ type BackupState struct {
 	LastIncrRun time.Time `yaml:"last-incr-run,omitempty" json:"last-incr-run,omitempty" example:"2023-12-15T12:00:00Z"`
 	// The number of successful full backups created for the routine.
 	Performed int `yaml:"performed,omitempty" json:"performed,omitempty" example:"5"`
+	// The time the scheduled backups of the routine were paused at, if they are paused.
+	PausedAt *time.Time `yaml:"paused-at,omitempty" json:"paused-at,omitempty" example:"2023-12-15T13:00:00Z"`
+	// The lower time bounds of the deleted empty incremental backups, by namespace.
+	// The next incremental backup of the namespace starts from this time.
+	EmptyIncrementalsFrom map[string]time.Time `yaml:"empty-incrementals-from,omitempty" json:"empty-incrementals-from,omitempty"`
 }
 
 // String satisfies the fmt.Stringer interface.
@@ -56,3 +61,59 @@ func (state *BackupState) LastRunEpoch() int64 {
 	defer state.Unlock()
 	return max(state.LastIncrRun.UnixNano(), state.LastFullRun.UnixNano())
 }
+
+// IsPaused returns true if the scheduled backups of the routine are paused.
+func (state *BackupState) IsPaused() bool {
+	state.Lock()
+	defer state.Unlock()
+	return state.PausedAt != nil
+}
+
+// SetPaused records the scheduled backups of the routine as paused at the given time,
+// or as resumed if the time is nil.
+func (state *BackupState) SetPaused(pausedAt *time.Time) {
+	state.Lock()
+	defer state.Unlock()
+	state.PausedAt = pausedAt
+}
+
+// GetPausedAt returns the time the scheduled backups of the routine were paused at,
+// or nil if they are not paused.
+func (state *BackupState) GetPausedAt() *time.Time {
+	state.Lock()
+	defer state.Unlock()
+	return state.PausedAt
+}
+
+// AddEmptyIncremental records a deleted empty incremental backup of the namespace with
+// the given lower time bound, unless an earlier one is already recorded.
+func (state *BackupState) AddEmptyIncremental(namespace string, from time.Time) {
+	state.Lock()
+	defer state.Unlock()
+	if state.EmptyIncrementalsFrom == nil {
+		state.EmptyIncrementalsFrom = make(map[string]time.Time)
+	}
+	if _, found := state.EmptyIncrementalsFrom[namespace]; !found {
+		state.EmptyIncrementalsFrom[namespace] = from
+	}
+}
+
+// TakeEmptyIncremental returns the lower time bound of the recorded empty incremental
+// backups of the namespace, or the given one if there are none, and forgets them.
+func (state *BackupState) TakeEmptyIncremental(namespace string, from time.Time) time.Time {
+	state.Lock()
+	defer state.Unlock()
+	if since, found := state.EmptyIncrementalsFrom[namespace]; found {
+		delete(state.EmptyIncrementalsFrom, namespace)
+		return since
+	}
+	return from
+}
+
+// ClearEmptyIncrementals forgets all the empty incremental backups, when a new
+// incremental chain starts.
+func (state *BackupState) ClearEmptyIncrementals() {
+	state.Lock()
+	defer state.Unlock()
+	state.EmptyIncrementalsFrom = nil
+}
@@ -0,0 +1,160 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// The reasons the backups of a routine are not allowed to run at some time.
+const (
+	// WindowReasonBlackout is the reason for the backups in a blackout period.
+	WindowReasonBlackout = "blackout"
+	// WindowReasonOutsideWindow is the reason for the backups outside the allowed windows.
+	WindowReasonOutsideWindow = "outside-window"
+)
+
+// maxWindowSearch is the period the next allowed backup time is looked for in,
+// the longest period the weekly windows repeat after.
+const maxWindowSearch = 8 * 24 * time.Hour
+
+// TimeWindow represents a daily time range, optionally limited to some days of the week.
+// A window ending before or at its start time spans midnight.
+// @Description TimeWindow represents a daily time range, optionally limited to some days of the week.
+//
+//nolint:lll
+type TimeWindow struct {
+	// The start time of the window in the HH:MM format.
+	Start string `yaml:"start" json:"start" example:"22:00" validate:"required"`
+	// The end time of the window in the HH:MM format.
+	End string `yaml:"end" json:"end" example:"06:00" validate:"required"`
+	// The days of the week the window starts on (optional, all days if not set).
+	Days []string `yaml:"days,omitempty" json:"days,omitempty" example:"Sat,Sun" enums:"Mon,Tue,Wed,Thu,Fri,Sat,Sun"`
+	// The IANA time zone of the window (optional, UTC if not set).
+	Timezone string `yaml:"timezone,omitempty" json:"timezone,omitempty" example:"Europe/Berlin"`
+}
+
+// parsedWindow is a TimeWindow with the values parsed.
+type parsedWindow struct {
+	start, end time.Duration // since midnight
+	days       []time.Weekday
+	location   *time.Location
+}
+
+// Validate validates the time window.
+func (w *TimeWindow) Validate() error {
+	_, err := w.parse()
+	return err
+}
+
+func (w *TimeWindow) parse() (*parsedWindow, error) {
+	start, err := parseTimeOfDay(w.Start)
+	if err != nil {
+		return nil, fmt.Errorf("window start '%s' invalid: %w", w.Start, err)
+	}
+	end, err := parseTimeOfDay(w.End)
+	if err != nil {
+		return nil, fmt.Errorf("window end '%s' invalid: %w", w.End, err)
+	}
+	location, err := time.LoadLocation(w.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("window timezone '%s' invalid: %w", w.Timezone, err)
+	}
+	days := make([]time.Weekday, 0, len(w.Days))
+	for _, day := range w.Days {
+		weekday := slices.IndexFunc(weekdays, func(name string) bool {
+			return strings.EqualFold(name, day)
+		})
+		if weekday < 0 {
+			return nil, fmt.Errorf("window day '%s' invalid, should be one of %v", day, weekdays)
+		}
+		days = append(days, time.Weekday(weekday))
+	}
+	return &parsedWindow{start: start, end: end, days: days, location: location}, nil
+}
+
+// weekdays are the names of the days of the week, indexed by time.Weekday.
+var weekdays = []string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"}
+
+func parseTimeOfDay(value string) (time.Duration, error) {
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(parsed.Hour())*time.Hour + time.Duration(parsed.Minute())*time.Minute, nil
+}
+
+// contains returns true if the given time is within the window.
+func (w *parsedWindow) contains(t time.Time) bool {
+	local := t.In(w.location)
+	// the wall clock time, regardless of the daylight saving time changes
+	timeOfDay := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute +
+		time.Duration(local.Second())*time.Second
+	if w.start < w.end {
+		return w.startsOn(local.Weekday()) && timeOfDay >= w.start && timeOfDay < w.end
+	}
+	// the window spans midnight
+	if timeOfDay >= w.start {
+		return w.startsOn(local.Weekday())
+	}
+	return timeOfDay < w.end && w.startsOn((local.Weekday()+6)%7)
+}
+
+func (w *parsedWindow) startsOn(day time.Weekday) bool {
+	return len(w.days) == 0 || slices.Contains(w.days, day)
+}
+
+// parseWindows parses the valid windows, the invalid ones are rejected by the validation.
+func parseWindows(windows []*TimeWindow) []*parsedWindow {
+	parsed := make([]*parsedWindow, 0, len(windows))
+	for _, window := range windows {
+		if p, err := window.parse(); err == nil {
+			parsed = append(parsed, p)
+		}
+	}
+	return parsed
+}
+
+// windowRestriction returns the reason the backups are not allowed at the given time,
+// or an empty string if they are allowed.
+func windowRestriction(allowed, blackout []*parsedWindow, t time.Time) string {
+	for _, window := range blackout {
+		if window.contains(t) {
+			return WindowReasonBlackout
+		}
+	}
+	if len(allowed) == 0 {
+		return ""
+	}
+	for _, window := range allowed {
+		if window.contains(t) {
+			return ""
+		}
+	}
+	return WindowReasonOutsideWindow
+}
+
+// WindowRestriction returns the reason the backups of the routine are not allowed
+// to run at the given time, or an empty string if they are allowed.
+// Blackout periods take precedence over the allowed windows.
+func (r *BackupRoutine) WindowRestriction(t time.Time) string {
+	if len(r.AllowedWindows) == 0 && len(r.Blackout) == 0 {
+		return ""
+	}
+	return windowRestriction(parseWindows(r.AllowedWindows), parseWindows(r.Blackout), t)
+}
+
+// NextAllowedTime returns the first minute after the given time the backups of the
+// routine are allowed to run at, or the zero time if there is none within a week.
+func (r *BackupRoutine) NextAllowedTime(t time.Time) time.Time {
+	allowed, blackout := parseWindows(r.AllowedWindows), parseWindows(r.Blackout)
+	next := t.Truncate(time.Minute)
+	for range int(maxWindowSearch / time.Minute) {
+		next = next.Add(time.Minute)
+		if windowRestriction(allowed, blackout, next) == "" {
+			return next
+		}
+	}
+	return time.Time{}
+}
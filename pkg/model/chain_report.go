@@ -0,0 +1,45 @@
+package model
+
+import "time"
+
+// The types of the incremental backup chain issues.
+const (
+	// ChainGap is an incremental backup starting after the end of the previous backup.
+	ChainGap = "gap"
+	// ChainOverlap is an incremental backup starting before the end of the previous backup.
+	ChainOverlap = "overlap"
+	// ChainMissingFull is an incremental backup without a preceding full backup.
+	ChainMissingFull = "missing-full-backup"
+)
+
+// ChainReport is the result of the continuity analysis of the incremental backup chains of a routine.
+// @Description ChainReport is the result of the continuity analysis of the incremental backup chains.
+type ChainReport struct {
+	// The backup routine name.
+	Routine string `yaml:"routine" json:"routine" example:"daily"`
+	// The time of the analysis in the ISO 8601 format.
+	Created time.Time `yaml:"created" json:"created" example:"2023-03-20T14:50:00Z"`
+	// The number of analyzed full backups, one per namespace.
+	FullBackupCount int `yaml:"full-backup-count" json:"full-backup-count" example:"2"`
+	// The number of analyzed incremental backups, one per namespace.
+	IncrementalBackupCount int `yaml:"incremental-backup-count" json:"incremental-backup-count" example:"10"`
+	// The continuity issues found.
+	Issues []ChainIssue `yaml:"issues" json:"issues"`
+}
+
+// ChainIssue is a continuity issue of an incremental backup chain.
+// @Description ChainIssue is a continuity issue of an incremental backup chain.
+type ChainIssue struct {
+	// The type of the issue.
+	Type string `yaml:"type" json:"type" enums:"gap,overlap,missing-full-backup"`
+	// The namespace of the backups.
+	Namespace string `yaml:"namespace" json:"namespace" example:"source-ns1"`
+	// The key of the incremental backup.
+	Key string `yaml:"key" json:"key" example:"storage/daily/incremental/1707915600000/data/source-ns1"`
+	// The creation time of the incremental backup in the ISO 8601 format.
+	Created time.Time `yaml:"created" json:"created" example:"2023-03-20T14:50:00Z"`
+	// The lower time bound of the incremental backup in the ISO 8601 format.
+	From time.Time `yaml:"from" json:"from" example:"2023-03-20T14:40:00Z"`
+	// The creation time of the previous backup of the chain in the ISO 8601 format, if any.
+	Previous *time.Time `yaml:"previous,omitempty" json:"previous,omitempty" example:"2023-03-20T14:30:00Z"`
+}
@@ -80,7 +80,19 @@ func (c *Config) Validate() error {
 		return err
 	}
 
-	if err := c.ServiceConfig.Logger.Validate(); err != nil { //nolint:revive
+	if err := c.ServiceConfig.Logger.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.ServiceConfig.GarbageCollector.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.ServiceConfig.Signing.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.ServiceConfig.JobQueue.Validate(); err != nil { //nolint:revive
 		return err
 	}
 
@@ -8,11 +8,21 @@ type backupPolicy struct {
 	sealed     bool
 }
 
+type retentionPolicy struct {
+	pruneIncrementals bool
+}
+
 // defaultConfig represents default configuration values.
 var defaultConfig = struct {
-	http         HTTPServerConfig
-	logger       LoggerConfig
-	backupPolicy backupPolicy
+	http             HTTPServerConfig
+	logger           LoggerConfig
+	backupPolicy     backupPolicy
+	retentionPolicy  retentionPolicy
+	garbageCollector GarbageCollectorConfig
+	storageQuota     StorageQuota
+	storage          Storage
+	signing          SigningConfig
+	jobQueue         JobQueueConfig
 }{
 	http: HTTPServerConfig{
 		Address: util.Ptr("0.0.0.0"),
@@ -33,4 +43,31 @@ var defaultConfig = struct {
 	backupPolicy: backupPolicy{
 		retryDelay: 60_000, // default retry delay is 1 minute
 	},
+	retentionPolicy: retentionPolicy{
+		pruneIncrementals: true,
+	},
+	garbageCollector: GarbageCollectorConfig{
+		Enabled:     util.Ptr(true),
+		Interval:    util.Ptr[int64](3_600_000),  // 1 hour
+		GracePeriod: util.Ptr[int64](86_400_000), // 1 day
+		Delete:      util.Ptr(false),
+	},
+	storageQuota: StorageQuota{
+		Action: util.Ptr(QuotaActionWarn),
+	},
+	storage: Storage{
+		SftpPort: util.Ptr(22),
+	},
+	signing: SigningConfig{
+		RequireSignature: util.Ptr(false),
+	},
+	jobQueue: JobQueueConfig{
+		MaxConcurrentJobs:           util.Ptr(2),
+		MaxConcurrentJobsPerCluster: util.Ptr(1),
+		Priorities: &JobPriorities{
+			Restore:     util.Ptr(3),
+			Incremental: util.Ptr(2),
+			Full:        util.Ptr(1),
+		},
+	},
 }
@@ -1,7 +1,9 @@
 package model
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/aws/smithy-go/ptr"
 )
@@ -89,3 +91,474 @@ func TestInvalidStorageReference(t *testing.T) {
 		t.Errorf("Expected error message '%s', but got '%s'", expectedError, err.Error())
 	}
 }
+
+func TestInvalidReplicationStorageReference(t *testing.T) {
+	config := validConfig()
+	config.BackupRoutines["routine1"].ReplicateTo = []string{"storage1"}
+
+	err := config.Validate()
+	if err == nil {
+		t.Fatalf("Expected validation error, but got none.")
+	}
+	expectedError := "backup routine 'routine1' validation error: replication storage storage1 should differ from the routine storage"
+	if err.Error() != expectedError {
+		t.Errorf("Expected error message '%s', but got '%s'", expectedError, err.Error())
+	}
+
+	config.BackupRoutines["routine1"].ReplicateTo = []string{"nonExistentStorage"}
+	expectedError = "backup routine 'routine1' validation error: replication storage 'nonExistentStorage' not found"
+	if err := config.Validate(); err == nil || err.Error() != expectedError {
+		t.Errorf("Expected error message '%s', but got '%v'", expectedError, err)
+	}
+}
+
+func TestInvalidTieringStorageReference(t *testing.T) {
+	config := validConfig()
+	config.BackupPolicies["policy1"].Tiering = &TieringPolicy{AfterDays: ptr.Int(14), Storage: ptr.String("storage1")}
+
+	err := config.Validate()
+	if err == nil {
+		t.Fatalf("Expected validation error, but got none.")
+	}
+	expectedError := "backup routine 'routine1' validation error: tiering storage storage1 should differ from the routine storage"
+	if err.Error() != expectedError {
+		t.Errorf("Expected error message '%s', but got '%s'", expectedError, err.Error())
+	}
+
+	config.BackupPolicies["policy1"].Tiering.Storage = ptr.String("storage2")
+	if err := config.Validate(); err != nil {
+		t.Errorf("Expected no validation error, but got: %v", err)
+	}
+}
+
+func TestInvalidVerifyCron(t *testing.T) {
+	config := validConfig()
+	config.BackupRoutines["routine1"].VerifyCron = "0 0 3 * * *"
+	if err := config.Validate(); err != nil {
+		t.Errorf("Expected no validation error, but got: %v", err)
+	}
+
+	config.BackupRoutines["routine1"].VerifyCron = "daily"
+	err := config.Validate()
+	if err == nil {
+		t.Fatalf("Expected validation error, but got none.")
+	}
+	expectedPrefix := "backup routine 'routine1' validation error: verification interval string 'daily' invalid"
+	if !strings.HasPrefix(err.Error(), expectedPrefix) {
+		t.Errorf("Expected error message '%s...', but got '%s'", expectedPrefix, err.Error())
+	}
+}
+
+func TestRestoreDrillValidation(t *testing.T) {
+	namespace := func(source, destination string) *RestoreNamespace {
+		return &RestoreNamespace{Source: ptr.String(source), Destination: ptr.String(destination)}
+	}
+	tests := []struct {
+		name    string
+		drill   RestoreDrill
+		wantErr string
+	}{
+		{name: "valid", drill: RestoreDrill{IntervalCron: "0 0 4 * * 1", DestinationCluster: "cluster2",
+			Namespace: namespace("ns1", "ns1")}},
+		{name: "same cluster, other namespace", drill: RestoreDrill{IntervalCron: "0 0 4 * * 1",
+			DestinationCluster: "cluster1", Namespace: namespace("ns1", "drill")}},
+		{name: "invalid cron", drill: RestoreDrill{IntervalCron: "weekly", DestinationCluster: "cluster2",
+			Namespace: namespace("ns1", "ns1")}, wantErr: "restore drill interval string 'weekly' invalid"},
+		{name: "unknown cluster", drill: RestoreDrill{IntervalCron: "0 0 4 * * 1", DestinationCluster: "scratch",
+			Namespace: namespace("ns1", "ns1")}, wantErr: "restore drill Aerospike cluster 'scratch' not found"},
+		{name: "no namespace", drill: RestoreDrill{IntervalCron: "0 0 4 * * 1", DestinationCluster: "cluster2"},
+			wantErr: "restore drill namespace is not specified"},
+		{name: "namespace not backed up", drill: RestoreDrill{IntervalCron: "0 0 4 * * 1",
+			DestinationCluster: "cluster2", Namespace: namespace("ns2", "ns2")},
+			wantErr: "restore drill namespace ns2 is not backed up by the routine"},
+		{name: "overwrite source", drill: RestoreDrill{IntervalCron: "0 0 4 * * 1", DestinationCluster: "cluster1",
+			Namespace: namespace("ns1", "ns1")}, wantErr: "restore drill would overwrite the backed up namespace"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := validConfig()
+			config.BackupRoutines["routine1"].RestoreDrill = &tt.drill
+			err := config.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no validation error, but got: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing '%s', but got '%v'", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestSigningConfigValidation(t *testing.T) {
+	keys := func() map[string]*SigningKey {
+		return map[string]*SigningKey{
+			"new": {PrivateKeyFile: ptr.String("new.pem")},
+			"old": {PublicKeyFile: ptr.String("old.pub")},
+		}
+	}
+	tests := []struct {
+		name    string
+		signing SigningConfig
+		wantErr string
+	}{
+		{name: "valid", signing: SigningConfig{KeyID: "new", Keys: keys()}},
+		{name: "no key ID", signing: SigningConfig{Keys: keys()},
+			wantErr: "empty signing key-id is not allowed"},
+		{name: "unknown key ID", signing: SigningConfig{KeyID: "next", Keys: keys()},
+			wantErr: "signing key 'next' not found"},
+		{name: "no private key", signing: SigningConfig{KeyID: "old", Keys: keys()},
+			wantErr: "private-key-file of the active signing key is not specified"},
+		{name: "no key file", signing: SigningConfig{KeyID: "new", Keys: map[string]*SigningKey{
+			"new": {PrivateKeyFile: ptr.String("new.pem")}, "old": {}}},
+			wantErr: "signing key old has no key file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := validConfig()
+			config.ServiceConfig.Signing = &tt.signing
+			err := config.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no validation error, but got: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing '%s', but got '%v'", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestBackupWindowValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		allowed  []*TimeWindow
+		blackout []*TimeWindow
+		wantErr  string
+	}{
+		{name: "valid", allowed: []*TimeWindow{{Start: "22:00", End: "06:00", Days: []string{"Sat", "sun"}}},
+			blackout: []*TimeWindow{{Start: "23:00", End: "23:30", Timezone: "Europe/Berlin"}}},
+		{name: "invalid start", allowed: []*TimeWindow{{Start: "24:00", End: "06:00"}},
+			wantErr: "allowed backup window invalid: window start '24:00' invalid"},
+		{name: "invalid end", blackout: []*TimeWindow{{Start: "22:00", End: "6"}},
+			wantErr: "blackout window invalid: window end '6' invalid"},
+		{name: "invalid day", allowed: []*TimeWindow{{Start: "22:00", End: "06:00", Days: []string{"Sunday"}}},
+			wantErr: "window day 'Sunday' invalid"},
+		{name: "invalid timezone", allowed: []*TimeWindow{{Start: "22:00", End: "06:00", Timezone: "Mars"}},
+			wantErr: "window timezone 'Mars' invalid"},
+		{name: "always blacked out", blackout: []*TimeWindow{{Start: "00:00", End: "00:00"}},
+			wantErr: "backup windows do not allow backups at any time"},
+		{name: "allowed window blacked out", allowed: []*TimeWindow{{Start: "01:00", End: "02:00"}},
+			blackout: []*TimeWindow{{Start: "00:00", End: "03:00"}},
+			wantErr:  "backup windows do not allow backups at any time"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := validConfig()
+			config.BackupRoutines["routine1"].AllowedWindows = tt.allowed
+			config.BackupRoutines["routine1"].Blackout = tt.blackout
+			err := config.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no validation error, but got: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing '%s', but got '%v'", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestBackupWindowRestriction(t *testing.T) {
+	routine := &BackupRoutine{
+		// the nights starting on weekends, and the Monday mornings
+		AllowedWindows: []*TimeWindow{
+			{Start: "22:00", End: "06:00", Days: []string{"Sat", "Sun"}},
+			{Start: "06:00", End: "08:00", Days: []string{"Mon"}},
+		},
+		Blackout: []*TimeWindow{{Start: "01:00", End: "02:00", Timezone: "Europe/Berlin"}},
+	}
+	// 2024-03-04 is Monday
+	at := func(value string) time.Time {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+	tests := []struct {
+		time string
+		want string
+	}{
+		{time: "2024-03-02T21:59:00Z", want: WindowReasonOutsideWindow},
+		{time: "2024-03-02T22:00:00Z"},
+		{time: "2024-03-03T05:00:00Z"},
+		{time: "2024-03-04T05:00:00Z"}, // the Sunday night window
+		{time: "2024-03-04T07:59:59Z"}, // the Monday window
+		{time: "2024-03-04T08:00:00Z", want: WindowReasonOutsideWindow},
+		{time: "2024-03-04T22:00:00Z", want: WindowReasonOutsideWindow},
+		{time: "2024-03-03T00:30:00Z", want: WindowReasonBlackout}, // 01:30 in Berlin
+		{time: "2024-03-05T00:30:00Z", want: WindowReasonBlackout},
+	}
+	for _, tt := range tests {
+		if got := routine.WindowRestriction(at(tt.time)); got != tt.want {
+			t.Errorf("WindowRestriction(%s) = '%s', want '%s'", tt.time, got, tt.want)
+		}
+	}
+
+	next := routine.NextAllowedTime(at("2024-03-04T08:00:00Z"))
+	if want := at("2024-03-09T22:00:00Z"); !next.Equal(want) {
+		t.Errorf("NextAllowedTime() = %v, want %v", next, want)
+	}
+	next = routine.NextAllowedTime(at("2024-03-03T00:30:10Z"))
+	if want := at("2024-03-03T01:00:00Z"); !next.Equal(want) {
+		t.Errorf("NextAllowedTime() = %v, want %v", next, want)
+	}
+	if got := (&BackupRoutine{}).WindowRestriction(at("2024-03-04T08:00:00Z")); got != "" {
+		t.Errorf("WindowRestriction() = '%s', want no restriction", got)
+	}
+}
+
+func TestJobQueueConfigValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		jobQueue *JobQueueConfig
+		wantErr  bool
+	}{
+		{name: "not set"},
+		{name: "valid", jobQueue: &JobQueueConfig{MaxConcurrentJobs: ptr.Int(2), MaxConcurrentJobsPerCluster: ptr.Int(2)}},
+		{name: "zero jobs", jobQueue: &JobQueueConfig{MaxConcurrentJobs: ptr.Int(0)}, wantErr: true},
+		{name: "more jobs than libraries", jobQueue: &JobQueueConfig{MaxConcurrentJobs: ptr.Int(3)}, wantErr: true},
+		{name: "negative jobs per cluster", jobQueue: &JobQueueConfig{MaxConcurrentJobsPerCluster: ptr.Int(-1)},
+			wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := validConfig()
+			config.ServiceConfig.JobQueue = tt.jobQueue
+			if err := config.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGarbageCollectorConfigValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		gc      *GarbageCollectorConfig
+		wantErr bool
+	}{
+		{name: "not set"},
+		{name: "valid", gc: &GarbageCollectorConfig{Interval: ptr.Int64(1000), GracePeriod: ptr.Int64(1000)}},
+		{name: "zero interval", gc: &GarbageCollectorConfig{Interval: ptr.Int64(0)}, wantErr: true},
+		{name: "zero grace period", gc: &GarbageCollectorConfig{GracePeriod: ptr.Int64(0)}, wantErr: true},
+		{name: "negative grace period", gc: &GarbageCollectorConfig{GracePeriod: ptr.Int64(-1)}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := validConfig()
+			config.ServiceConfig.GarbageCollector = tt.gc
+			if err := config.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestJobQueueConfigPriorities(t *testing.T) {
+	var config *JobQueueConfig
+	if config.GetPriorityOrDefault(JobTypeRestore) <= config.GetPriorityOrDefault(JobTypeIncremental) ||
+		config.GetPriorityOrDefault(JobTypeIncremental) <= config.GetPriorityOrDefault(JobTypeFull) {
+		t.Errorf("Expected restores above incrementals above fulls by default")
+	}
+	config = &JobQueueConfig{Priorities: &JobPriorities{Full: ptr.Int(5)}}
+	if got := config.GetPriorityOrDefault(JobTypeFull); got != 5 {
+		t.Errorf("GetPriorityOrDefault() = %d, want 5", got)
+	}
+	if got := config.GetPriorityOrDefault(JobTypeRestore); got != 3 {
+		t.Errorf("GetPriorityOrDefault() = %d, want the default 3", got)
+	}
+}
+
+func TestS3CredentialsValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		credentials *S3Credentials
+		wantErr     bool
+	}{
+		{name: "no credentials"},
+		{name: "static keys from environment", credentials: &S3Credentials{
+			AccessKeyIDEnv: ptr.String("KEY_ID"), SecretAccessKeyEnv: ptr.String("SECRET")}},
+		{name: "static keys from files", credentials: &S3Credentials{
+			AccessKeyIDFile: ptr.String("/id"), SecretAccessKeyFile: ptr.String("/secret")}},
+		{name: "assume role with static keys", credentials: &S3Credentials{
+			AccessKeyIDEnv: ptr.String("KEY_ID"), SecretAccessKeyFile: ptr.String("/secret"),
+			RoleArn: ptr.String("arn"), ExternalID: ptr.String("id")}},
+		{name: "web identity", credentials: &S3Credentials{
+			RoleArn: ptr.String("arn"), WebIdentityTokenFile: ptr.String("/token")}},
+		{name: "web identity with sts endpoint", credentials: &S3Credentials{
+			RoleArn: ptr.String("arn"), WebIdentityTokenFile: ptr.String("/token"),
+			StsEndpointOverride: ptr.String("https://sts.local")}},
+		{name: "empty", credentials: &S3Credentials{}, wantErr: true},
+		{name: "access key id without secret", credentials: &S3Credentials{
+			AccessKeyIDEnv: ptr.String("KEY_ID")}, wantErr: true},
+		{name: "access key id from environment and file", credentials: &S3Credentials{
+			AccessKeyIDEnv: ptr.String("KEY_ID"), AccessKeyIDFile: ptr.String("/id"),
+			SecretAccessKeyEnv: ptr.String("SECRET")}, wantErr: true},
+		{name: "external id without role", credentials: &S3Credentials{
+			AccessKeyIDEnv: ptr.String("KEY_ID"), SecretAccessKeyEnv: ptr.String("SECRET"),
+			ExternalID: ptr.String("id")}, wantErr: true},
+		{name: "sts endpoint without role", credentials: &S3Credentials{
+			AccessKeyIDEnv: ptr.String("KEY_ID"), SecretAccessKeyEnv: ptr.String("SECRET"),
+			StsEndpointOverride: ptr.String("https://sts.local")}, wantErr: true},
+		{name: "web identity with external id", credentials: &S3Credentials{
+			RoleArn: ptr.String("arn"), WebIdentityTokenFile: ptr.String("/token"),
+			ExternalID: ptr.String("id")}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := Storage{
+				Type:          S3,
+				Path:          ptr.String("s3://bucket/path"),
+				S3Region:      ptr.String("eu-central-1"),
+				S3Credentials: tt.credentials,
+			}
+			if err := storage.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStorageIsStagedS3(t *testing.T) {
+	tests := []struct {
+		name    string
+		storage Storage
+		want    bool
+	}{
+		{name: "profile", storage: Storage{Type: S3, S3Profile: ptr.String("default")}},
+		{name: "explicit credentials", storage: Storage{Type: S3, S3Credentials: &S3Credentials{
+			RoleArn: ptr.String("arn:aws:iam::123456789012:role/backup")}}, want: true},
+		{name: "object options", storage: Storage{Type: S3, S3StorageClass: ptr.String("STANDARD_IA")}, want: true},
+		{name: "local", storage: Storage{Type: Local, S3Credentials: &S3Credentials{}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.storage.IsStagedS3(); got != tt.want {
+				t.Errorf("IsStagedS3() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestS3ObjectOptionsValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		storage Storage
+		wantErr bool
+	}{
+		{name: "no options", storage: Storage{}},
+		{name: "sse-s3 with storage class",
+			storage: Storage{S3ServerSideEncryption: ptr.String(SseS3), S3StorageClass: ptr.String("STANDARD_IA")}},
+		{name: "sse-kms with key id",
+			storage: Storage{S3ServerSideEncryption: ptr.String(SseKms), S3SseKmsKeyID: ptr.String("key")}},
+		{name: "sse-c", storage: Storage{S3ServerSideEncryption: ptr.String(SseC),
+			S3SseCustomerKeyFile: ptr.String("/run/secrets/sse-customer-key")}},
+		{name: "invalid sse", storage: Storage{S3ServerSideEncryption: ptr.String("AES")}, wantErr: true},
+		{name: "kms key id without sse-kms",
+			storage: Storage{S3ServerSideEncryption: ptr.String(SseS3), S3SseKmsKeyID: ptr.String("key")}, wantErr: true},
+		{name: "sse-c without key", storage: Storage{S3ServerSideEncryption: ptr.String(SseC)}, wantErr: true},
+		{name: "sse-c with key env and file",
+			storage: Storage{S3ServerSideEncryption: ptr.String(SseC), S3SseCustomerKeyEnv: ptr.String("S3_SSE_CUSTOMER_KEY"),
+				S3SseCustomerKeyFile: ptr.String("/run/secrets/sse-customer-key")}, wantErr: true},
+		{name: "customer key without sse-c",
+			storage: Storage{S3SseCustomerKeyEnv: ptr.String("S3_SSE_CUSTOMER_KEY")}, wantErr: true},
+		{name: "archive storage class", storage: Storage{S3StorageClass: ptr.String("GLACIER")}, wantErr: true},
+		{name: "object lock",
+			storage: Storage{S3ObjectLockMode: ptr.String(ObjectLockCompliance), S3ObjectLockDays: ptr.Int(30)}},
+		{name: "invalid object lock mode",
+			storage: Storage{S3ObjectLockMode: ptr.String("LEGAL"), S3ObjectLockDays: ptr.Int(30)}, wantErr: true},
+		{name: "object lock mode without days",
+			storage: Storage{S3ObjectLockMode: ptr.String(ObjectLockGovernance)}, wantErr: true},
+		{name: "object lock with zero days",
+			storage: Storage{S3ObjectLockMode: ptr.String(ObjectLockGovernance), S3ObjectLockDays: ptr.Int(0)}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := tt.storage
+			storage.Type = S3
+			storage.Path = ptr.String("s3://bucket/path")
+			storage.S3Region = ptr.String("eu-central-1")
+			if err := storage.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAzureSecretsValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		storage Storage
+		wantErr bool
+	}{
+		{name: "default credential", storage: Storage{}},
+		{name: "sas token env", storage: Storage{AzureSasTokenEnv: ptr.String("AZURE_SAS_TOKEN")}},
+		{name: "account key file", storage: Storage{AzureAccountKeyFile: ptr.String("/run/secrets/key")}},
+		{name: "client secret", storage: Storage{AzureTenantID: ptr.String("tenant"),
+			AzureClientID: ptr.String("client"), AzureClientSecretEnv: ptr.String("AZURE_CLIENT_SECRET")}},
+		{name: "account key env and file", storage: Storage{AzureAccountKeyEnv: ptr.String("AZURE_STORAGE_KEY"),
+			AzureAccountKeyFile: ptr.String("/run/secrets/key")}, wantErr: true},
+		{name: "client secret without tenant", storage: Storage{
+			AzureClientSecretFile: ptr.String("/run/secrets/secret")}, wantErr: true},
+		{name: "sas token and account key", storage: Storage{AzureSasTokenEnv: ptr.String("AZURE_SAS_TOKEN"),
+			AzureAccountKeyEnv: ptr.String("AZURE_STORAGE_KEY")}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := tt.storage
+			storage.Type = AzureBlob
+			storage.Path = ptr.String("backups")
+			storage.AzureAccountName = ptr.String("account")
+			storage.AzureContainerName = ptr.String("container")
+			if err := storage.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSftpPasswordValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		storage Storage
+		wantErr bool
+	}{
+		{name: "key file", storage: Storage{SftpKeyFile: ptr.String("/run/secrets/id_ed25519")}},
+		{name: "password env", storage: Storage{SftpPasswordEnv: ptr.String("SFTP_PASSWORD")}},
+		{name: "password file", storage: Storage{SftpPasswordFile: ptr.String("/run/secrets/password")}},
+		{name: "no authentication", storage: Storage{}, wantErr: true},
+		{name: "password env and file", storage: Storage{SftpPasswordEnv: ptr.String("SFTP_PASSWORD"),
+			SftpPasswordFile: ptr.String("/run/secrets/password")}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := tt.storage
+			storage.Type = SFTP
+			storage.Path = ptr.String("/backups")
+			storage.SftpHost = ptr.String("archive.example.com")
+			storage.SftpUser = ptr.String("backup")
+			storage.SftpKnownHostsFile = ptr.String("/etc/known_hosts")
+			if err := storage.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package model
+
+import "fmt"
+
+// GarbageCollectorConfig represents the configuration of the garbage collector,
+// which finds the folders left behind by failed backups.
+// @Description GarbageCollectorConfig represents the configuration of the garbage collector.
+//
+//nolint:lll
+type GarbageCollectorConfig struct {
+	// Whether to run the garbage collector periodically.
+	Enabled *bool `yaml:"enabled,omitempty" json:"enabled,omitempty" default:"true"`
+	// The interval between garbage collector runs in milliseconds.
+	Interval *int64 `yaml:"interval,omitempty" json:"interval,omitempty" default:"3600000" example:"3600000"`
+	// The minimum age in milliseconds of a backup folder to be considered garbage.
+	GracePeriod *int64 `yaml:"grace-period,omitempty" json:"grace-period,omitempty" default:"86400000" example:"86400000"`
+	// Whether to delete the garbage found. When false, the garbage is only reported.
+	Delete *bool `yaml:"delete,omitempty" json:"delete,omitempty" default:"false"`
+}
+
+// IsEnabled returns the value of the Enabled property.
+// If the property is not set, it returns the default value.
+func (g *GarbageCollectorConfig) IsEnabled() bool {
+	if g != nil && g.Enabled != nil {
+		return *g.Enabled
+	}
+	return *defaultConfig.garbageCollector.Enabled
+}
+
+// GetIntervalOrDefault returns the value of the Interval property.
+// If the property is not set, it returns the default value.
+func (g *GarbageCollectorConfig) GetIntervalOrDefault() int64 {
+	if g != nil && g.Interval != nil {
+		return *g.Interval
+	}
+	return *defaultConfig.garbageCollector.Interval
+}
+
+// GetGracePeriodOrDefault returns the value of the GracePeriod property.
+// If the property is not set, it returns the default value.
+func (g *GarbageCollectorConfig) GetGracePeriodOrDefault() int64 {
+	if g != nil && g.GracePeriod != nil {
+		return *g.GracePeriod
+	}
+	return *defaultConfig.garbageCollector.GracePeriod
+}
+
+// IsDelete returns the value of the Delete property.
+// If the property is not set, it returns the default value.
+func (g *GarbageCollectorConfig) IsDelete() bool {
+	if g != nil && g.Delete != nil {
+		return *g.Delete
+	}
+	return *defaultConfig.garbageCollector.Delete
+}
+
+// Validate validates the garbage collector configuration.
+func (g *GarbageCollectorConfig) Validate() error {
+	if g == nil {
+		return nil
+	}
+	if g.Interval != nil && *g.Interval <= 0 {
+		return fmt.Errorf("garbage collector interval %d invalid, should be positive number", *g.Interval)
+	}
+	if g.GracePeriod != nil && *g.GracePeriod <= 0 {
+		return fmt.Errorf("garbage collector grace period %d invalid, should be positive number", *g.GracePeriod)
+	}
+	return nil
+}
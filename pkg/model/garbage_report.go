@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// GarbageReport is the result of a garbage collector run.
+// @Description GarbageReport is the result of a garbage collector run.
+type GarbageReport struct {
+	// The time of the run in the ISO 8601 format.
+	Created time.Time `yaml:"created" json:"created" example:"2023-03-20T14:50:00Z"`
+	// The garbage folders found.
+	Folders []GarbageFolder `yaml:"folders" json:"folders"`
+}
+
+// GarbageFolder is a folder left behind by a failed backup.
+// @Description GarbageFolder is a folder left behind by a failed backup.
+type GarbageFolder struct {
+	// The backup routine name.
+	Routine string `yaml:"routine" json:"routine" example:"daily"`
+	// The path to the folder.
+	Path string `yaml:"path" json:"path" example:"storage/daily/backup/1707915600000/data/source-ns1"`
+	// The reason the folder is considered garbage.
+	Reason string `yaml:"reason" json:"reason" example:"missing metadata"`
+	// The backup time of the folder in the ISO 8601 format.
+	Created time.Time `yaml:"created" json:"created" example:"2023-03-20T14:50:00Z"`
+	// Whether the folder was deleted.
+	Deleted bool `yaml:"deleted" json:"deleted"`
+}
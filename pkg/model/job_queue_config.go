@@ -0,0 +1,104 @@
+package model
+
+import "fmt"
+
+// The types of the jobs run through the job queue.
+const (
+	JobTypeFull        = "full"
+	JobTypeIncremental = "incremental"
+	JobTypeRestore     = "restore"
+)
+
+// MaxConcurrentJobsLimit is the maximum number of the jobs running at the same time:
+// a backup job and a restore job, each running its own shared library.
+const MaxConcurrentJobsLimit = 2
+
+// JobQueueConfig represents the configuration of the service-wide job queue,
+// which runs the backup and restore jobs in priority order.
+// @Description JobQueueConfig represents the configuration of the service-wide job queue.
+//
+//nolint:lll
+type JobQueueConfig struct {
+	// The maximum number of the backup and restore jobs running at the same time, at most 2:
+	// a backup and a restore job, as the shared libraries are not reentrant.
+	MaxConcurrentJobs *int `yaml:"max-concurrent-jobs,omitempty" json:"max-concurrent-jobs,omitempty" default:"2" example:"2"`
+	// The maximum number of the backup and restore jobs running on the same cluster at the same time.
+	MaxConcurrentJobsPerCluster *int `yaml:"max-concurrent-jobs-per-cluster,omitempty" json:"max-concurrent-jobs-per-cluster,omitempty" default:"1" example:"1"`
+	// The priorities of the job types, the jobs of a higher priority run first.
+	Priorities *JobPriorities `yaml:"priorities,omitempty" json:"priorities,omitempty"`
+}
+
+// JobPriorities represents the priorities of the job types in the job queue.
+// @Description JobPriorities represents the priorities of the job types in the job queue.
+type JobPriorities struct {
+	// The priority of the restore jobs.
+	Restore *int `yaml:"restore,omitempty" json:"restore,omitempty" default:"3" example:"3"`
+	// The priority of the incremental backup jobs.
+	Incremental *int `yaml:"incremental,omitempty" json:"incremental,omitempty" default:"2" example:"2"`
+	// The priority of the full backup jobs.
+	Full *int `yaml:"full,omitempty" json:"full,omitempty" default:"1" example:"1"`
+}
+
+// GetMaxConcurrentJobsOrDefault returns the value of the MaxConcurrentJobs property.
+// If the property is not set, it returns the default value.
+func (q *JobQueueConfig) GetMaxConcurrentJobsOrDefault() int {
+	if q != nil && q.MaxConcurrentJobs != nil {
+		return *q.MaxConcurrentJobs
+	}
+	return *defaultConfig.jobQueue.MaxConcurrentJobs
+}
+
+// GetMaxConcurrentJobsPerClusterOrDefault returns the value of the
+// MaxConcurrentJobsPerCluster property.
+// If the property is not set, it returns the default value.
+func (q *JobQueueConfig) GetMaxConcurrentJobsPerClusterOrDefault() int {
+	if q != nil && q.MaxConcurrentJobsPerCluster != nil {
+		return *q.MaxConcurrentJobsPerCluster
+	}
+	return *defaultConfig.jobQueue.MaxConcurrentJobsPerCluster
+}
+
+// GetPriorityOrDefault returns the priority of the given job type.
+// If the priority is not set, it returns the default value.
+func (q *JobQueueConfig) GetPriorityOrDefault(jobType string) int {
+	var priorities JobPriorities
+	if q != nil && q.Priorities != nil {
+		priorities = *q.Priorities
+	}
+	defaults := defaultConfig.jobQueue.Priorities
+	switch jobType {
+	case JobTypeRestore:
+		return valueOrDefault(priorities.Restore, defaults.Restore)
+	case JobTypeIncremental:
+		return valueOrDefault(priorities.Incremental, defaults.Incremental)
+	default:
+		return valueOrDefault(priorities.Full, defaults.Full)
+	}
+}
+
+func valueOrDefault(value, defaultValue *int) int {
+	if value != nil {
+		return *value
+	}
+	return *defaultValue
+}
+
+// Validate validates the job queue configuration.
+func (q *JobQueueConfig) Validate() error {
+	if q == nil {
+		return nil
+	}
+	if q.MaxConcurrentJobs != nil && *q.MaxConcurrentJobs <= 0 {
+		return fmt.Errorf("max-concurrent-jobs %d invalid, should be positive number", *q.MaxConcurrentJobs)
+	}
+	// one job per shared library, see the job queue of the service
+	if q.MaxConcurrentJobs != nil && *q.MaxConcurrentJobs > MaxConcurrentJobsLimit {
+		return fmt.Errorf("max-concurrent-jobs %d invalid, should be at most %d",
+			*q.MaxConcurrentJobs, MaxConcurrentJobsLimit)
+	}
+	if q.MaxConcurrentJobsPerCluster != nil && *q.MaxConcurrentJobsPerCluster <= 0 {
+		return fmt.Errorf("max-concurrent-jobs-per-cluster %d invalid, should be positive number",
+			*q.MaxConcurrentJobsPerCluster)
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package model
+
+// PrunePreview is the result of evaluating the retention policy of a routine
+// against its existing backups. Nothing is deleted when the preview is built.
+// @Description PrunePreview is the result of evaluating the retention policy of a routine.
+type PrunePreview struct {
+	// The backups that would be deleted.
+	Delete []PruneCandidate `yaml:"delete" json:"delete"`
+	// The backups that would be kept.
+	Keep []PruneCandidate `yaml:"keep" json:"keep"`
+	// The total size in bytes of the backups that would be deleted.
+	ReclaimedBytes uint64 `yaml:"reclaimed-bytes" json:"reclaimed-bytes" format:"int64" example:"2000"`
+}
+
+// PruneCandidate is a backup evaluated by the retention policy.
+// @Description PruneCandidate is a backup evaluated by the retention policy.
+type PruneCandidate struct {
+	BackupDetails
+	// The backup type.
+	Type string `yaml:"type" json:"type" enums:"full,incremental" example:"full"`
+	// The reasons to keep or delete the backup.
+	Reasons []string `yaml:"reasons" json:"reasons" example:"keep-full"`
+}
+
+// Backup types of a PruneCandidate.
+const (
+	FullBackupType        = "full"
+	IncrementalBackupType = "incremental"
+)
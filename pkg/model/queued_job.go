@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+// The states of the jobs in the job queue.
+const (
+	QueuedJobWaiting = "waiting"
+	QueuedJobRunning = "running"
+)
+
+// QueuedJob represents a backup or restore job in the service-wide job queue.
+// @Description QueuedJob represents a backup or restore job in the service-wide job queue.
+//
+//nolint:lll
+type QueuedJob struct {
+	// The type of the job.
+	Type string `yaml:"type" json:"type" enums:"full,incremental,restore"`
+	// The state of the job.
+	State string `yaml:"state" json:"state" enums:"waiting,running"`
+	// The position of the waiting job in the queue, starting from 1. Zero for the running jobs.
+	Position int `yaml:"position,omitempty" json:"position,omitempty" example:"1"`
+	// The priority of the job, the jobs of a higher priority run first.
+	Priority int `yaml:"priority" json:"priority" example:"2"`
+	// The backup routine name, if the job belongs to a routine.
+	Routine string `yaml:"routine,omitempty" json:"routine,omitempty" example:"daily"`
+	// The namespace of the job.
+	Namespace string `yaml:"namespace,omitempty" json:"namespace,omitempty" example:"source-ns1"`
+	// The seed nodes of the cluster the job runs on.
+	Cluster string `yaml:"cluster" json:"cluster" example:"localhost:3000"`
+	// The restore job id, for the restore jobs.
+	JobID int `yaml:"job-id,omitempty" json:"job-id,omitempty" example:"123"`
+	// The time the job was queued at in the ISO 8601 format.
+	Queued time.Time `yaml:"queued" json:"queued" example:"2023-03-20T14:50:00Z"`
+	// The time the job started running at in the ISO 8601 format.
+	Started *time.Time `yaml:"started,omitempty" json:"started,omitempty" example:"2023-03-20T14:55:00Z"`
+}
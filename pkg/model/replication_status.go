@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+// ReplicationState represents the state of the replication of a backup.
+// @Description ReplicationState represents the state of the replication of a backup.
+type ReplicationState string
+
+const (
+	// ReplicationPending means the replication is in progress or scheduled for retry.
+	ReplicationPending ReplicationState = "pending"
+	// ReplicationDone means the backup was copied and verified.
+	ReplicationDone ReplicationState = "done"
+	// ReplicationFailed means the replication failed after all the retries.
+	ReplicationFailed ReplicationState = "failed"
+)
+
+// ReplicationStatus represents the status of the replication of a backup to a secondary storage.
+// @Description ReplicationStatus represents the status of the replication of a backup to a secondary storage.
+//
+//nolint:lll
+type ReplicationStatus struct {
+	// The name of the secondary storage.
+	Storage string `yaml:"storage" json:"storage" example:"s3-dr"`
+	// The state of the replication.
+	State ReplicationState `yaml:"state" json:"state" enums:"pending,done,failed" example:"done"`
+	// The number of replication attempts.
+	Attempts int32 `yaml:"attempts" json:"attempts" example:"1"`
+	// The number of bytes copied.
+	Bytes uint64 `yaml:"bytes,omitempty" json:"bytes,omitempty" format:"int64" example:"2000"`
+	// The time of the last update of the status.
+	Updated time.Time `yaml:"updated" json:"updated" example:"2023-03-20T14:50:00Z"`
+	// The error of the last failed attempt.
+	Error string `yaml:"error,omitempty" json:"error,omitempty"`
+}
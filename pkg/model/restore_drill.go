@@ -0,0 +1,74 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/reugn/go-quartz/quartz"
+)
+
+// RestoreDrill represents a scheduled test restore of the routine backups into a
+// scratch cluster or namespace. The latest backup of the source namespace is restored
+// to the destination namespace, and the number of restored records is compared with
+// the backup metadata.
+// @Description RestoreDrill represents a scheduled test restore of the routine backups.
+//
+//nolint:lll
+type RestoreDrill struct {
+	// The interval for the restore drill as a cron expression string.
+	IntervalCron string `yaml:"interval-cron" json:"interval-cron" example:"0 0 4 * * 1" validate:"required"`
+	// The name of the Aerospike cluster to restore the backups to.
+	DestinationCluster string `yaml:"destination-cluster" json:"destination-cluster" example:"scratchCluster" validate:"required"`
+	// The namespace to restore and the scratch namespace to restore it to.
+	Namespace *RestoreNamespace `yaml:"namespace" json:"namespace" validate:"required"`
+}
+
+// Validate validates the restore drill of the routine.
+func (d *RestoreDrill) Validate(r *BackupRoutine, c *Config) error {
+	if err := quartz.ValidateCronExpression(d.IntervalCron); err != nil {
+		return fmt.Errorf("restore drill interval string '%s' invalid: %v", d.IntervalCron, err)
+	}
+	if d.DestinationCluster == "" {
+		return emptyFieldValidationError("restore drill destination-cluster")
+	}
+	if _, exists := c.AerospikeClusters[d.DestinationCluster]; !exists {
+		return notFoundValidationError("restore drill Aerospike cluster", d.DestinationCluster)
+	}
+	if d.Namespace == nil || d.Namespace.Source == nil || d.Namespace.Destination == nil {
+		return errors.New("restore drill namespace is not specified")
+	}
+	if len(r.Namespaces) > 0 && !slices.Contains(r.Namespaces, *d.Namespace.Source) {
+		return fmt.Errorf("restore drill namespace %s is not backed up by the routine", *d.Namespace.Source)
+	}
+	if d.DestinationCluster == r.SourceCluster && *d.Namespace.Destination == *d.Namespace.Source {
+		return errors.New("restore drill would overwrite the backed up namespace")
+	}
+	return nil
+}
+
+// RestoreDrillResult is the result of a restore drill.
+// @Description RestoreDrillResult is the result of a restore drill.
+type RestoreDrillResult struct {
+	// The backup routine name.
+	Routine string `yaml:"routine" json:"routine" example:"daily"`
+	// The start time of the drill in the ISO 8601 format.
+	Started time.Time `yaml:"started" json:"started" example:"2023-03-20T14:50:00Z"`
+	// The end time of the drill in the ISO 8601 format.
+	Finished time.Time `yaml:"finished" json:"finished" example:"2023-03-20T14:55:00Z"`
+	// The restored namespace.
+	Namespace string `yaml:"namespace,omitempty" json:"namespace,omitempty" example:"source-ns1"`
+	// The creation time of the restored full backup in the ISO 8601 format.
+	BackupTime *time.Time `yaml:"backup-time,omitempty" json:"backup-time,omitempty" example:"2023-03-20T00:00:00Z"`
+	// The number of applied backups, the full backup and the incremental ones.
+	BackupCount int `yaml:"backup-count" json:"backup-count" example:"3"`
+	// The number of records of the applied backups according to their metadata.
+	ExpectedRecords uint64 `yaml:"expected-records" json:"expected-records" format:"int64" example:"100"`
+	// The result of the restore operation.
+	Result RestoreResult `yaml:"result" json:"result"`
+	// Whether the drill passed.
+	Passed bool `yaml:"passed" json:"passed"`
+	// The reason the drill failed, if any.
+	Error string `yaml:"error,omitempty" json:"error,omitempty"`
+}
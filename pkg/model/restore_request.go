@@ -33,6 +33,9 @@ type RestoreTimestampRequest struct {
 	Time int64 `json:"time,omitempty" format:"int64" example:"1739538000000" validate:"required"`
 	// The backup routine name.
 	Routine string `json:"routine,omitempty" example:"daily" validate:"required"`
+	// Whether to restore past a gap in the incremental backup chain, logging a warning.
+	// By default, such restore is refused, as the records changed during the gap would be missing.
+	AllowGaps bool `json:"allow-gaps,omitempty" example:"false"`
 }
 
 // String satisfies the fmt.Stringer interface.
@@ -14,6 +14,8 @@ type RestoreJobStatus struct {
 	RestoreResult
 	Status JobStatus `yaml:"status,omitempty" json:"status,omitempty" enums:"Running,Done,Failed"`
 	Error  string    `yaml:"error,omitempty" json:"error,omitempty"`
+	// The position of the restore in the job queue while it waits to run.
+	QueuePosition int `yaml:"queue-position,omitempty" json:"queue-position,omitempty" example:"2"`
 }
 
 // RestoreResult represents a single restore operation result.
@@ -0,0 +1,88 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+)
+
+// RetentionPolicy defines which backups are kept when the old backups of a routine are pruned.
+// A full backup is retained if any of the configured rules retains it.
+// The latest full backup is never pruned.
+// @Description RetentionPolicy defines which backups are kept when the old backups of a routine are pruned.
+type RetentionPolicy struct {
+	// The number of the latest full backups to keep.
+	KeepFull *int `yaml:"keep-full,omitempty" json:"keep-full,omitempty" example:"7"`
+	// Keep full backups created within the given number of days.
+	KeepDays *int `yaml:"keep-days,omitempty" json:"keep-days,omitempty" example:"30"`
+	// Whether to delete incremental backups that belong to pruned full backups (default: true).
+	// When false, incremental backups are kept regardless of their full backup.
+	PruneIncrementals *bool `yaml:"prune-incrementals,omitempty" json:"prune-incrementals,omitempty"`
+	// Grandfather-father-son retention tiers (optional).
+	GFS *GFSRetention `yaml:"gfs,omitempty" json:"gfs,omitempty"`
+}
+
+// GFSRetention defines grandfather-father-son retention tiers.
+// For each tier, the latest full backup of each of the given number of the most
+// recent periods (UTC calendar days, ISO weeks, months and years) is kept.
+// @Description GFSRetention defines grandfather-father-son retention tiers.
+type GFSRetention struct {
+	// The number of daily full backups to keep.
+	Daily *int `yaml:"daily,omitempty" json:"daily,omitempty" example:"7"`
+	// The number of weekly full backups to keep.
+	Weekly *int `yaml:"weekly,omitempty" json:"weekly,omitempty" example:"4"`
+	// The number of monthly full backups to keep.
+	Monthly *int `yaml:"monthly,omitempty" json:"monthly,omitempty" example:"12"`
+	// The number of yearly full backups to keep.
+	Yearly *int `yaml:"yearly,omitempty" json:"yearly,omitempty" example:"3"`
+}
+
+// IsPruneIncrementals returns the value of the PruneIncrementals property.
+// If the property is not set, it returns the default value.
+func (r *RetentionPolicy) IsPruneIncrementals() bool {
+	if r.PruneIncrementals != nil {
+		return *r.PruneIncrementals
+	}
+	return defaultConfig.retentionPolicy.pruneIncrementals
+}
+
+// Validate validates the retention policy.
+func (r *RetentionPolicy) Validate() error {
+	if r == nil {
+		return nil
+	}
+	if r.KeepFull == nil && r.KeepDays == nil && r.GFS == nil {
+		return errors.New("retention policy should define at least one retention rule")
+	}
+	if r.KeepFull != nil && *r.KeepFull <= 0 {
+		return fmt.Errorf("keepFull %d invalid, should be positive number", *r.KeepFull)
+	}
+	if r.KeepDays != nil && *r.KeepDays <= 0 {
+		return fmt.Errorf("keepDays %d invalid, should be positive number", *r.KeepDays)
+	}
+	if err := r.GFS.Validate(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Validate validates the GFS retention tiers.
+func (g *GFSRetention) Validate() error {
+	if g == nil {
+		return nil
+	}
+	if g.Daily == nil && g.Weekly == nil && g.Monthly == nil && g.Yearly == nil {
+		return errors.New("gfs retention should define at least one tier")
+	}
+	tiers := []struct {
+		name  string
+		value *int
+	}{
+		{"daily", g.Daily}, {"weekly", g.Weekly}, {"monthly", g.Monthly}, {"yearly", g.Yearly},
+	}
+	for _, tier := range tiers {
+		if tier.value != nil && *tier.value <= 0 {
+			return fmt.Errorf("gfs %s %d invalid, should be positive number", tier.name, *tier.value)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// RoutineStatus represents the scheduling status of a backup routine.
+// @Description RoutineStatus represents the scheduling status of a backup routine.
+//
+//nolint:lll
+type RoutineStatus struct {
+	// The backup routine name.
+	Routine string `yaml:"routine" json:"routine" example:"daily"`
+	// Whether the scheduled backups of the routine are paused.
+	Paused bool `yaml:"paused" json:"paused"`
+	// The time the scheduled backups were paused at in the ISO 8601 format.
+	PausedAt *time.Time `yaml:"paused-at,omitempty" json:"paused-at,omitempty" example:"2023-12-15T13:00:00Z"`
+	// The time of the last full backup in the ISO 8601 format.
+	LastFullRun *time.Time `yaml:"last-full-run,omitempty" json:"last-full-run,omitempty" example:"2023-12-14T10:08:54Z"`
+	// The time of the last incremental backup in the ISO 8601 format.
+	LastIncrementalRun *time.Time `yaml:"last-incremental-run,omitempty" json:"last-incremental-run,omitempty" example:"2023-12-15T12:00:00Z"`
+	// The next scheduled full backup time in the ISO 8601 format, unless paused.
+	NextFullRun *time.Time `yaml:"next-full-run,omitempty" json:"next-full-run,omitempty" example:"2023-12-16T10:00:00Z"`
+	// The next scheduled incremental backup time in the ISO 8601 format, unless paused.
+	NextIncrementalRun *time.Time `yaml:"next-incremental-run,omitempty" json:"next-incremental-run,omitempty" example:"2023-12-15T13:00:00Z"`
+}
@@ -0,0 +1,81 @@
+package model
+
+import (
+	"errors"
+)
+
+// S3Credentials represents the explicit credentials of an S3 storage.
+// The static access keys are read from files or environment variables, and can be
+// used to assume a role. A role can also be assumed with a web identity token file.
+// If not set, the credentials of the S3 profile are used.
+// @Description S3Credentials represents the explicit credentials of an S3 storage.
+//
+//nolint:lll
+type S3Credentials struct {
+	// The name of the environment variable containing the access key ID.
+	AccessKeyIDEnv *string `yaml:"access-key-id-env,omitempty" json:"access-key-id-env,omitempty" example:"AWS_ACCESS_KEY_ID"`
+	// The name of the environment variable containing the secret access key.
+	SecretAccessKeyEnv *string `yaml:"secret-access-key-env,omitempty" json:"secret-access-key-env,omitempty" example:"AWS_SECRET_ACCESS_KEY"`
+	// The path to the file containing the access key ID.
+	AccessKeyIDFile *string `yaml:"access-key-id-file,omitempty" json:"access-key-id-file,omitempty" example:"/var/run/secrets/s3/access-key-id"`
+	// The path to the file containing the secret access key.
+	SecretAccessKeyFile *string `yaml:"secret-access-key-file,omitempty" json:"secret-access-key-file,omitempty" example:"/var/run/secrets/s3/secret-access-key"`
+	// The ARN of the role to assume.
+	RoleArn *string `yaml:"role-arn,omitempty" json:"role-arn,omitempty" example:"arn:aws:iam::111122223333:role/backup"`
+	// The external ID to pass when assuming the role (optional).
+	ExternalID *string `yaml:"external-id,omitempty" json:"external-id,omitempty" example:"aerospike-backup"`
+	// The session name of the assumed role (optional).
+	RoleSessionName *string `yaml:"role-session-name,omitempty" json:"role-session-name,omitempty" example:"aerospike-backup-service"`
+	// The path to the web identity token file to assume the role with,
+	// such as the projected service account token in Kubernetes.
+	WebIdentityTokenFile *string `yaml:"web-identity-token-file,omitempty" json:"web-identity-token-file,omitempty" example:"/var/run/secrets/eks.amazonaws.com/serviceaccount/token"`
+	// The STS endpoint to assume the role with (optional), such as a VPC endpoint.
+	// The default STS endpoint of the region is used if not set.
+	StsEndpointOverride *string `yaml:"sts-endpoint-override,omitempty" json:"sts-endpoint-override,omitempty" example:"https://sts.eu-central-1.amazonaws.com"`
+}
+
+// HasStaticKeys returns true if the static access keys are configured.
+func (c *S3Credentials) HasStaticKeys() bool {
+	return c.AccessKeyIDEnv != nil || c.AccessKeyIDFile != nil ||
+		c.SecretAccessKeyEnv != nil || c.SecretAccessKeyFile != nil
+}
+
+// Validate validates the S3 credentials.
+func (c *S3Credentials) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.AccessKeyIDEnv != nil && c.AccessKeyIDFile != nil {
+		return errors.New("s3 access key id should be read either from an environment variable or a file")
+	}
+	if c.SecretAccessKeyEnv != nil && c.SecretAccessKeyFile != nil {
+		return errors.New("s3 secret access key should be read either from an environment variable or a file")
+	}
+	if c.HasStaticKeys() {
+		if c.AccessKeyIDEnv == nil && c.AccessKeyIDFile == nil {
+			return errors.New("s3 access key id is not specified")
+		}
+		if c.SecretAccessKeyEnv == nil && c.SecretAccessKeyFile == nil {
+			return errors.New("s3 secret access key is not specified")
+		}
+	}
+	if c.RoleArn == nil {
+		if c.ExternalID != nil || c.RoleSessionName != nil || c.WebIdentityTokenFile != nil ||
+			c.StsEndpointOverride != nil {
+			return errors.New("s3 role arn is not specified")
+		}
+		if !c.HasStaticKeys() {
+			return errors.New("s3 credentials are empty")
+		}
+		return nil
+	}
+	if c.WebIdentityTokenFile != nil {
+		if c.HasStaticKeys() {
+			return errors.New("s3 web identity token file cannot be used with static access keys")
+		}
+		if c.ExternalID != nil {
+			return errors.New("s3 external id cannot be used with a web identity token file")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+)
+
+// SigningConfig represents the configuration of the backup signing.
+// The metadata and the manifest of each new backup are signed with the active
+// Ed25519 key, and the signatures are verified on listing and before restore.
+// To rotate the key, a new key is added and made active, while the public keys
+// of the previous ones are kept to verify the older backups.
+// @Description SigningConfig represents the configuration of the backup signing.
+//
+//nolint:lll
+type SigningConfig struct {
+	// The ID of the key the new backups are signed with, one of the configured keys.
+	KeyID string `yaml:"key-id" json:"key-id" example:"2024-01" validate:"required"`
+	// The signing keys by key ID.
+	Keys map[string]*SigningKey `yaml:"keys" json:"keys" validate:"required"`
+	// Whether to refuse to restore the unsigned backups and the ones with invalid signature.
+	RequireSignature *bool `yaml:"require-signature,omitempty" json:"require-signature,omitempty" default:"false"`
+}
+
+// SigningKey represents an Ed25519 key pair used for the backup signing.
+// @Description SigningKey represents an Ed25519 key pair used for the backup signing.
+//
+//nolint:lll
+type SigningKey struct {
+	// The path to the PEM encoded PKCS #8 private key file. Required for the active key only.
+	PrivateKeyFile *string `yaml:"private-key-file,omitempty" json:"private-key-file,omitempty" example:"/etc/aerospike-backup-service/signing.pem"`
+	// The path to the PEM encoded PKIX public key file. Derived from the private key if not set.
+	PublicKeyFile *string `yaml:"public-key-file,omitempty" json:"public-key-file,omitempty" example:"/etc/aerospike-backup-service/signing.pub"`
+}
+
+// IsSignatureRequired returns the value of the RequireSignature property.
+// If the property is not set, it returns the default value.
+func (s *SigningConfig) IsSignatureRequired() bool {
+	if s != nil && s.RequireSignature != nil {
+		return *s.RequireSignature
+	}
+	return *defaultConfig.signing.RequireSignature
+}
+
+// Validate validates the signing configuration.
+func (s *SigningConfig) Validate() error {
+	if s == nil {
+		return nil
+	}
+	if s.KeyID == "" {
+		return emptyFieldValidationError("signing key-id")
+	}
+	for keyID, key := range s.Keys {
+		if keyID == "" {
+			return emptyFieldValidationError("signing key ID")
+		}
+		if key == nil || (key.PrivateKeyFile == nil && key.PublicKeyFile == nil) {
+			return fmt.Errorf("signing key %s has no key file", keyID)
+		}
+	}
+	active, found := s.Keys[s.KeyID]
+	if !found {
+		return notFoundValidationError("signing key", s.KeyID)
+	}
+	if active.PrivateKeyFile == nil {
+		return errors.New("private-key-file of the active signing key is not specified")
+	}
+	return nil
+}
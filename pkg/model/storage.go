@@ -15,17 +15,85 @@ import (
 //nolint:lll
 type Storage struct {
 	// The type of the storage provider
-	Type StorageType `yaml:"type" json:"type" enums:"local,aws-s3" validate:"required"`
-	// The root path for the backup repository.
+	Type StorageType `yaml:"type" json:"type" enums:"local,aws-s3,gcp-gcs,azure-blob,sftp" validate:"required"`
+	// The root path for the backup repository. For GCP GCS and Azure Blob, the prefix within the bucket or container.
+	// For SFTP, the absolute path on the host.
 	Path *string `yaml:"path,omitempty" json:"path,omitempty" example:"backups" validate:"required"`
 	// The S3 region string (AWS S3 optional).
 	S3Region *string `yaml:"s3-region,omitempty" json:"s3-region,omitempty" example:"eu-central-1"`
 	// The S3 profile name (AWS S3 optional).
 	S3Profile *string `yaml:"s3-profile,omitempty" json:"s3-profile,omitempty" example:"default"`
+	// The explicit credentials of the S3 storage (AWS S3 optional).
+	// The credentials of the S3 profile are used if not set.
+	S3Credentials *S3Credentials `yaml:"s3-credentials,omitempty" json:"s3-credentials,omitempty"`
 	// An alternative endpoint for the S3 SDK to communicate (AWS S3 optional).
 	S3EndpointOverride *string `yaml:"s3-endpoint-override,omitempty" json:"s3-endpoint-override,omitempty" example:"http://host.docker.internal:9000"`
 	// The log level of the AWS S3 SDK (AWS S3 optional).
 	S3LogLevel *string `yaml:"s3-log-level,omitempty" json:"s3-log-level,omitempty" default:"FATAL" enum:"OFF,FATAL,ERROR,WARN,INFO,DEBUG,TRACE"`
+	// The server-side encryption of the S3 objects (AWS S3 optional).
+	S3ServerSideEncryption *string `yaml:"s3-sse,omitempty" json:"s3-sse,omitempty" enums:"SSE-S3,SSE-KMS,SSE-C" example:"SSE-KMS"`
+	// The KMS key ID for SSE-KMS (AWS S3 optional). The AWS managed key is used if not set.
+	S3SseKmsKeyID *string `yaml:"s3-sse-kms-key-id,omitempty" json:"s3-sse-kms-key-id,omitempty" example:"arn:aws:kms:eu-central-1:111122223333:key/1234abcd-12ab-34cd-56ef-1234567890ab"`
+	// The name of the environment variable containing the base64-encoded 256-bit customer key for SSE-C
+	// (AWS S3, the variable or the file is required for SSE-C).
+	S3SseCustomerKeyEnv *string `yaml:"s3-sse-customer-key-env,omitempty" json:"s3-sse-customer-key-env,omitempty" example:"S3_SSE_CUSTOMER_KEY"`
+	// The path to the file containing the base64-encoded 256-bit customer key for SSE-C
+	// (AWS S3, the variable or the file is required for SSE-C).
+	S3SseCustomerKeyFile *string `yaml:"s3-sse-customer-key-file,omitempty" json:"s3-sse-customer-key-file,omitempty" example:"/var/run/secrets/s3/sse-customer-key"`
+	// The storage class of the S3 objects (AWS S3 optional).
+	S3StorageClass *string `yaml:"s3-storage-class,omitempty" json:"s3-storage-class,omitempty" enums:"STANDARD,REDUCED_REDUNDANCY,STANDARD_IA,ONEZONE_IA,INTELLIGENT_TIERING,GLACIER_IR" example:"STANDARD_IA"`
+	// The Object Lock retention mode of the backup objects (AWS S3 optional).
+	// The bucket should have Object Lock enabled.
+	S3ObjectLockMode *string `yaml:"s3-object-lock-mode,omitempty" json:"s3-object-lock-mode,omitempty" enums:"GOVERNANCE,COMPLIANCE" example:"COMPLIANCE"`
+	// The number of days the backup objects are locked for after they are written (AWS S3, required for Object Lock).
+	S3ObjectLockDays *int `yaml:"s3-object-lock-days,omitempty" json:"s3-object-lock-days,omitempty" example:"30"`
+	// The GCS bucket name (GCP GCS required).
+	GcpBucket *string `yaml:"gcp-bucket,omitempty" json:"gcp-bucket,omitempty" example:"as-backup-bucket"`
+	// The path to the service account credentials JSON file (GCP GCS optional).
+	// Application default credentials are used if not set.
+	GcpCredentialsFile *string `yaml:"gcp-credentials-file,omitempty" json:"gcp-credentials-file,omitempty" example:"/etc/aerospike-backup-service/gcp-credentials.json"`
+	// An alternative endpoint for the GCS client to communicate (GCP GCS optional).
+	GcpEndpointOverride *string `yaml:"gcp-endpoint-override,omitempty" json:"gcp-endpoint-override,omitempty" example:"http://localhost:4443/storage/v1/"`
+	// The Azure storage account name (Azure Blob required).
+	AzureAccountName *string `yaml:"azure-account-name,omitempty" json:"azure-account-name,omitempty" example:"asbackupaccount"`
+	// The Azure Blob container name (Azure Blob required).
+	AzureContainerName *string `yaml:"azure-container-name,omitempty" json:"azure-container-name,omitempty" example:"as-backup-container"`
+	// The name of the environment variable containing the shared access signature token (Azure Blob optional).
+	AzureSasTokenEnv *string `yaml:"azure-sas-token-env,omitempty" json:"azure-sas-token-env,omitempty" example:"AZURE_SAS_TOKEN"`
+	// The path to the file containing the shared access signature token (Azure Blob optional).
+	AzureSasTokenFile *string `yaml:"azure-sas-token-file,omitempty" json:"azure-sas-token-file,omitempty" example:"/var/run/secrets/azure/sas-token"`
+	// The name of the environment variable containing the shared key of the storage account (Azure Blob optional).
+	AzureAccountKeyEnv *string `yaml:"azure-account-key-env,omitempty" json:"azure-account-key-env,omitempty" example:"AZURE_STORAGE_KEY"`
+	// The path to the file containing the shared key of the storage account (Azure Blob optional).
+	AzureAccountKeyFile *string `yaml:"azure-account-key-file,omitempty" json:"azure-account-key-file,omitempty" example:"/var/run/secrets/azure/account-key"`
+	// The tenant ID of the service principal (Azure Blob optional).
+	AzureTenantID *string `yaml:"azure-tenant-id,omitempty" json:"azure-tenant-id,omitempty"`
+	// The client ID of the service principal (Azure Blob optional).
+	AzureClientID *string `yaml:"azure-client-id,omitempty" json:"azure-client-id,omitempty"`
+	// The name of the environment variable containing the client secret of the service principal (Azure Blob optional).
+	AzureClientSecretEnv *string `yaml:"azure-client-secret-env,omitempty" json:"azure-client-secret-env,omitempty" example:"AZURE_CLIENT_SECRET"`
+	// The path to the file containing the client secret of the service principal (Azure Blob optional).
+	// The default Azure credential is used if no SAS token, shared key or client secret is set.
+	AzureClientSecretFile *string `yaml:"azure-client-secret-file,omitempty" json:"azure-client-secret-file,omitempty" example:"/var/run/secrets/azure/client-secret"`
+	// An alternative service URL for the Azure SDK to communicate (Azure Blob optional).
+	AzureEndpointOverride *string `yaml:"azure-endpoint-override,omitempty" json:"azure-endpoint-override,omitempty" example:"http://127.0.0.1:10000/devstoreaccount1"`
+	// The SFTP host name (SFTP required).
+	SftpHost *string `yaml:"sftp-host,omitempty" json:"sftp-host,omitempty" example:"archive.example.com"`
+	// The SFTP port (SFTP optional).
+	SftpPort *int `yaml:"sftp-port,omitempty" json:"sftp-port,omitempty" default:"22" example:"22"`
+	// The SFTP user name (SFTP required).
+	SftpUser *string `yaml:"sftp-user,omitempty" json:"sftp-user,omitempty" example:"backup"`
+	// The path to the private key file to authenticate (SFTP optional).
+	SftpKeyFile *string `yaml:"sftp-key-file,omitempty" json:"sftp-key-file,omitempty" example:"/etc/aerospike-backup-service/id_ed25519"`
+	// The name of the environment variable containing the password to authenticate (SFTP optional).
+	SftpPasswordEnv *string `yaml:"sftp-password-env,omitempty" json:"sftp-password-env,omitempty" example:"SFTP_PASSWORD"`
+	// The path to the file containing the password to authenticate (SFTP optional).
+	// Either the key file or the password is required.
+	SftpPasswordFile *string `yaml:"sftp-password-file,omitempty" json:"sftp-password-file,omitempty" example:"/var/run/secrets/sftp/password"`
+	// The path to the known_hosts file to verify the host key (SFTP required).
+	SftpKnownHostsFile *string `yaml:"sftp-known-hosts-file,omitempty" json:"sftp-known-hosts-file,omitempty" example:"/etc/aerospike-backup-service/known_hosts"`
+	// The capacity quota of the storage (optional).
+	Quota *StorageQuota `yaml:"quota,omitempty" json:"quota,omitempty"`
 }
 
 // StorageType represents the type of the backup storage.
@@ -33,12 +101,33 @@ type Storage struct {
 type StorageType string
 
 const (
-	Local StorageType = "local"
-	S3    StorageType = "aws-s3"
+	Local     StorageType = "local"
+	S3        StorageType = "aws-s3"
+	GcpGCS    StorageType = "gcp-gcs"
+	AzureBlob StorageType = "azure-blob"
+	SFTP      StorageType = "sftp"
+)
+
+// The Object Lock retention modes of the S3 objects.
+const (
+	ObjectLockGovernance = "GOVERNANCE"
+	ObjectLockCompliance = "COMPLIANCE"
+)
+
+// The server-side encryption types of the S3 objects.
+const (
+	SseS3  = "SSE-S3"
+	SseKms = "SSE-KMS"
+	SseC   = "SSE-C"
 )
 
 var validS3LogLevels = []string{"OFF", "FATAL", "ERROR", "WARN", "INFO", "DEBUG", "TRACE"}
 
+// validS3StorageClasses are the storage classes of the objects readable without
+// restoring them from an archive first.
+var validS3StorageClasses = []string{"STANDARD", "REDUCED_REDUNDANCY", "STANDARD_IA", "ONEZONE_IA",
+	"INTELLIGENT_TIERING", "GLACIER_IR"}
+
 // Validate validates the storage configuration.
 func (s *Storage) Validate() error {
 	if s == nil {
@@ -54,11 +143,35 @@ func (s *Storage) Validate() error {
 		if s.S3Region == nil || len(*s.S3Region) == 0 {
 			return errors.New("s3 region is not specified")
 		}
+		if err := s.validateS3ObjectOptions(); err != nil {
+			return err
+		}
+		if err := s.S3Credentials.Validate(); err != nil {
+			return err
+		}
+	}
+	if s.Type == GcpGCS {
+		if s.GcpBucket == nil || len(*s.GcpBucket) == 0 {
+			return errors.New("gcp bucket is not specified")
+		}
+	}
+	if s.Type == AzureBlob {
+		if err := s.validateAzure(); err != nil {
+			return err
+		}
+	}
+	if s.Type == SFTP {
+		if err := s.validateSftp(); err != nil {
+			return err
+		}
 	}
 	if s.S3LogLevel != nil &&
 		!slices.Contains(validS3LogLevels, strings.ToUpper(*s.S3LogLevel)) {
 		return errors.New("invalid s3 log level")
 	}
+	if err := s.Quota.Validate(); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -66,13 +179,154 @@ func (s *Storage) Validate() error {
 func (s *Storage) validateType() error {
 	s.Type = StorageType(strings.ToLower(string(s.Type)))
 	switch s.Type {
-	case Local, S3:
+	case Local, S3, GcpGCS, AzureBlob, SFTP:
 		return nil
 	default:
 		return fmt.Errorf("invalid storage type: %v", s.Type)
 	}
 }
 
+// validateS3ObjectOptions validates the server-side encryption and the storage class
+// of the S3 objects.
+func (s *Storage) validateS3ObjectOptions() error {
+	if s.S3ServerSideEncryption != nil &&
+		!slices.Contains([]string{SseS3, SseKms, SseC}, *s.S3ServerSideEncryption) {
+		return fmt.Errorf("invalid s3 server-side encryption: %s. Possible values: %s, %s, %s",
+			*s.S3ServerSideEncryption, SseS3, SseKms, SseC)
+	}
+	isSse := func(sse string) bool {
+		return s.S3ServerSideEncryption != nil && *s.S3ServerSideEncryption == sse
+	}
+	if s.S3SseKmsKeyID != nil && !isSse(SseKms) {
+		return fmt.Errorf("s3 sse kms key id requires %s server-side encryption", SseKms)
+	}
+	if err := validateSecret("s3 sse customer key", s.S3SseCustomerKeyEnv, s.S3SseCustomerKeyFile); err != nil {
+		return err
+	}
+	customerKey := isSecretSet(s.S3SseCustomerKeyEnv, s.S3SseCustomerKeyFile)
+	if customerKey && !isSse(SseC) {
+		return fmt.Errorf("s3 sse customer key requires %s server-side encryption", SseC)
+	}
+	if isSse(SseC) && !customerKey {
+		return errors.New("s3 sse customer key is not specified")
+	}
+	if s.S3StorageClass != nil && !slices.Contains(validS3StorageClasses, *s.S3StorageClass) {
+		return fmt.Errorf("invalid s3 storage class: %s. Possible values: %s",
+			*s.S3StorageClass, strings.Join(validS3StorageClasses, ", "))
+	}
+	if s.S3ObjectLockMode != nil &&
+		*s.S3ObjectLockMode != ObjectLockGovernance && *s.S3ObjectLockMode != ObjectLockCompliance {
+		return fmt.Errorf("invalid s3 object lock mode: %s. Possible values: %s, %s",
+			*s.S3ObjectLockMode, ObjectLockGovernance, ObjectLockCompliance)
+	}
+	if (s.S3ObjectLockMode == nil) != (s.S3ObjectLockDays == nil) {
+		return errors.New("s3 object lock mode and days should be specified together")
+	}
+	if s.S3ObjectLockDays != nil && *s.S3ObjectLockDays <= 0 {
+		return fmt.Errorf("s3 object lock days %d invalid, should be positive number", *s.S3ObjectLockDays)
+	}
+	return nil
+}
+
+// HasS3ObjectOptions returns true if the server-side encryption, the storage class
+// or the Object Lock of the S3 objects is configured.
+func (s *Storage) HasS3ObjectOptions() bool {
+	return s.Type == S3 &&
+		(s.S3ServerSideEncryption != nil || s.S3StorageClass != nil || s.S3ObjectLockMode != nil)
+}
+
+// IsStagedS3 returns true if the backup files of the S3 storage are staged locally,
+// as the shared library supports neither the object options nor the explicit credentials.
+func (s *Storage) IsStagedS3() bool {
+	return s.HasS3ObjectOptions() || s.Type == S3 && s.S3Credentials != nil
+}
+
+// validateAzure validates the Azure Blob storage configuration.
+func (s *Storage) validateAzure() error {
+	if s.AzureAccountName == nil || len(*s.AzureAccountName) == 0 {
+		return errors.New("azure account name is not specified")
+	}
+	if s.AzureContainerName == nil || len(*s.AzureContainerName) == 0 {
+		return errors.New("azure container name is not specified")
+	}
+	for _, secret := range []struct {
+		name      string
+		env, file *string
+	}{
+		{"azure sas token", s.AzureSasTokenEnv, s.AzureSasTokenFile},
+		{"azure account key", s.AzureAccountKeyEnv, s.AzureAccountKeyFile},
+		{"azure client secret", s.AzureClientSecretEnv, s.AzureClientSecretFile},
+	} {
+		if err := validateSecret(secret.name, secret.env, secret.file); err != nil {
+			return err
+		}
+	}
+	sasToken := isSecretSet(s.AzureSasTokenEnv, s.AzureSasTokenFile)
+	accountKey := isSecretSet(s.AzureAccountKeyEnv, s.AzureAccountKeyFile)
+	clientSecret := s.AzureTenantID != nil || s.AzureClientID != nil ||
+		isSecretSet(s.AzureClientSecretEnv, s.AzureClientSecretFile)
+	if clientSecret && (s.AzureTenantID == nil || s.AzureClientID == nil ||
+		!isSecretSet(s.AzureClientSecretEnv, s.AzureClientSecretFile)) {
+		return errors.New("azure tenant id, client id and client secret should be specified together")
+	}
+	authMethods := 0
+	for _, isSet := range []bool{sasToken, accountKey, clientSecret} {
+		if isSet {
+			authMethods++
+		}
+	}
+	if authMethods > 1 {
+		return errors.New("only one of azure sas token, account key or client secret should be specified")
+	}
+	return nil
+}
+
+// isSecretSet returns true if the secret is read from an environment variable or a file.
+func isSecretSet(env, file *string) bool {
+	return env != nil || file != nil
+}
+
+// validateSecret returns an error if the secret is read both from an environment
+// variable and a file. The secrets are never stored in the configuration itself.
+func validateSecret(name string, env, file *string) error {
+	if env != nil && file != nil {
+		return fmt.Errorf("%s should be read either from an environment variable or a file", name)
+	}
+	return nil
+}
+
+// validateSftp validates the SFTP storage configuration.
+func (s *Storage) validateSftp() error {
+	if s.SftpHost == nil || len(*s.SftpHost) == 0 {
+		return errors.New("sftp host is not specified")
+	}
+	if s.SftpPort != nil && (*s.SftpPort <= 0 || *s.SftpPort > 65535) {
+		return fmt.Errorf("sftp port %d invalid", *s.SftpPort)
+	}
+	if s.SftpUser == nil || len(*s.SftpUser) == 0 {
+		return errors.New("sftp user is not specified")
+	}
+	if err := validateSecret("sftp password", s.SftpPasswordEnv, s.SftpPasswordFile); err != nil {
+		return err
+	}
+	if s.SftpKeyFile == nil && !isSecretSet(s.SftpPasswordEnv, s.SftpPasswordFile) {
+		return errors.New("sftp key file or password is not specified")
+	}
+	if s.SftpKnownHostsFile == nil || len(*s.SftpKnownHostsFile) == 0 {
+		return errors.New("sftp known hosts file is not specified")
+	}
+	return nil
+}
+
+// GetSftpPortOrDefault returns the value of the SftpPort property.
+// If the property is not set, it returns the default value.
+func (s *Storage) GetSftpPortOrDefault() int {
+	if s.SftpPort != nil {
+		return *s.SftpPort
+	}
+	return *defaultConfig.storage.SftpPort
+}
+
 // SetDefaultProfile sets the "default" profile if not set.
 func (s *Storage) SetDefaultProfile() {
 	if s.Type == S3 && s.S3Profile == nil {
@@ -0,0 +1,31 @@
+package model
+
+// StorageCheck is the result of the connectivity and permission check of a storage.
+// @Description StorageCheck is the result of the connectivity and permission check of a storage.
+type StorageCheck struct {
+	// Whether all the steps of the check succeeded.
+	Success bool `yaml:"success" json:"success" example:"true"`
+	// The steps of the check in the order they were run.
+	// The check stops at the first failed step, apart from the cleanup.
+	Steps []StorageCheckStep `yaml:"steps" json:"steps"`
+}
+
+// StorageCheckStep is a step of the storage check.
+// @Description StorageCheckStep is a step of the storage check.
+type StorageCheckStep struct {
+	// The name of the step.
+	Name string `yaml:"name" json:"name" enums:"connect,write,read,list,delete" example:"write"`
+	// The duration of the step in milliseconds.
+	DurationMillis int64 `yaml:"duration-millis" json:"duration-millis" example:"20"`
+	// The error of the failed step, such as a permission error.
+	Error string `yaml:"error,omitempty" json:"error,omitempty" example:"access denied"`
+}
+
+// Storage check step names.
+const (
+	StorageCheckConnect = "connect"
+	StorageCheckWrite   = "write"
+	StorageCheckRead    = "read"
+	StorageCheckList    = "list"
+	StorageCheckDelete  = "delete"
+)
@@ -0,0 +1,72 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+)
+
+// QuotaAction represents the action taken when a full backup would exceed the storage quota.
+// @Description QuotaAction represents the action taken when a full backup would exceed the storage quota.
+type QuotaAction string
+
+const (
+	// QuotaActionPrune prunes the oldest backups of the routine to make room for the full backup.
+	QuotaActionPrune QuotaAction = "prune"
+	// QuotaActionSkip skips the full backup.
+	QuotaActionSkip QuotaAction = "skip"
+	// QuotaActionWarn logs a warning and runs the full backup.
+	QuotaActionWarn QuotaAction = "warn"
+)
+
+// StorageQuota represents the capacity quota of a storage.
+// The usage is estimated from the size of the backups of all the routines using the storage.
+// @Description StorageQuota represents the capacity quota of a storage.
+//
+//nolint:lll
+type StorageQuota struct {
+	// The maximum total size of the backups in the storage in bytes.
+	MaxBytes *int64 `yaml:"max-bytes,omitempty" json:"max-bytes,omitempty" example:"1099511627776" validate:"required"`
+	// The action taken when a full backup would exceed the quota (default: warn).
+	Action *QuotaAction `yaml:"action,omitempty" json:"action,omitempty" enums:"prune,skip,warn" default:"warn"`
+}
+
+// GetActionOrDefault returns the value of the Action property.
+// If the property is not set, it returns the default value.
+func (q *StorageQuota) GetActionOrDefault() QuotaAction {
+	if q.Action != nil {
+		return *q.Action
+	}
+	return *defaultConfig.storageQuota.Action
+}
+
+// Validate validates the storage quota.
+func (q *StorageQuota) Validate() error {
+	if q == nil {
+		return nil
+	}
+	if q.MaxBytes == nil {
+		return errors.New("quota maxBytes is not specified")
+	}
+	if *q.MaxBytes <= 0 {
+		return fmt.Errorf("quota maxBytes %d invalid, should be positive number", *q.MaxBytes)
+	}
+	if q.Action != nil {
+		switch *q.Action {
+		case QuotaActionPrune, QuotaActionSkip, QuotaActionWarn:
+		default:
+			return fmt.Errorf("invalid quota action: %s. Possible values: prune, skip, warn", *q.Action)
+		}
+	}
+	return nil
+}
+
+// StorageUsage represents the estimated usage of a storage.
+// @Description StorageUsage represents the estimated usage of a storage.
+type StorageUsage struct {
+	// The total size of the backups in the storage in bytes.
+	UsedBytes uint64 `yaml:"used-bytes" json:"used-bytes" format:"int64" example:"2000"`
+	// The size of the backups in bytes by routine.
+	Routines map[string]uint64 `yaml:"routines" json:"routines"`
+	// The storage quota, if configured.
+	Quota *StorageQuota `yaml:"quota,omitempty" json:"quota,omitempty"`
+}
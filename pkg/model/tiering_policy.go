@@ -0,0 +1,34 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+)
+
+// TieringPolicy defines when the backups of a routine are moved to a colder storage.
+// A full backup is moved together with its incremental backups, once the next
+// full backup exists. The latest full backup is never moved.
+// @Description TieringPolicy defines when the backups of a routine are moved to a colder storage.
+type TieringPolicy struct {
+	// Move full backups and their incremental backups older than the given number of days.
+	AfterDays *int `yaml:"after-days,omitempty" json:"after-days,omitempty" example:"14"`
+	// The name of the storage to move the backups to.
+	Storage *string `yaml:"storage,omitempty" json:"storage,omitempty" example:"s3-archive"`
+}
+
+// Validate validates the tiering policy.
+func (t *TieringPolicy) Validate() error {
+	if t == nil {
+		return nil
+	}
+	if t.AfterDays == nil {
+		return errors.New("tiering after-days is not specified")
+	}
+	if *t.AfterDays <= 0 {
+		return fmt.Errorf("tiering afterDays %d invalid, should be positive number", *t.AfterDays)
+	}
+	if t.Storage == nil || *t.Storage == "" {
+		return errors.New("tiering storage is not specified")
+	}
+	return nil
+}
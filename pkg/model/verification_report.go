@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// VerificationReport is the result of a backup verification job.
+// @Description VerificationReport is the result of a backup verification job.
+type VerificationReport struct {
+	// The backup routine name.
+	Routine string `yaml:"routine" json:"routine" example:"daily"`
+	// The status of the verification job.
+	Status JobStatus `yaml:"status" json:"status" enums:"Running,Done,Failed"`
+	// The start time of the verification in the ISO 8601 format.
+	Started time.Time `yaml:"started" json:"started" example:"2023-03-20T14:50:00Z"`
+	// The end time of the verification in the ISO 8601 format.
+	Finished *time.Time `yaml:"finished,omitempty" json:"finished,omitempty" example:"2023-03-20T14:55:00Z"`
+	// Whether all the verified backups are intact.
+	Success bool `yaml:"success" json:"success"`
+	// The number of verified backups, one per namespace.
+	BackupCount int `yaml:"backup-count" json:"backup-count" example:"2"`
+	// The number of verified files.
+	FileCount int `yaml:"file-count" json:"file-count" example:"10"`
+	// The verification failures.
+	Failures []VerificationFailure `yaml:"failures" json:"failures"`
+	// The error that prevented the verification, if any.
+	Error string `yaml:"error,omitempty" json:"error,omitempty"`
+}
+
+// VerificationFailure is a failed check of a backup.
+// @Description VerificationFailure is a failed check of a backup.
+type VerificationFailure struct {
+	// The path to the backup.
+	Path string `yaml:"path" json:"path" example:"storage/daily/backup/1707915600000/data/source-ns1"`
+	// The path of the file relative to the backup folder, if the failure concerns a file.
+	File string `yaml:"file,omitempty" json:"file,omitempty" example:"data/source-ns1/source-ns1_1.asb"`
+	// The reason of the failure.
+	Reason string `yaml:"reason" json:"reason" example:"checksum mismatch"`
+}
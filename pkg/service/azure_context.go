@@ -0,0 +1,328 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+	"github.com/aerospike/backup/pkg/model"
+	"github.com/aerospike/backup/pkg/shared"
+	"github.com/aerospike/backup/pkg/util"
+	"gopkg.in/yaml.v3"
+)
+
+const azureProtocol = "azure://"
+
+// AzureContext is responsible for performing basic operations on Azure Blob Storage.
+// The shared library cannot write to Azure Blob directly, so the backup files are
+// staged in a local folder and uploaded once the backup is completed.
+type AzureContext struct {
+	*localStaging
+	ctx           context.Context
+	client        *azblob.Client
+	containerName string
+	path          string
+	metadataCache *util.LoadingCache[string, *model.BackupMetadata]
+}
+
+var _ StorageAccessor = (*AzureContext)(nil)
+var _ stagedStorage = (*AzureContext)(nil)
+
+// NewAzureContext returns a new AzureContext.
+func NewAzureContext(config *model.Storage) (*AzureContext, error) {
+	ctx := context.TODO()
+	client, err := newAzureClient(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Azure Blob client: %v", err)
+	}
+
+	containerName := *config.AzureContainerName
+	// Check if the container exists
+	_, err = client.ServiceClient().NewContainerClient(containerName).GetProperties(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error checking Azure container %s existence: %v", containerName, err)
+	}
+
+	a := &AzureContext{
+		ctx:           ctx,
+		client:        client,
+		containerName: containerName,
+		path:          strings.Trim(*config.Path, "/"),
+	}
+	a.localStaging = newLocalStaging(a, "azure-"+*config.AzureAccountName+"-"+containerName)
+	a.metadataCache = util.NewLoadingCache(ctx, func(path string) (*model.BackupMetadata, error) {
+		return a.readMetadata(path)
+	})
+	return a, nil
+}
+
+// newAzureClient creates the client with the configured authentication method.
+func newAzureClient(config *model.Storage) (*azblob.Client, error) {
+	serviceURL := fmt.Sprintf("https://%s.blob.core.windows.net/", *config.AzureAccountName)
+	if config.AzureEndpointOverride != nil && *config.AzureEndpointOverride != "" {
+		serviceURL = strings.TrimSuffix(*config.AzureEndpointOverride, "/") + "/"
+	}
+	switch {
+	case config.AzureSasTokenEnv != nil || config.AzureSasTokenFile != nil:
+		sasToken, err := shared.ReadSecret(config.AzureSasTokenEnv, config.AzureSasTokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read azure sas token: %w", err)
+		}
+		return azblob.NewClientWithNoCredential(serviceURL+"?"+strings.TrimPrefix(sasToken, "?"), nil)
+	case config.AzureAccountKeyEnv != nil || config.AzureAccountKeyFile != nil:
+		accountKey, err := shared.ReadSecret(config.AzureAccountKeyEnv, config.AzureAccountKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read azure account key: %w", err)
+		}
+		credential, err := azblob.NewSharedKeyCredential(*config.AzureAccountName, accountKey)
+		if err != nil {
+			return nil, err
+		}
+		return azblob.NewClientWithSharedKeyCredential(serviceURL, credential, nil)
+	case config.AzureClientSecretEnv != nil || config.AzureClientSecretFile != nil:
+		clientSecret, err := shared.ReadSecret(config.AzureClientSecretEnv, config.AzureClientSecretFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read azure client secret: %w", err)
+		}
+		credential, err := azidentity.NewClientSecretCredential(
+			*config.AzureTenantID, *config.AzureClientID, clientSecret, nil)
+		if err != nil {
+			return nil, err
+		}
+		return azblob.NewClient(serviceURL, credential, nil)
+	default:
+		credential, err := azidentity.NewDefaultAzureCredential(nil)
+		if err != nil {
+			return nil, err
+		}
+		return azblob.NewClient(serviceURL, credential, nil)
+	}
+}
+
+func (a *AzureContext) readBackupState(stateFilePath string, state *model.BackupState) error {
+	err := a.readFile(stateFilePath, state)
+	if bloberror.HasCode(err, bloberror.BlobNotFound) {
+		slog.Debug("State file does not exist for backup", "path", stateFilePath)
+		return nil
+	}
+	return err
+}
+
+func (a *AzureContext) readBackupDetails(path string, useCache bool) (model.BackupDetails, error) {
+	var metadata *model.BackupMetadata
+	var err error
+	if useCache {
+		metadata, err = a.metadataCache.Get(path)
+	} else {
+		metadata, err = a.readMetadata(path)
+	}
+	if err != nil {
+		return model.BackupDetails{}, err
+	}
+	return model.BackupDetails{
+		BackupMetadata: *metadata,
+		Key:            util.Ptr(azureProtocol + a.containerName + "/" + path),
+	}, nil
+}
+
+func (a *AzureContext) read(filePath string) ([]byte, error) {
+	response, err := a.client.DownloadStream(a.ctx, a.containerName, filePath, nil)
+	if err != nil {
+		if !bloberror.HasCode(err, bloberror.BlobNotFound) {
+			slog.Warn("Failed to read file", "path", filePath, "err", err)
+		}
+		return nil, err
+	}
+	defer response.Body.Close()
+	content, err := io.ReadAll(response.Body)
+	if err != nil {
+		slog.Warn("Couldn't read object body of a file", "path", filePath, "err", err)
+		return nil, err
+	}
+	return content, nil
+}
+
+func (a *AzureContext) open(filePath string) (io.ReadCloser, error) {
+	response, err := a.client.DownloadStream(a.ctx, a.containerName, filePath, nil)
+	if err != nil {
+		return nil, err
+	}
+	return response.Body, nil
+}
+
+// create uploads the file in blocks, the blob is not committed if the writer
+// is closed with an error.
+func (a *AzureContext) create(filePath string) (streamWriter, error) {
+	return newPipeWriter(func(reader io.Reader) error {
+		_, err := a.client.UploadStream(a.ctx, a.containerName, filePath, reader, nil)
+		if err != nil {
+			slog.Warn("Couldn't upload file", "path", filePath,
+				"container", a.containerName, "err", err)
+			return err
+		}
+		a.fileWritten(filePath)
+		return nil
+	}), nil
+}
+
+// readFile reads and decodes the YAML content from the given filePath into v.
+func (a *AzureContext) readFile(filePath string, v any) error {
+	content, err := a.read(filePath)
+	if err != nil {
+		return err
+	}
+	if err = yaml.Unmarshal(content, v); err != nil {
+		slog.Warn("Failed unmarshal file", "path", filePath, "err", err,
+			"content", string(content))
+		return err
+	}
+	return nil
+}
+
+func (a *AzureContext) write(filePath string, data []byte) error {
+	_, err := a.client.UploadBuffer(a.ctx, a.containerName, filePath, data, nil)
+	if err != nil {
+		slog.Warn("Couldn't upload file", "path", filePath,
+			"container", a.containerName, "err", err)
+		return err
+	}
+	a.fileWritten(filePath)
+	return nil
+}
+
+// fileWritten invalidates the cached metadata of the written file.
+func (a *AzureContext) fileWritten(filePath string) {
+	if filepath.Base(filePath) == metadataFile {
+		a.metadataCache.Invalidate(filepath.Dir(filePath))
+	}
+	slog.Debug("File written", "path", filePath, "container", a.containerName)
+}
+
+func (a *AzureContext) fileSize(filePath string) (int64, error) {
+	properties, err := a.client.ServiceClient().NewContainerClient(a.containerName).
+		NewBlobClient(filePath).GetProperties(a.ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	if properties.ContentLength == nil {
+		return 0, nil
+	}
+	return *properties.ContentLength, nil
+}
+
+// lsFiles returns all files in the given Azure Blob prefix path, recursively.
+func (a *AzureContext) lsFiles(prefix string) ([]string, error) {
+	var result []string
+	pager := a.client.NewListBlobsFlatPager(a.containerName, &azblob.ListBlobsFlatOptions{
+		Prefix: util.Ptr(strings.TrimSuffix(prefix, "/") + "/"),
+	})
+	for pager.More() {
+		page, err := pager.NextPage(a.ctx)
+		if err != nil {
+			slog.Warn("Couldn't list objects in folder", "prefix", prefix, "err", err)
+			return nil, err
+		}
+		for _, blob := range page.Segment.BlobItems {
+			if blob.Name != nil {
+				result = append(result, *blob.Name)
+			}
+		}
+	}
+	return result, nil
+}
+
+// lsDir returns all subfolders in the given Azure Blob prefix path.
+func (a *AzureContext) lsDir(prefix string) ([]string, error) {
+	result := make([]string, 0)
+	pager := a.client.ServiceClient().NewContainerClient(a.containerName).NewListBlobsHierarchyPager("/",
+		&container.ListBlobsHierarchyOptions{
+			Prefix: util.Ptr(strings.TrimSuffix(prefix, "/") + "/"),
+		})
+	for pager.More() {
+		page, err := pager.NextPage(a.ctx)
+		if err != nil {
+			slog.Warn("Couldn't list objects in folder", "prefix", prefix, "err", err)
+			return nil, err
+		}
+		for _, blobPrefix := range page.Segment.BlobPrefixes {
+			if blobPrefix.Name != nil {
+				result = append(result, strings.TrimSuffix(*blobPrefix.Name, "/"))
+			}
+		}
+	}
+	return result, nil
+}
+
+// CreateFolder creates the local staging folder for the given path.
+// Azure Blob doesn't require to create folders.
+func (a *AzureContext) CreateFolder(path string) {
+	a.createStagingFolder(path)
+}
+
+func (a *AzureContext) readMetadata(path string) (*model.BackupMetadata, error) {
+	metadata := &model.BackupMetadata{}
+	metadataFilePath := filepath.Join(path, metadataFile)
+	err := a.readFile(metadataFilePath, metadata)
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("Read metadata file", "path", path, "data", metadata)
+	return metadata, nil
+}
+
+func (a *AzureContext) DeleteFolder(folder string) error {
+	slog.Debug("Delete folder", "path", folder)
+	a.removeStaged(folder)
+	files, err := a.lsFiles(folder)
+	if err != nil {
+		slog.Warn("Couldn't list files in directory", "path", folder, "err", err)
+		return err
+	}
+
+	for _, file := range files {
+		if err := a.deleteFile(file); err != nil {
+			slog.Debug("Couldn't delete file", "path", file, "err", err)
+		}
+	}
+	return nil
+}
+
+func (a *AzureContext) deleteFile(path string) error {
+	if _, err := a.client.DeleteBlob(a.ctx, a.containerName, path, nil); err != nil {
+		return err
+	}
+	if filepath.Base(path) == metadataFile {
+		a.metadataCache.Invalidate(filepath.Dir(path))
+	}
+	return nil
+}
+
+// wrapWithPrefix returns the local staging folder of the path,
+// which is uploaded to Azure Blob after the backup.
+func (a *AzureContext) wrapWithPrefix(path string) *string {
+	result := a.stagingFolder(path)
+	return &result
+}
+
+func (a *AzureContext) validateStorageContainsBackup() error {
+	files, err := a.lsFiles(a.path)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("given path %s not exist", a.path)
+	}
+	for _, file := range files {
+		if strings.HasSuffix(file, ".asb") {
+			return nil
+		}
+	}
+	return fmt.Errorf("no backup files found in %s", a.path)
+}
@@ -0,0 +1,112 @@
+//go:build !ci
+
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aerospike/backup/pkg/model"
+	"github.com/aws/smithy-go/ptr"
+	"gopkg.in/yaml.v3"
+)
+
+// well-known Azurite development storage account
+const (
+	azuriteAccountName = "devstoreaccount1"
+	azuriteAccountKey  = "Eby8vdM02xNOcqFlqUwJPLlmEtlCDXJ1OUzFT50uSRZ6IFsuFq2UVErCz4I6tq/K1SZFPTOtr/KBHBeksoGMGw=="
+	azuriteEndpoint    = "http://127.0.0.1:10000/devstoreaccount1"
+	azuriteContainer   = "as-backup-container"
+)
+
+// newAzuriteContext returns an AzureContext for the Azurite from docker-compose.
+func newAzuriteContext(t *testing.T) *AzureContext {
+	t.Helper()
+	t.Setenv("AZURITE_ACCOUNT_KEY", azuriteAccountKey)
+	storage := &model.Storage{
+		Type:                  model.AzureBlob,
+		Path:                  ptr.String("storageAzure"),
+		AzureAccountName:      ptr.String(azuriteAccountName),
+		AzureContainerName:    ptr.String(azuriteContainer),
+		AzureAccountKeyEnv:    ptr.String("AZURITE_ACCOUNT_KEY"),
+		AzureEndpointOverride: ptr.String(azuriteEndpoint),
+	}
+	client, err := newAzureClient(storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the container may already exist
+	_, _ = client.CreateContainer(context.Background(), azuriteContainer, nil)
+	azureContext, err := NewAzureContext(storage)
+	if err != nil {
+		t.Skip("Azurite is not available:", err)
+	}
+	t.Cleanup(func() {
+		_ = azureContext.DeleteFolder(azureContext.path)
+	})
+	return azureContext
+}
+
+func TestAzureContext_ReadWriteMetadata(t *testing.T) {
+	azureContext := newAzuriteContext(t)
+	path := filepath.Join(azureContext.path, "backup", "10", "data", "ns1")
+	metadataWrite := model.BackupMetadata{
+		Namespace: "testNS",
+		Created:   time.UnixMilli(10),
+	}
+	data, _ := yaml.Marshal(metadataWrite)
+	if err := azureContext.write(filepath.Join(path, metadataFile), data); err != nil {
+		t.Fatal(err)
+	}
+
+	details, err := azureContext.readBackupDetails(path, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if details.Namespace != metadataWrite.Namespace || !details.Created.Equal(metadataWrite.Created) {
+		t.Errorf("Expected %v, got %v", metadataWrite, details.BackupMetadata)
+	}
+	if *details.Key != "azure://"+azuriteContainer+"/"+path {
+		t.Errorf("Unexpected key %s", *details.Key)
+	}
+
+	dirs, _ := azureContext.lsDir(filepath.Join(azureContext.path, "backup"))
+	if len(dirs) != 1 || dirs[0] != filepath.Join(azureContext.path, "backup", "10") {
+		t.Errorf("Unexpected subfolders %v", dirs)
+	}
+
+	state := model.NewBackupState()
+	if err := azureContext.readBackupState(filepath.Join(azureContext.path, model.StateFileName), state); err != nil {
+		t.Errorf("Expected no error for missing state, got %v", err)
+	}
+}
+
+func TestAzureContext_UploadDownload(t *testing.T) {
+	azureContext := newAzuriteContext(t)
+	path := filepath.Join(azureContext.path, "backup", "data", "ns1")
+	_ = azureContext.write(filepath.Join(path, "old.asb"), []byte("old"))
+
+	azureContext.CreateFolder(path)
+	stagingFolder := *azureContext.wrapWithPrefix(path)
+	_ = os.WriteFile(filepath.Join(stagingFolder, "ns1_0.asb"), []byte("data"), 0644)
+	backend := &BackupBackend{StorageAccessor: azureContext}
+	if err := backend.completeStaged(path, nil); err != nil {
+		t.Fatal(err)
+	}
+	files, _ := azureContext.lsFiles(path)
+	if len(files) != 1 || files[0] != filepath.Join(path, "ns1_0.asb") {
+		t.Errorf("Expected the uploaded file only, got %v", files)
+	}
+
+	localDir := t.TempDir()
+	if err := azureContext.download(path, localDir); err != nil {
+		t.Fatal(err)
+	}
+	content, _ := os.ReadFile(filepath.Join(localDir, "ns1_0.asb"))
+	if string(content) != "data" {
+		t.Errorf("Expected downloaded content, got %s", content)
+	}
+}
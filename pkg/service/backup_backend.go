@@ -3,13 +3,16 @@ package service
 import (
 	"archive/zip"
 	"bytes"
+	"errors"
 	"fmt"
 	"log/slog"
 	"path/filepath"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/aerospike/backup/pkg/model"
+	"github.com/aerospike/backup/pkg/util"
 	"gopkg.in/yaml.v3"
 )
 
@@ -22,47 +25,115 @@ type BackupBackend struct {
 	stateFilePath          string
 	removeFullBackup       bool
 	fullBackupInProgress   *atomic.Bool // BackupBackend needs to know if full backup is running to filter it out
+	incrementalInProgress  atomic.Bool  // the folder of a running incremental backup is incomplete
 	stateFileMutex         sync.RWMutex
+	metadataMutex          sync.Mutex // serializes the metadata updates
+	storageName            string
+	tier                   *BackupBackend // the colder storage the aging backups are moved to, if any
+	signer                 *backupSigner  // signs the new backups and verifies the listed ones, if configured
+	manifests              manifestCache  // the manifests of the listed backups, to verify their signatures
 }
 
 var _ BackupListReader = (*BackupBackend)(nil)
 
 const metadataFile = "metadata.yaml"
 
-func newBackend(config *model.Config, routineName string) *BackupBackend {
+var (
+	// ErrBackupNotFound is returned when the requested backup does not exist.
+	ErrBackupNotFound = errors.New("backup not found")
+	// ErrBackupInUse is returned on an attempt to delete a full backup
+	// that newer incremental backups depend on.
+	ErrBackupInUse = errors.New("backup is required by incremental backups")
+	// ErrBackupHeld is returned on an attempt to delete a backup on hold.
+	ErrBackupHeld = errors.New("backup is on hold")
+	// ErrBackupLocked is returned on an attempt to delete a backup locked by the storage.
+	ErrBackupLocked = errors.New("backup is locked")
+)
+
+func newBackend(config *model.Config, routineName string, signer *backupSigner) *BackupBackend {
 	backupRoutine := config.BackupRoutines[routineName]
 	storage := config.Storage[backupRoutine.Storage]
 	backupPolicy := config.BackupPolicies[backupRoutine.BackupPolicy]
 	removeFullBackup := backupPolicy.RemoveFiles.RemoveFullBackup()
-	switch storage.Type {
-	case model.Local:
-		routinePath := filepath.Join(*storage.Path, routineName)
-		return &BackupBackend{
-			StorageAccessor:        NewOSDiskAccessor(),
-			fullBackupsPath:        filepath.Join(routinePath, model.FullBackupDirectory),
-			incrementalBackupsPath: filepath.Join(routinePath, model.IncrementalBackupDirectory),
-			stateFilePath:          filepath.Join(routinePath, model.StateFileName),
-			removeFullBackup:       removeFullBackup,
-			fullBackupInProgress:   &atomic.Bool{},
-		}
-	case model.S3:
-		s3Context, err := NewS3Context(storage)
+	backend, err := newStorageBackend(storage, routineName, removeFullBackup)
+	if err != nil {
+		panic(err)
+	}
+	backend.storageName = backupRoutine.Storage
+	backend.signer = signer
+	if backupPolicy.Tiering != nil {
+		tier, err := newTierBackend(config, backupPolicy.Tiering, routineName, removeFullBackup)
 		if err != nil {
-			panic(err)
+			// the backups stay in the storage until the tiering storage is reachable
+			slog.Error("Tiering is skipped", "routine", routineName, "err", err)
+		} else {
+			tier.signer = backend.signer
+			backend.tier = tier
 		}
+	}
+	return backend
+}
 
-		routinePath := filepath.Join(s3Context.path, routineName)
-		return &BackupBackend{
-			StorageAccessor:        s3Context,
-			fullBackupsPath:        filepath.Join(routinePath, model.FullBackupDirectory),
-			incrementalBackupsPath: filepath.Join(routinePath, model.IncrementalBackupDirectory),
-			stateFilePath:          filepath.Join(routinePath, model.StateFileName),
-			removeFullBackup:       removeFullBackup,
-			fullBackupInProgress:   &atomic.Bool{},
-		}
-	default:
-		panic(fmt.Sprintf("Unsupported storage type: %v", storage.Type))
+// newTierBackend returns the BackupBackend of the routine backups in the tiering storage.
+func newTierBackend(config *model.Config, tiering *model.TieringPolicy, routineName string,
+	removeFullBackup bool) (*BackupBackend, error) {
+	tierStorage := *tiering.Storage
+	tier, err := newStorageBackend(config.Storage[tierStorage], routineName, removeFullBackup)
+	if err != nil {
+		return nil, fmt.Errorf("cannot access tiering storage %s: %w", tierStorage, err)
+	}
+	tier.storageName = tierStorage
+	return tier, nil
+}
+
+// newStorageBackend returns a new BackupBackend for the routine backups in the storage.
+func newStorageBackend(storage *model.Storage, routineName string,
+	removeFullBackup bool) (*BackupBackend, error) {
+	accessor, rootPath, err := newStorageAccessor(storage)
+	if err != nil {
+		return nil, err
 	}
+
+	routinePath := filepath.Join(rootPath, routineName)
+	return &BackupBackend{
+		StorageAccessor:        accessor,
+		fullBackupsPath:        filepath.Join(routinePath, model.FullBackupDirectory),
+		incrementalBackupsPath: filepath.Join(routinePath, model.IncrementalBackupDirectory),
+		stateFilePath:          filepath.Join(routinePath, model.StateFileName),
+		removeFullBackup:       removeFullBackup,
+		fullBackupInProgress:   &atomic.Bool{},
+	}, nil
+}
+
+// completeStaged uploads the backup files staged locally for the storage types
+// not supported by the shared library, if the backup succeeded.
+// It returns the backup error as is for other storage types.
+func (b *BackupBackend) completeStaged(path string, backupErr error) error {
+	staged, ok := b.StorageAccessor.(stagedStorage)
+	if !ok {
+		return backupErr
+	}
+	defer staged.removeStaged(path)
+	if backupErr != nil {
+		return backupErr
+	}
+	if err := staged.upload(path); err != nil {
+		return fmt.Errorf("failed to upload backup files: %w", err)
+	}
+	return nil
+}
+
+// discardStaged removes the local staging folder of a folder written directly
+// to the storage.
+func (b *BackupBackend) discardStaged(path string) {
+	if staged, ok := b.StorageAccessor.(stagedStorage); ok {
+		staged.removeStaged(path)
+	}
+}
+
+// routinePath returns the root folder of the routine backups.
+func (b *BackupBackend) routinePath() string {
+	return filepath.Dir(b.fullBackupsPath)
 }
 
 func (b *BackupBackend) readState() *model.BackupState {
@@ -79,14 +150,39 @@ func (b *BackupBackend) readState() *model.BackupState {
 func (b *BackupBackend) writeState(state *model.BackupState) error {
 	b.stateFileMutex.Lock()
 	defer b.stateFileMutex.Unlock()
+	// the empty incrementals map may be updated concurrently
+	state.Lock()
+	defer state.Unlock()
 	return b.writeYaml(b.stateFilePath, state)
 }
 
+// writeBackupMetadata writes the metadata of the backup, recording the time
+// the backup objects are locked until by the storage.
 func (b *BackupBackend) writeBackupMetadata(path string, metadata model.BackupMetadata) error {
+	metadata.LockedUntil = nil
+	if locked, ok := b.StorageAccessor.(lockedStorage); ok {
+		metadata.LockedUntil = locked.lockedUntil(time.Now())
+	}
 	metadataFilePath := filepath.Join(path, metadataFile)
 	return b.writeYaml(metadataFilePath, metadata)
 }
 
+// rewriteBackupMetadata writes the updated metadata of the backup, keeping the time
+// the backup objects were locked until when the backup was written, so that the
+// metadata is not locked longer than the backup files.
+func (b *BackupBackend) rewriteBackupMetadata(path string, metadata model.BackupMetadata) error {
+	metadataFilePath := filepath.Join(path, metadataFile)
+	locked, ok := b.StorageAccessor.(lockedStorage)
+	if !ok {
+		return b.writeYaml(metadataFilePath, metadata)
+	}
+	data, err := yaml.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+	return locked.writeLockedUntil(metadataFilePath, data, metadata.LockedUntil)
+}
+
 func (b *BackupBackend) writeYaml(path string, data any) error {
 	dataYaml, err := yaml.Marshal(data)
 	if err != nil {
@@ -96,8 +192,19 @@ func (b *BackupBackend) writeYaml(path string, data any) error {
 	return b.write(path, dataYaml)
 }
 
-// FullBackupList returns a list of available full backups.
+// FullBackupList returns a list of available full backups,
+// including the ones moved to the tiering storage.
 func (b *BackupBackend) FullBackupList(timebounds *model.TimeBounds) ([]model.BackupDetails, error) {
+	backups, err := b.fullBackupList(timebounds)
+	if err != nil || b.tier == nil {
+		return backups, err
+	}
+	tiered, err := b.tier.fullBackupList(timebounds)
+	return b.withTiered(backups, tiered, err), nil
+}
+
+// fullBackupList returns a list of the full backups located in the storage.
+func (b *BackupBackend) fullBackupList(timebounds *model.TimeBounds) ([]model.BackupDetails, error) {
 	slog.Info("Get full backups", "backupFolder", b.fullBackupsPath,
 		"timebounds", timebounds, "removeFullBackup", b.removeFullBackup)
 
@@ -126,6 +233,15 @@ func (b *BackupBackend) detailsFromPaths(timebounds *model.TimeBounds, useCache
 				continue
 			}
 			if timebounds.Contains(details.Created.UnixMilli()) {
+				if b.storageName != "" {
+					details.Storage = &b.storageName
+				}
+				// the manifest is only included in the details of a single backup
+				if b.signer != nil {
+					valid := b.signer.verify(details.BackupMetadata,
+						b.manifests.get(namespacePath, details.Created, b.readManifest))
+					details.SignatureValid = &valid
+				}
 				backupDetails = append(backupDetails, details)
 			}
 		}
@@ -142,17 +258,309 @@ func (b *BackupBackend) fromSubfolders(timebounds *model.TimeBounds,
 	return b.detailsFromPaths(timebounds, true, subfolders...), nil
 }
 
-// IncrementalBackupList returns a list of available incremental backups.
+// IncrementalBackupList returns a list of available incremental backups,
+// including the ones moved to the tiering storage.
 func (b *BackupBackend) IncrementalBackupList(timebounds *model.TimeBounds) ([]model.BackupDetails, error) {
+	backups, err := b.incrementalBackupList(timebounds)
+	if err != nil || b.tier == nil {
+		return backups, err
+	}
+	tiered, err := b.tier.incrementalBackupList(timebounds)
+	return b.withTiered(backups, tiered, err), nil
+}
+
+// incrementalBackupList returns a list of the incremental backups located in the storage.
+func (b *BackupBackend) incrementalBackupList(timebounds *model.TimeBounds) ([]model.BackupDetails, error) {
 	return b.fromSubfolders(timebounds, b.incrementalBackupsPath)
 }
 
+// withTiered appends the tiered backups to the given ones.
+// The backups being moved are listed only once, from the storage they are moved from.
+func (b *BackupBackend) withTiered(backups []model.BackupDetails,
+	tiered []model.BackupDetails, err error) []model.BackupDetails {
+	if err != nil {
+		slog.Warn("Cannot read tiered backups", "storage", b.tier.storageName, "err", err)
+		return backups
+	}
+	type backupID struct {
+		created   int64
+		namespace string
+	}
+	listed := make(map[backupID]bool, len(backups))
+	for _, backup := range backups {
+		listed[backupID{backup.Created.UnixMilli(), backup.Namespace}] = true
+	}
+	for _, backup := range tiered {
+		if !listed[backupID{backup.Created.UnixMilli(), backup.Namespace}] {
+			backups = append(backups, backup)
+		}
+	}
+	return backups
+}
+
+// storageBackend returns the backend of the storage the backup is located in.
+func (b *BackupBackend) storageBackend(backup model.BackupDetails) *BackupBackend {
+	if b.tier != nil && backup.Storage != nil && *backup.Storage == b.tier.storageName {
+		return b.tier
+	}
+	return b
+}
+
+// PrunePreview evaluates the retention policy against the existing backups
+// without deleting anything.
+func (b *BackupBackend) PrunePreview(policy *model.RetentionPolicy, now time.Time) (*model.PrunePreview, error) {
+	allTime := &model.TimeBounds{}
+	fullBackups, err := b.FullBackupList(allTime)
+	if err != nil {
+		return nil, err
+	}
+	incrementalBackups, err := b.IncrementalBackupList(allTime)
+	if err != nil {
+		return nil, err
+	}
+	return evaluateRetention(policy, fullBackups, incrementalBackups, now), nil
+}
+
+// DeleteFullBackup deletes the full backup created at the given time (epoch millis).
+// A full backup that newer incremental backups depend on is deleted only if force is true.
+func (b *BackupBackend) DeleteFullBackup(timestamp int64, force bool) error {
+	allTime := &model.TimeBounds{}
+	fullBackups, err := b.FullBackupList(allTime)
+	if err != nil {
+		return err
+	}
+	incrementalBackups, err := b.IncrementalBackupList(allTime)
+	if err != nil {
+		return err
+	}
+	chains, _ := buildBackupChains(fullBackups, incrementalBackups)
+	for _, chain := range chains {
+		if chain.created.UnixMilli() != timestamp {
+			continue
+		}
+		if held := heldBackups(chain.full, chain.incrementals); held > 0 {
+			return fmt.Errorf("%w: %d backups of the chain are on hold", ErrBackupHeld, held)
+		}
+		if lockedUntil := latestLock(time.Now(), chain.full); lockedUntil != nil {
+			return fmt.Errorf("%w: full backup %d is locked until %s",
+				ErrBackupLocked, timestamp, lockedUntil.Format(time.RFC3339))
+		}
+		if len(chain.incrementals) > 0 {
+			if !force {
+				return fmt.Errorf("%w: %d incremental backups found", ErrBackupInUse, len(chain.incrementals))
+			}
+			slog.Warn("Force delete full backup required by incremental backups",
+				"timestamp", timestamp, "incrementalBackups", len(chain.incrementals))
+		}
+		return b.storageBackend(chain.full[0]).deleteFullBackup(chain.created)
+	}
+	return fmt.Errorf("%w: full backup %d", ErrBackupNotFound, timestamp)
+}
+
+// DeleteIncrementalBackup deletes the incremental backup created at the given time (epoch millis).
+func (b *BackupBackend) DeleteIncrementalBackup(timestamp int64) error {
+	bounds, err := model.NewTimeBounds(&timestamp, util.Ptr(timestamp+1))
+	if err != nil {
+		return err
+	}
+	incrementalBackups, err := b.IncrementalBackupList(bounds)
+	if err != nil {
+		return err
+	}
+	if len(incrementalBackups) == 0 {
+		return fmt.Errorf("%w: incremental backup %d", ErrBackupNotFound, timestamp)
+	}
+	if heldBackups(incrementalBackups) > 0 {
+		return fmt.Errorf("%w: incremental backup %d", ErrBackupHeld, timestamp)
+	}
+	if lockedUntil := latestLock(time.Now(), incrementalBackups); lockedUntil != nil {
+		return fmt.Errorf("%w: incremental backup %d is locked until %s",
+			ErrBackupLocked, timestamp, lockedUntil.Format(time.RFC3339))
+	}
+	return b.storageBackend(incrementalBackups[0]).deleteIncrementalBackup(incrementalBackups[0].Created)
+}
+
+// PlaceHold places a legal hold on all the namespaces of the backup created
+// at the given time (epoch millis).
+func (b *BackupBackend) PlaceHold(timestamp int64, isFullBackup bool, hold model.BackupHold) error {
+	return b.updateBackupMetadata(timestamp, isFullBackup, func(metadata *model.BackupMetadata) {
+		metadata.Hold = &hold
+	})
+}
+
+// ReleaseHold releases the legal hold of the backup created at the given time (epoch millis).
+// The released hold is kept in the hold history of the backup, with the actor who released it.
+func (b *BackupBackend) ReleaseHold(timestamp int64, isFullBackup bool, actor string) error {
+	released := time.Now()
+	return b.updateBackupMetadata(timestamp, isFullBackup, func(metadata *model.BackupMetadata) {
+		if metadata.Hold == nil {
+			return
+		}
+		hold := *metadata.Hold
+		hold.ReleasedBy = actor
+		hold.Released = &released
+		metadata.HoldHistory = append(metadata.HoldHistory, hold)
+		metadata.Hold = nil
+	})
+}
+
+// updateBackupMetadata applies the update to the metadata of each namespace
+// of the backup created at the given time (epoch millis), wherever it is located.
+func (b *BackupBackend) updateBackupMetadata(timestamp int64, isFullBackup bool,
+	update func(*model.BackupMetadata)) error {
+	err := b.updateStoredBackupMetadata(timestamp, isFullBackup, update)
+	if errors.Is(err, ErrBackupNotFound) && b.tier != nil {
+		return b.tier.updateStoredBackupMetadata(timestamp, isFullBackup, update)
+	}
+	return err
+}
+
+// updateStoredBackupMetadata applies the update to the metadata of each namespace
+// of the backup created at the given time (epoch millis) located in the storage.
+func (b *BackupBackend) updateStoredBackupMetadata(timestamp int64, isFullBackup bool,
+	update func(*model.BackupMetadata)) error {
+	namespaces, err := b.lsDir(filepath.Join(b.backupPath(timestamp, isFullBackup), model.DataDirectory))
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, namespacePath := range namespaces {
+		err := b.updateMetadata(namespacePath, func(metadata *model.BackupMetadata) bool {
+			if metadata.Created.UnixMilli() != timestamp {
+				return false
+			}
+			update(metadata)
+			found = true
+			return true
+		})
+		if err != nil && !errors.Is(err, ErrBackupNotFound) {
+			return err
+		}
+	}
+	if !found {
+		return fmt.Errorf("%w: backup %d", ErrBackupNotFound, timestamp)
+	}
+	return nil
+}
+
+// backupPath returns the folder of the backup created at the given time (epoch millis).
+func (b *BackupBackend) backupPath(timestamp int64, isFullBackup bool) string {
+	if !isFullBackup {
+		return filepath.Join(b.incrementalBackupsPath, timeSuffix(time.UnixMilli(timestamp)))
+	}
+	if b.removeFullBackup {
+		return b.fullBackupsPath
+	}
+	return filepath.Join(b.fullBackupsPath, timeSuffix(time.UnixMilli(timestamp)))
+}
+
+// BackupDetails returns the details of each namespace of the backup created at the
+// given time (epoch millis), wherever it is located, including the backup manifests.
+func (b *BackupBackend) BackupDetails(timestamp int64, isFullBackup bool) ([]model.BackupDetails, error) {
+	backups, err := b.storedBackupDetails(timestamp, isFullBackup)
+	if errors.Is(err, ErrBackupNotFound) && b.tier != nil {
+		return b.tier.storedBackupDetails(timestamp, isFullBackup)
+	}
+	return backups, err
+}
+
+// storedBackupDetails returns the details of each namespace of the backup created at
+// the given time (epoch millis) located in the storage.
+func (b *BackupBackend) storedBackupDetails(timestamp int64, isFullBackup bool) ([]model.BackupDetails, error) {
+	namespaces, err := b.lsDir(filepath.Join(b.backupPath(timestamp, isFullBackup), model.DataDirectory))
+	if err != nil {
+		return nil, err
+	}
+	var backups []model.BackupDetails
+	for _, namespacePath := range namespaces {
+		details, err := b.readBackupDetails(namespacePath, true)
+		if err != nil || details.Created.UnixMilli() != timestamp {
+			continue
+		}
+		if b.storageName != "" {
+			details.Storage = &b.storageName
+		}
+		// the backups created before the manifests were introduced have none
+		if manifest, err := b.readManifest(namespacePath); err == nil {
+			details.Manifest = manifest
+		}
+		details.SignatureValid = b.signatureValid(details)
+		backups = append(backups, details)
+	}
+	if len(backups) == 0 {
+		return nil, fmt.Errorf("%w: backup %d", ErrBackupNotFound, timestamp)
+	}
+	return backups, nil
+}
+
+// updateMetadata updates the metadata of the backup at the given path.
+// The update function returns false if the metadata should not be written.
+func (b *BackupBackend) updateMetadata(path string, update func(*model.BackupMetadata) bool) error {
+	b.metadataMutex.Lock()
+	defer b.metadataMutex.Unlock()
+	details, err := b.readBackupDetails(path, false)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrBackupNotFound, path)
+	}
+	if !update(&details.BackupMetadata) {
+		return nil
+	}
+	return b.rewriteBackupMetadata(path, details.BackupMetadata)
+}
+
+// heldBackups returns the number of the given backups on hold.
+func heldBackups(backupLists ...[]model.BackupDetails) int {
+	held := 0
+	for _, backups := range backupLists {
+		for _, backup := range backups {
+			if backup.IsHeld() {
+				held++
+			}
+		}
+	}
+	return held
+}
+
+// latestLock returns the latest time the given backups are locked until,
+// or nil if none of them is locked at the given time.
+func latestLock(now time.Time, backupLists ...[]model.BackupDetails) *time.Time {
+	var latest *time.Time
+	for _, backups := range backupLists {
+		for _, backup := range backups {
+			if backup.IsLocked(now) && (latest == nil || backup.LockedUntil.After(*latest)) {
+				latest = backup.LockedUntil
+			}
+		}
+	}
+	return latest
+}
+
+// deleteFullBackup removes the full backup folder created at the given time,
+// including the backed up cluster configuration.
+func (b *BackupBackend) deleteFullBackup(created time.Time) error {
+	path := b.backupPath(created.UnixMilli(), true)
+	b.manifests.remove(path)
+	return b.DeleteFolder(path)
+}
+
+// deleteIncrementalBackup removes the incremental backup folder created at the given time.
+func (b *BackupBackend) deleteIncrementalBackup(created time.Time) error {
+	path := b.backupPath(created.UnixMilli(), false)
+	b.manifests.remove(path)
+	return b.DeleteFolder(path)
+}
+
 func (b *BackupBackend) FullBackupInProgress() *atomic.Bool {
 	return b.fullBackupInProgress
 }
 
+// ReadClusterConfiguration returns the cluster configuration backed up in the path,
+// looking into the tiering storage if it is not found in the storage.
 func (b *BackupBackend) ReadClusterConfiguration(path string) ([]byte, error) {
 	configBackups, err := b.lsFiles(path)
+	if err == nil && len(configBackups) == 0 && b.tier != nil {
+		return b.tier.ReadClusterConfiguration(path)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -1,7 +1,9 @@
 package service
 
 import (
+	"errors"
 	"os"
+	"path/filepath"
 	"strconv"
 	"sync/atomic"
 	"testing"
@@ -12,6 +14,44 @@ import (
 
 const tempFolder = "./tmp"
 
+// newDiskBackend returns a BackupBackend of the accessor, with the backups in a temporary folder.
+func newDiskBackend(t *testing.T, accessor StorageAccessor) *BackupBackend {
+	routinePath := filepath.Join(t.TempDir(), "routine")
+	return &BackupBackend{
+		StorageAccessor:        accessor,
+		fullBackupsPath:        filepath.Join(routinePath, model.FullBackupDirectory),
+		incrementalBackupsPath: filepath.Join(routinePath, model.IncrementalBackupDirectory),
+		fullBackupInProgress:   &atomic.Bool{},
+	}
+}
+
+// writeFullBackup writes the metadata of a full backup of the namespace and returns its folder.
+func writeFullBackup(t *testing.T, backend *BackupBackend, metadata model.BackupMetadata) string {
+	t.Helper()
+	path := getFullPath(backend.fullBackupsPath, &model.BackupPolicy{}, metadata.Namespace, metadata.Created)
+	writeTestBackup(t, backend, path, metadata)
+	return path
+}
+
+// writeIncrementalBackup writes the metadata of an incremental backup of the namespace
+// and returns its folder.
+func writeIncrementalBackup(t *testing.T, backend *BackupBackend, metadata model.BackupMetadata) string {
+	t.Helper()
+	path := getIncrementalPath(backend.incrementalBackupsPath, metadata.Namespace, metadata.Created)
+	writeTestBackup(t, backend, path, metadata)
+	return path
+}
+
+func writeTestBackup(t *testing.T, backend *BackupBackend, path string, metadata model.BackupMetadata) {
+	t.Helper()
+	if err := os.MkdirAll(path, 0744); err != nil {
+		t.Fatal(err)
+	}
+	if err := backend.writeBackupMetadata(path, metadata); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestFullBackupRemoveFiles(t *testing.T) {
 	backend := &BackupBackend{
 		StorageAccessor:      &OSDiskAccessor{},
@@ -57,3 +97,139 @@ func TestFullBackupKeepFiles(t *testing.T) {
 		_ = os.RemoveAll(tempFolder)
 	})
 }
+
+func TestDeleteFullBackup(t *testing.T) {
+	backend := newDiskBackend(t, &OSDiskAccessor{})
+	for _, created := range []int64{10, 30} {
+		writeFullBackup(t, backend, model.BackupMetadata{Created: time.UnixMilli(created), Namespace: "ns1"})
+	}
+	writeIncrementalBackup(t, backend, model.BackupMetadata{Created: time.UnixMilli(20), Namespace: "ns1"})
+
+	if err := backend.DeleteFullBackup(20, false); !errors.Is(err, ErrBackupNotFound) {
+		t.Errorf("Expected ErrBackupNotFound, got %v", err)
+	}
+	if err := backend.DeleteFullBackup(10, false); !errors.Is(err, ErrBackupInUse) {
+		t.Errorf("Expected ErrBackupInUse, got %v", err)
+	}
+	if err := backend.DeleteFullBackup(30, false); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if err := backend.DeleteFullBackup(10, true); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	list, _ := backend.FullBackupList(&model.TimeBounds{})
+	if len(list) != 0 {
+		t.Errorf("Expected empty list, got %v", list)
+	}
+
+	if err := backend.DeleteIncrementalBackup(10); !errors.Is(err, ErrBackupNotFound) {
+		t.Errorf("Expected ErrBackupNotFound, got %v", err)
+	}
+	if err := backend.DeleteIncrementalBackup(20); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	list, _ = backend.IncrementalBackupList(&model.TimeBounds{})
+	if len(list) != 0 {
+		t.Errorf("Expected empty list, got %v", list)
+	}
+}
+
+func TestBackupHold(t *testing.T) {
+	backend := newDiskBackend(t, &OSDiskAccessor{})
+	for _, namespace := range []string{"ns1", "ns2"} {
+		writeFullBackup(t, backend, model.BackupMetadata{Created: time.UnixMilli(10), Namespace: namespace})
+	}
+	writeIncrementalBackup(t, backend, model.BackupMetadata{Created: time.UnixMilli(20), Namespace: "ns1"})
+
+	hold := model.BackupHold{Reason: "audit", Actor: "auditor", Created: time.UnixMilli(100)}
+	if err := backend.PlaceHold(30, true, hold); !errors.Is(err, ErrBackupNotFound) {
+		t.Errorf("Expected ErrBackupNotFound, got %v", err)
+	}
+	if err := backend.PlaceHold(10, true, hold); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	list, _ := backend.FullBackupList(&model.TimeBounds{})
+	for _, backup := range list {
+		if !backup.IsHeld() || backup.Hold.Actor != "auditor" {
+			t.Errorf("Expected backup on hold, got %v", backup)
+		}
+	}
+	if err := backend.DeleteFullBackup(10, true); !errors.Is(err, ErrBackupHeld) {
+		t.Errorf("Expected ErrBackupHeld, got %v", err)
+	}
+
+	if err := backend.PlaceHold(20, false, hold); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if err := backend.DeleteIncrementalBackup(20); !errors.Is(err, ErrBackupHeld) {
+		t.Errorf("Expected ErrBackupHeld, got %v", err)
+	}
+
+	if err := backend.ReleaseHold(10, true, "reviewer"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if err := backend.ReleaseHold(20, false, "reviewer"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	list, _ = backend.FullBackupList(&model.TimeBounds{})
+	for _, backup := range list {
+		if backup.IsHeld() || len(backup.HoldHistory) != 1 ||
+			backup.HoldHistory[0].Actor != "auditor" || backup.HoldHistory[0].ReleasedBy != "reviewer" ||
+			backup.HoldHistory[0].Released == nil {
+			t.Errorf("Expected released hold in history, got %v", backup.HoldHistory)
+		}
+	}
+	if err := backend.DeleteFullBackup(10, true); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if err := backend.DeleteIncrementalBackup(20); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+// lockedDiskAccessor is a disk accessor locking the written objects for a day.
+type lockedDiskAccessor struct {
+	OSDiskAccessor
+}
+
+func (a *lockedDiskAccessor) lockedUntil(now time.Time) *time.Time {
+	lockedUntil := now.AddDate(0, 0, 1)
+	return &lockedUntil
+}
+
+func (a *lockedDiskAccessor) writeLockedUntil(path string, data []byte, _ *time.Time) error {
+	return a.write(path, data)
+}
+
+func TestBackupLocked(t *testing.T) {
+	backend := newDiskBackend(t, &lockedDiskAccessor{})
+	writeFullBackup(t, backend, model.BackupMetadata{Created: time.UnixMilli(10), Namespace: "ns1"})
+	writeIncrementalBackup(t, backend, model.BackupMetadata{Created: time.UnixMilli(20), Namespace: "ns1"})
+
+	list, _ := backend.FullBackupList(&model.TimeBounds{})
+	if len(list) != 1 || !list[0].IsLocked(time.Now()) {
+		t.Errorf("Expected locked backup, got %v", list)
+	}
+	if err := backend.DeleteFullBackup(10, true); !errors.Is(err, ErrBackupLocked) {
+		t.Errorf("Expected ErrBackupLocked, got %v", err)
+	}
+	if err := backend.DeleteIncrementalBackup(20); !errors.Is(err, ErrBackupLocked) {
+		t.Errorf("Expected ErrBackupLocked, got %v", err)
+	}
+}
+
+func TestBackupLocked_UpdateKeepsLockTime(t *testing.T) {
+	backend := newDiskBackend(t, &lockedDiskAccessor{})
+	writeFullBackup(t, backend, model.BackupMetadata{Created: time.UnixMilli(10), Namespace: "ns1"})
+	written, _ := backend.FullBackupList(&model.TimeBounds{})
+
+	time.Sleep(10 * time.Millisecond)
+	if err := backend.PlaceHold(10, true, model.BackupHold{Actor: "test"}); err != nil {
+		t.Fatal(err)
+	}
+	updated, _ := backend.FullBackupList(&model.TimeBounds{})
+	if len(updated) != 1 || updated[0].Hold == nil ||
+		!updated[0].LockedUntil.Equal(*written[0].LockedUntil) {
+		t.Errorf("Expected the lock time %v after the update, got %v", written[0].LockedUntil, updated)
+	}
+}
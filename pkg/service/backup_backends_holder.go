@@ -44,16 +44,20 @@ func (b *BackendHolderImpl) Get(name string) (*BackupBackend, bool) {
 	return backend, found
 }
 
-func NewBackupBackends(config *model.Config) *BackendHolderImpl {
-	return &BackendHolderImpl{
-		data: BuildBackupBackends(config),
+func NewBackupBackends(config *model.Config) (*BackendHolderImpl, error) {
+	signer, err := loadSigner(config)
+	if err != nil {
+		return nil, err
 	}
+	return &BackendHolderImpl{
+		data: buildBackupBackends(config, signer),
+	}, nil
 }
 
-func BuildBackupBackends(config *model.Config) map[string]*BackupBackend {
+func buildBackupBackends(config *model.Config, signer *backupSigner) map[string]*BackupBackend {
 	backends := make(map[string]*BackupBackend, len(config.BackupRoutines))
 	for routineName := range config.BackupRoutines {
-		backends[routineName] = newBackend(config, routineName)
+		backends[routineName] = newBackend(config, routineName, signer)
 	}
 	return backends
 }
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aerospike/backup/pkg/model"
+	"github.com/aerospike/backup/pkg/util"
+	"github.com/reugn/go-quartz/quartz"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const yearlyCron = "0 0 0 1 1 *"
+
+func flowConfig() *model.Config {
+	config := model.NewConfigWithDefaultValues()
+	config.AerospikeClusters["cluster"] = &model.AerospikeCluster{}
+	config.Storage["memory"] = &model.Storage{Type: model.Local, Path: util.Ptr("backups")}
+	// no retries, so that no retry timer outlives the test
+	config.BackupPolicies["policy"] = &model.BackupPolicy{
+		MaxRetries: util.Ptr[int32](0),
+		Retention:  &model.RetentionPolicy{KeepFull: util.Ptr(2)},
+	}
+	config.BackupRoutines["routine"] = &model.BackupRoutine{
+		BackupPolicy:     "policy",
+		SourceCluster:    "cluster",
+		Storage:          "memory",
+		IntervalCron:     yearlyCron,
+		IncrIntervalCron: yearlyCron,
+		Namespaces:       []string{"ns1", "ns2"},
+	}
+	return config
+}
+
+// flowBackends returns the backends holder with the routine backed by the memory accessor.
+func flowBackends(accessor *memoryAccessor) *BackendHolderImpl {
+	backends := &BackendHolderImpl{}
+	backends.SetData(map[string]*BackupBackend{"routine": newMemoryBackend(accessor, "routine", false)})
+	return backends
+}
+
+func TestBackupFlow_ScheduleAndRestoreByTime(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := newSimulatedClock(t, start)
+	accessor := newMemoryAccessor()
+	useFakeBackup(t, &fakeBackup{accessor: accessor, records: 100, incrementalRecords: 10})
+	config := flowConfig()
+	backends := flowBackends(accessor)
+	backend, _ := backends.Get("routine")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	scheduler, err := ScheduleBackup(ctx, config, backends)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = scheduler.Clear() })
+
+	incremental := scheduledJob(t, scheduler, quartzGroupBackupIncremental)
+	full := scheduledJob(t, scheduler, quartzGroupBackupFull)
+	// the initial full backup runs right away
+	require.Eventually(t, func() bool {
+		backups, _ := backend.FullBackupList(&model.TimeBounds{})
+		return len(backups) == 2 && !full.(*backupJob).isRunning.Load()
+	}, 5*time.Second, 10*time.Millisecond)
+	run := func(job quartz.Job, after time.Duration) time.Time {
+		now := clock.Advance(after)
+		require.NoError(t, job.Execute(ctx))
+		return now
+	}
+	firstIncremental := run(incremental, time.Hour)
+	secondIncremental := run(incremental, time.Hour)
+	secondFull := run(full, 24*time.Hour)
+	run(full, 24*time.Hour)
+
+	allTime := &model.TimeBounds{}
+	fullBackups, _ := backend.FullBackupList(allTime)
+	assert.Len(t, fullBackups, 4, "the retention policy keeps 2 full backups of 2 namespaces")
+	for _, backup := range fullBackups {
+		assert.True(t, backup.Created.After(secondIncremental))
+		assert.Equal(t, uint64(100), backup.RecordCount)
+	}
+	incrementalBackups, _ := backend.IncrementalBackupList(allTime)
+	assert.Empty(t, incrementalBackups, "the incremental backups are pruned with their full backup")
+
+	// restore the chain before the second full backup is pruned
+	accessor = newMemoryAccessor()
+	backends = flowBackends(accessor)
+	backend, _ = backends.Get("routine")
+	useFakeBackup(t, &fakeBackup{accessor: accessor, records: 100, incrementalRecords: 10})
+	handler, err := newBackupHandler(config, "routine", backend, backends)
+	require.NoError(t, err)
+	handler.runFullBackup(start)
+	handler.runIncrementalBackup(firstIncremental)
+	handler.runIncrementalBackup(secondIncremental)
+	handler.runFullBackup(secondFull)
+
+	restoreService := newRestoreMemory(backends, config, nil)
+	restore := &fakeRestore{accessor: accessor}
+	restoreService.restoreService = restore
+	jobID, err := restoreService.RestoreByTime(&model.RestoreTimestampRequest{
+		DestinationCuster: &model.AerospikeCluster{},
+		Policy:            &model.RestorePolicy{},
+		Time:              secondIncremental.Add(time.Minute).UnixMilli(),
+		Routine:           "routine",
+	})
+	require.NoError(t, err)
+	status := waitForRestore(t, restoreService, jobID)
+
+	assert.Equal(t, model.JobStatusDone, status.Status, status.Error)
+	configuration, err := restoreService.RetrieveConfiguration("routine", secondIncremental.UnixMilli())
+	assert.NoError(t, err)
+	assert.NotEmpty(t, configuration)
+	assert.Equal(t, uint64(2*(100+10+10)), status.TotalRecords)
+	assert.Equal(t, []string{
+		"backups/routine/backup/1704067200000/data/ns1",
+		"backups/routine/incremental/1704070800000/data/ns1",
+		"backups/routine/incremental/1704074400000/data/ns1",
+		"backups/routine/backup/1704067200000/data/ns2",
+		"backups/routine/incremental/1704070800000/data/ns2",
+		"backups/routine/incremental/1704074400000/data/ns2",
+	}, sortedByNamespace(restore.restored))
+}
+
+func TestBackupFlow_BackupFailure(t *testing.T) {
+	accessor := newMemoryAccessor()
+	useFakeBackup(t, &fakeBackup{accessor: accessor, err: errors.New("backup failure")})
+	config := flowConfig()
+	backends := flowBackends(accessor)
+	backend, _ := backends.Get("routine")
+	handler, err := newBackupHandler(config, "routine", backend, backends)
+	require.NoError(t, err)
+
+	handler.runFullBackup(time.UnixMilli(100))
+
+	fullBackups, _ := backend.FullBackupList(&model.TimeBounds{})
+	assert.Empty(t, fullBackups)
+	assert.True(t, handler.state.LastFullRunIsEmpty())
+	// incremental backups wait for the initial full backup
+	handler.runIncrementalBackup(time.UnixMilli(200))
+	incrementalBackups, _ := backend.IncrementalBackupList(&model.TimeBounds{})
+	assert.Empty(t, incrementalBackups)
+}
+
+func scheduledJob(t *testing.T, scheduler quartz.Scheduler, group string) quartz.Job {
+	t.Helper()
+	jobDetail, err := scheduler.GetScheduledJob(quartz.NewJobKeyWithGroup("routine", group))
+	require.NoError(t, err)
+	return jobDetail.JobDetail().Job()
+}
+
+func waitForRestore(t *testing.T, restoreService *RestoreMemory, jobID int) *model.RestoreJobStatus {
+	t.Helper()
+	var status *model.RestoreJobStatus
+	require.Eventually(t, func() bool {
+		status, _ = restoreService.JobStatus(jobID)
+		return status != nil && status.Status != model.JobStatusRunning
+	}, 5*time.Second, 10*time.Millisecond)
+	return status
+}
+
+// sortedByNamespace returns the restored paths grouped by namespace, keeping the restore order.
+func sortedByNamespace(paths []string) []string {
+	var ns1, ns2 []string
+	for _, path := range paths {
+		if path[len(path)-3:] == "ns1" {
+			ns1 = append(ns1, path)
+		} else {
+			ns2 = append(ns2, path)
+		}
+	}
+	return append(ns1, ns2...)
+}
@@ -2,8 +2,10 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"path/filepath"
 	"strconv"
 	"time"
 
@@ -26,12 +28,21 @@ type BackupHandler struct {
 	secretAgent      *model.SecretAgent
 	state            *model.BackupState
 	retry            *RetryService
+	backends         BackendsHolder
+	storageRoutines  []string // the routines sharing the storage, for the quota
+	replicator       *replicator
+	// the backup runs deferred by the backup windows
+	deferred deferredBackups
 }
 
 var backupService shared.Backup = shared.NewBackup()
 
+// clusterConfiguration reads the configuration of the cluster nodes, replaced in tests.
+var clusterConfiguration = getClusterConfiguration
+
 // newBackupHandler returns a new BackupHandler instance.
-func newBackupHandler(config *model.Config, routineName string, backupBackend *BackupBackend) (*BackupHandler, error) {
+func newBackupHandler(config *model.Config, routineName string, backupBackend *BackupBackend,
+	backends BackendsHolder) (*BackupHandler, error) {
 	backupRoutine := config.BackupRoutines[routineName]
 	cluster := config.AerospikeClusters[backupRoutine.SourceCluster]
 	storage := config.Storage[backupRoutine.Storage]
@@ -62,12 +73,26 @@ func newBackupHandler(config *model.Config, routineName string, backupBackend *B
 		secretAgent:      secretAgent,
 		state:            backupBackend.readState(),
 		retry:            NewRetryService(routineName),
+		backends:         backends,
+		storageRoutines:  routinesOfStorage(config, backupRoutine.Storage),
+		replicator:       newReplicator(config, routineName, backupBackend),
 	}, nil
 }
 
 func (h *BackupHandler) runFullBackup(now time.Time) {
+	h.retryFullBackup(now, false)
+}
+
+// retryFullBackup runs the full backup, retrying on failure. Unless overridden, the runs,
+// including the retries, are deferred while the backup windows of the routine do not allow them.
+func (h *BackupHandler) retryFullBackup(now time.Time, override bool) {
 	h.retry.retry(
-		func() error { return h.runFullBackupInternal(now) },
+		func() error {
+			if !override && h.deferByWindow(quartzGroupBackupFull, currentTime()) {
+				return nil
+			}
+			return h.runFullBackupInternal(now)
+		},
 		time.Duration(h.backupFullPolicy.GetRetryDelayOrDefault())*time.Millisecond,
 		h.backupFullPolicy.GetMaxRetriesOrDefault(),
 	)
@@ -84,6 +109,19 @@ func (h *BackupHandler) runFullBackupInternal(now time.Time) error {
 		h.backend.FullBackupInProgress().Store(false)
 		slog.Debug("Release fullBackupInProgress lock", "name", h.routineName)
 	}()
+	if h.fullBackupHeld() {
+		slog.Warn("Full backup is on hold and cannot be overwritten, skipping full backup",
+			"name", h.routineName)
+		incrementSkippedCounters(h.routineName, quartzGroupBackupFull, skipReasonHeld)
+		return nil
+	}
+	if !h.checkQuota() {
+		incrementSkippedCounters(h.routineName, quartzGroupBackupFull, skipReasonQuota)
+		return nil
+	}
+	// the configuration is written first to be included in the backup manifests
+	h.writeClusterConfiguration(now)
+
 	for _, namespace := range h.namespaces {
 		err := h.fullBackupForNamespace(now, namespace)
 		if err != nil {
@@ -95,22 +133,33 @@ func (h *BackupHandler) runFullBackupInternal(now time.Time) error {
 	backupCounter.Inc()
 
 	// update the state
+	h.state.ClearEmptyIncrementals()
 	h.updateFullBackupState(now)
 
 	h.cleanIncrementalBackups()
 
-	h.writeClusterConfiguration(now)
+	h.applyRetention(now)
+
+	h.applyTiering(now)
 	return nil
 }
 
+// stop stops the deferred backup runs and the replications of the handler,
+// when the routine is rescheduled.
+func (h *BackupHandler) stop() {
+	h.deferred.stop()
+	h.replicator.stop()
+}
+
 func (h *BackupHandler) writeClusterConfiguration(now time.Time) {
-	infos, err := getClusterConfiguration(h.cluster)
+	infos, err := clusterConfiguration(h.cluster)
 	if err != nil || len(infos) == 0 {
 		slog.Warn("Could not read aerospike configuration", "err", err, "name", h.routineName)
 		return
 	}
 	path := getConfigurationPath(h.backend.fullBackupsPath, h.backupFullPolicy, now)
 	h.backend.CreateFolder(path)
+	defer h.backend.discardStaged(path)
 	for i, info := range infos {
 		confFilePath := fmt.Sprintf("%s/aerospike_%d.conf", path, i)
 		slog.Debug("Write aerospike configuration", "path", confFilePath)
@@ -119,6 +168,7 @@ func (h *BackupHandler) writeClusterConfiguration(now time.Time) {
 			slog.Error("Failed to write configuration for the backup", "name", h.routineName, "err", err)
 		}
 	}
+	h.replicator.replicateFolder(path)
 }
 
 func (h *BackupHandler) fullBackupForNamespace(upperBound time.Time, namespace string) error {
@@ -137,6 +187,7 @@ func (h *BackupHandler) fullBackupForNamespace(upperBound time.Time, namespace s
 		backupPath := h.backend.wrapWithPrefix(backupFolder)
 		stats, err = backupService.BackupRun(h.backupRoutine, h.backupFullPolicy, h.cluster,
 			h.storage, h.secretAgent, options, &namespace, backupPath)
+		err = h.backend.completeStaged(backupFolder, err)
 		if err != nil {
 			return
 		}
@@ -144,7 +195,10 @@ func (h *BackupHandler) fullBackupForNamespace(upperBound time.Time, namespace s
 		backupDurationGauge.Set(float64(elapsed.Milliseconds()))
 	}
 	slog.Debug("Starting full backup", "up to", upperBound, "name", h.routineName)
-	out := stdio.Stderr.Capture(backupRunFunc)
+	var out string
+	jobs.run(h.queuedJob(model.JobTypeFull, namespace), func() {
+		out = stdio.Stderr.Capture(backupRunFunc)
+	})
 	slog.Debug("Completed full backup", "name", h.routineName)
 	util.LogCaptured(out)
 
@@ -153,23 +207,77 @@ func (h *BackupHandler) fullBackupForNamespace(upperBound time.Time, namespace s
 		return fmt.Errorf("error during backup namespace %s, routine %s: %w", namespace, h.routineName, err)
 	}
 
+	configurationPath := getConfigurationPath(h.backend.fullBackupsPath, h.backupFullPolicy, upperBound)
+	if err := h.backend.writeManifest(backupFolder,
+		backupDataFiles(backupFolder), configurationFiles(configurationPath)); err != nil {
+		slog.Warn("Could not write backup manifest", "name", h.routineName,
+			"folder", backupFolder, "err", err)
+	}
 	metadata := stats.ToMetadata(time.Time{}, upperBound, namespace)
+	if err := h.backend.signBackup(backupFolder, &metadata); err != nil {
+		slog.Warn("Could not sign backup", "name", h.routineName,
+			"folder", backupFolder, "err", err)
+	}
 	if err := h.backend.writeBackupMetadata(backupFolder, metadata); err != nil {
 		slog.Error("Could not write backup metadata", "name", h.routineName,
 			"folder", backupFolder, "err", err)
 		return err
 	}
+	h.replicator.replicateBackup(backupFolder)
 	return nil
 }
 
 func (h *BackupHandler) cleanIncrementalBackups() {
-	if h.backupIncrPolicy.RemoveFiles.RemoveIncrementalBackup() {
-		if err := h.backend.DeleteFolder(h.backend.incrementalBackupsPath); err != nil {
-			slog.Error("Could not clean incremental backups", "name", h.routineName, "err", err)
-		} else {
-			slog.Info("Cleaned incremental backups", "name", h.routineName)
+	if !h.backupIncrPolicy.RemoveFiles.RemoveIncrementalBackup() {
+		return
+	}
+	incrementalBackups, err := h.backend.incrementalBackupList(&model.TimeBounds{})
+	if err != nil {
+		slog.Error("Could not read incremental backup list", "name", h.routineName, "err", err)
+		return
+	}
+	// the incremental backups are deleted one by one, so that the ones on hold and
+	// the ones locked by the storage are kept without blocking the others
+	held := make(map[string]bool)
+	for _, backup := range incrementalBackups {
+		if backup.IsHeld() {
+			held[timeSuffix(backup.Created)] = true
 		}
 	}
+	folders, err := h.backend.lsDir(h.backend.incrementalBackupsPath)
+	if err != nil {
+		slog.Error("Could not list incremental backups", "name", h.routineName, "err", err)
+		return
+	}
+	var locked int
+	for _, folder := range folders {
+		if held[filepath.Base(folder)] {
+			continue
+		}
+		err := h.backend.DeleteFolder(folder)
+		switch {
+		case errors.Is(err, ErrBackupLocked):
+			locked++
+		case err != nil:
+			slog.Error("Could not clean incremental backup", "name", h.routineName,
+				"folder", folder, "err", err)
+		}
+	}
+	slog.Info("Cleaned incremental backups", "name", h.routineName,
+		"held", len(held), "locked", locked)
+}
+
+// fullBackupHeld returns true if the full backup would overwrite a backup on hold.
+func (h *BackupHandler) fullBackupHeld() bool {
+	if !h.backend.removeFullBackup {
+		return false
+	}
+	fullBackups, err := h.backend.FullBackupList(&model.TimeBounds{})
+	if err != nil {
+		slog.Warn("Could not read full backup list", "name", h.routineName, "err", err)
+		return false
+	}
+	return heldBackups(fullBackups) > 0
 }
 
 func (h *BackupHandler) runIncrementalBackup(now time.Time) {
@@ -179,6 +287,18 @@ func (h *BackupHandler) runIncrementalBackup(now time.Time) {
 			"name", h.routineName)
 		return
 	}
+	if h.deferByWindow(quartzGroupBackupIncremental, now) {
+		return
+	}
+	// the deferred runs may overlap with the scheduled ones
+	if !h.backend.incrementalInProgress.CompareAndSwap(false, true) {
+		slog.Log(context.Background(), util.LevelTrace,
+			"Incremental backup is currently in progress, skipping it",
+			"name", h.routineName)
+		incrementSkippedCounters(h.routineName, quartzGroupBackupIncremental, skipReasonInProgress)
+		return
+	}
+	defer h.backend.incrementalInProgress.Store(false)
 	if h.backend.FullBackupInProgress().Load() {
 		slog.Log(context.Background(), util.LevelTrace,
 			"Full backup is currently in progress, skipping incremental backup",
@@ -214,6 +334,7 @@ func (h *BackupHandler) runIncrBackupForNamespace(upperBound time.Time, namespac
 		backupPath := h.backend.wrapWithPrefix(backupFolder)
 		stats, err = backupService.BackupRun(
 			h.backupRoutine, h.backupIncrPolicy, h.cluster, h.storage, h.secretAgent, options, &namespace, backupPath)
+		err = h.backend.completeStaged(backupFolder, err)
 		if err != nil {
 			slog.Warn("Failed incremental backup", "name", h.routineName, "err", err)
 			incrBackupFailureCounter.Inc()
@@ -223,18 +344,38 @@ func (h *BackupHandler) runIncrBackupForNamespace(upperBound time.Time, namespac
 		incrBackupDurationGauge.Set(float64(elapsed.Milliseconds()))
 	}
 	slog.Debug("Starting incremental backup", "name", h.routineName)
-	out := stdio.Stderr.Capture(backupRunFunc)
+	var out string
+	jobs.run(h.queuedJob(model.JobTypeIncremental, namespace), func() {
+		out = stdio.Stderr.Capture(backupRunFunc)
+	})
 	slog.Debug("Completed incremental backup", "name", h.routineName)
 	util.LogCaptured(out)
 	// delete if the backup file is empty
 	if h.isBackupEmpty(stats) {
 		h.deleteEmptyBackup(backupFolder, h.routineName)
+		if err == nil {
+			h.state.AddEmptyIncremental(namespace, time.Unix(0, fromEpoch))
+		} else {
+			// the failed backup leaves a gap in the chain
+			h.state.TakeEmptyIncremental(namespace, time.Unix(0, fromEpoch))
+		}
 	} else {
-		metadata := stats.ToMetadata(time.Unix(0, fromEpoch), upperBound, namespace)
+		from := h.state.TakeEmptyIncremental(namespace, time.Unix(0, fromEpoch))
+		if err := h.backend.writeManifest(backupFolder, backupDataFiles(backupFolder)); err != nil {
+			slog.Warn("Could not write backup manifest", "name", h.routineName,
+				"folder", backupFolder, "err", err)
+		}
+		metadata := stats.ToMetadata(from, upperBound, namespace)
+		if err := h.backend.signBackup(backupFolder, &metadata); err != nil {
+			slog.Warn("Could not sign backup", "name", h.routineName,
+				"folder", backupFolder, "err", err)
+		}
 		if err := h.backend.writeBackupMetadata(backupFolder, metadata); err != nil {
 			slog.Error("Could not write backup metadata", "name", h.routineName,
 				"folder", backupFolder, "err", err)
+			return
 		}
+		h.replicator.replicateBackup(backupFolder)
 	}
 }
 
@@ -293,3 +434,13 @@ func getConfigurationPath(fullBackupsPath string, backupPolicy *model.BackupPoli
 func timeSuffix(now time.Time) string {
 	return strconv.FormatInt(now.UnixMilli(), 10)
 }
+
+// queuedJob returns the job queue entry of the backup of the namespace.
+func (h *BackupHandler) queuedJob(jobType, namespace string) model.QueuedJob {
+	return model.QueuedJob{
+		Type:      jobType,
+		Routine:   h.routineName,
+		Namespace: namespace,
+		Cluster:   clusterKey(h.cluster),
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"log/slog"
 	"sync/atomic"
-	"time"
 
 	"github.com/aerospike/backup/pkg/util"
 	"github.com/reugn/go-quartz/quartz"
@@ -16,6 +15,7 @@ type backupJob struct {
 	handler   *BackupHandler
 	jobType   string
 	isRunning atomic.Bool
+	override  bool // whether to run regardless of the backup windows
 }
 
 var _ quartz.Job = (*backupJob)(nil)
@@ -26,9 +26,9 @@ func (j *backupJob) Execute(ctx context.Context) error {
 		defer j.isRunning.Store(false)
 		switch j.jobType {
 		case quartzGroupBackupFull:
-			j.handler.runFullBackup(time.Now())
+			j.handler.retryFullBackup(currentTime(), j.override)
 		case quartzGroupBackupIncremental:
-			j.handler.runIncrementalBackup(time.Now())
+			j.handler.runIncrementalBackup(currentTime())
 		default:
 			slog.Error("Unsupported backup type",
 				"type", j.jobType,
@@ -39,12 +39,21 @@ func (j *backupJob) Execute(ctx context.Context) error {
 			"Backup is currently in progress, skipping it",
 			"type", j.jobType,
 			"name", j.handler.routineName)
-		incrementSkippedCounters(j.jobType)
+		incrementSkippedCounters(j.handler.routineName, j.jobType, skipReasonInProgress)
 	}
 	return nil
 }
 
-func incrementSkippedCounters(jobType string) {
+// The reasons the backup runs are skipped for, besides the backup window restrictions.
+const (
+	skipReasonInProgress = "in-progress"
+	skipReasonQuota      = "quota"
+	skipReasonPaused     = "paused"
+	skipReasonHeld       = "held"
+)
+
+func incrementSkippedCounters(routineName, jobType, reason string) {
+	backupSkipReasonCounter.WithLabelValues(routineName, jobType, reason).Inc()
 	switch jobType {
 	case quartzGroupBackupFull:
 		backupSkippedCounter.Inc()
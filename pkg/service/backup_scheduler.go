@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/aerospike/backup/pkg/model"
+	"github.com/aerospike/backup/pkg/stdio"
 	"github.com/reugn/go-quartz/quartz"
 )
 
@@ -18,6 +19,10 @@ const (
 
 var jobStore = &backupJobs{jobs: make(map[string]*quartz.JobDetail)}
 
+// currentTime returns the time the scheduled jobs run at.
+// Replaced in tests to simulate the passage of time.
+var currentTime = time.Now
+
 type backupJobs struct {
 	sync.Mutex
 	jobs map[string]*quartz.JobDetail
@@ -29,8 +34,33 @@ func (b *backupJobs) put(key string, value *quartz.JobDetail) {
 	b.jobs[key] = value
 }
 
+// reset removes the jobs of the previous schedule and stops the backup runs
+// deferred by their handlers and their replications.
+func (b *backupJobs) reset() {
+	b.Lock()
+	handlers := make(map[*BackupHandler]bool)
+	for _, job := range b.jobs {
+		if backupJob, ok := job.Job().(*backupJob); ok {
+			handlers[backupJob.handler] = true
+		}
+	}
+	clear(b.jobs)
+	b.Unlock()
+	// the replications are waited for without blocking the ad-hoc backups
+	for handler := range handlers {
+		handler.stop()
+	}
+}
+
+// StopBackupJobs stops the deferred backup runs and the replications, on shutdown.
+// The pending replications are resumed on the next start.
+func StopBackupJobs() {
+	jobStore.reset()
+}
+
 // NewAdHocFullBackupJobForRoutine returns a new full backup job for the routine name.
-func NewAdHocFullBackupJobForRoutine(name string) *quartz.JobDetail {
+// The overridden job runs regardless of the backup windows of the routine.
+func NewAdHocFullBackupJobForRoutine(name string, override bool) *quartz.JobDetail {
 	jobStore.Lock()
 	defer jobStore.Unlock()
 	key := quartz.NewJobKeyWithGroup(name, quartzGroupBackupFull).String()
@@ -40,42 +70,65 @@ func NewAdHocFullBackupJobForRoutine(name string) *quartz.JobDetail {
 	}
 	jobKey := quartz.NewJobKeyWithGroup(fmt.Sprintf("%s-adhoc-%d", name, time.Now().UnixMilli()),
 		quartzGroupBackupFull)
+	if override {
+		handler := job.Job().(*backupJob).handler
+		return quartz.NewJobDetail(&backupJob{handler: handler, jobType: quartzGroupBackupFull, override: true},
+			jobKey)
+	}
 	return quartz.NewJobDetail(job.Job(), jobKey)
 }
 
+// ApplyNewConfig reschedules the backup jobs with the new configuration.
+// The signing keys are loaded first, so that the current jobs keep running if they
+// cannot be read.
 func ApplyNewConfig(scheduler quartz.Scheduler, config *model.Config, backends BackendsHolder) error {
-	err := scheduler.Clear()
+	signer, err := loadSigner(config)
+	if err != nil {
+		return err
+	}
+	err = scheduler.Clear()
 	if err != nil {
 		return err
 	}
 
-	backends.SetData(BuildBackupBackends(config))
+	backends.SetData(buildBackupBackends(config, signer))
 
-	return scheduleRoutines(scheduler, config, backends)
+	return scheduleRoutines(scheduler, config, backends, signer)
 }
 
 // ScheduleBackup creates a new quartz.Scheduler, schedules all the configured backup jobs,
 // starts and returns the scheduler.
 func ScheduleBackup(ctx context.Context, config *model.Config, backends BackendsHolder,
 ) (quartz.Scheduler, error) {
+	signer, err := loadSigner(config)
+	if err != nil {
+		return nil, err
+	}
 	scheduler := quartz.NewStdScheduler()
 	scheduler.Start(ctx)
 
-	err := scheduleRoutines(scheduler, config, backends)
+	err = scheduleRoutines(scheduler, config, backends, signer)
 	if err != nil {
 		return nil, err
 	}
 	return scheduler, nil
 }
 
-func scheduleRoutines(scheduler quartz.Scheduler, config *model.Config, backends BackendsHolder) error {
+func scheduleRoutines(scheduler quartz.Scheduler, config *model.Config, backends BackendsHolder,
+	signer *backupSigner) error {
+	jobStore.reset()
+	if config.ServiceConfig != nil {
+		jobs.configure(config.ServiceConfig.JobQueue, stdio.Stderr.Exclusive())
+	}
+	restore := newRestoreMemory(backends, config, signer)
 	for routineName, routine := range config.BackupRoutines {
 		backend, _ := backends.Get(routineName)
-		handler, err := newBackupHandler(config, routineName, backend)
+		handler, err := newBackupHandler(config, routineName, backend, backends)
 		if err != nil {
 			slog.Error("failed to create backup handler", "routine", routineName, "err", err)
 			continue
 		}
+		handler.replicator.resume()
 
 		// schedule full backup job for the routine
 		if err := scheduleFullBackup(scheduler, handler, routine, routineName); err != nil {
@@ -87,9 +140,34 @@ func scheduleRoutines(scheduler quartz.Scheduler, config *model.Config, backends
 			if err := scheduleIncrementalBackup(scheduler, handler, routine, routineName); err != nil {
 				return err
 			}
+			// refresh the chain issues metric of the routine
+			if err := scheduleChainAnalysis(scheduler, handler.backend, routineName); err != nil {
+				return err
+			}
+		}
+
+		if handler.state.IsPaused() {
+			// the routine was paused before the restart or the configuration change
+			if err := pauseRoutineJobs(scheduler, routineName); err != nil {
+				return err
+			}
+		}
+
+		if routine.VerifyCron != "" {
+			// schedule the verification of the routine backups
+			if err := scheduleVerification(scheduler, backend, routine, routineName); err != nil {
+				return err
+			}
+		}
+
+		if routine.RestoreDrill != nil {
+			// schedule the restore drill of the routine backups
+			if err := scheduleRestoreDrill(scheduler, restore, routine, routineName); err != nil {
+				return err
+			}
 		}
 	}
-	return nil
+	return scheduleGarbageCollector(scheduler, config, backends)
 }
 
 func scheduleFullBackup(scheduler quartz.Scheduler, handler *BackupHandler,
@@ -107,7 +185,7 @@ func scheduleFullBackup(scheduler quartz.Scheduler, handler *BackupHandler,
 		return err
 	}
 	jobStore.put(fullJobDetail.JobKey().String(), fullJobDetail)
-	if needToRunFullBackupNow(handler.state.LastFullRun, fullCronTrigger) {
+	if !handler.state.IsPaused() && needToRunFullBackupNow(handler.state.LastFullRun, fullCronTrigger) {
 		slog.Debug("Schedule initial full backup", "name", routineName)
 		fullJobDetail := quartz.NewJobDetail(
 			fullJob,
@@ -147,7 +225,7 @@ func needToRunFullBackupNow(lastFullRun time.Time, trigger *quartz.CronTrigger)
 	if err != nil {
 		return true // some error, run backup to be safe
 	}
-	if time.Unix(0, fireTimeNano).Before(time.Now()) {
+	if time.Unix(0, fireTimeNano).Before(currentTime()) {
 		return true // next scheduled backup is in past
 	}
 
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// deferredBackups holds the backup runs deferred until the backup windows of the
+// routine allow them, at most one per backup type.
+type deferredBackups struct {
+	mu      sync.Mutex
+	timers  map[string]*time.Timer
+	stopped bool
+}
+
+// schedule runs f after the given delay, replacing the run of the same type
+// deferred before. Nothing is scheduled once the deferred runs are stopped.
+func (d *deferredBackups) schedule(jobType string, delay time.Duration, f func()) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.stopped {
+		return
+	}
+	if d.timers == nil {
+		d.timers = make(map[string]*time.Timer)
+	}
+	if timer, found := d.timers[jobType]; found {
+		timer.Stop()
+	}
+	d.timers[jobType] = time.AfterFunc(delay, f)
+}
+
+// stop cancels the deferred runs, when the routine is rescheduled.
+func (d *deferredBackups) stop() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, timer := range d.timers {
+		timer.Stop()
+	}
+	clear(d.timers)
+	d.stopped = true
+}
+
+// deferByWindow returns true if the backup windows of the routine do not allow
+// the backup of the given type at the given time. The backup run is then deferred
+// until the next time the windows allow it.
+func (h *BackupHandler) deferByWindow(jobType string, now time.Time) bool {
+	reason := h.backupRoutine.WindowRestriction(now)
+	if reason == "" {
+		return false
+	}
+	incrementSkippedCounters(h.routineName, jobType, reason)
+	next := h.backupRoutine.NextAllowedTime(now)
+	if next.IsZero() {
+		slog.Warn("Backup windows do not allow the backup, skipping it",
+			"name", h.routineName, "type", jobType, "reason", reason)
+		return true
+	}
+	slog.Info("Backup deferred by the backup windows",
+		"name", h.routineName, "type", jobType, "reason", reason, "until", next)
+	h.deferred.schedule(jobType, next.Sub(now), func() {
+		if h.state.IsPaused() {
+			incrementSkippedCounters(h.routineName, jobType, skipReasonPaused)
+			return
+		}
+		switch jobType {
+		case quartzGroupBackupFull:
+			h.runFullBackup(currentTime())
+		case quartzGroupBackupIncremental:
+			h.runIncrementalBackup(currentTime())
+		}
+	})
+	return true
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aerospike/backup/pkg/model"
+	"github.com/aerospike/backup/pkg/util"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// windowHandler returns the handler of the routine allowed to back up in the nights
+// only, with the simulated clock at Monday noon.
+func windowHandler(t *testing.T) (*BackupHandler, *simulatedClock) {
+	t.Helper()
+	clock := newSimulatedClock(t, time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC))
+	accessor := newMemoryAccessor()
+	useFakeBackup(t, &fakeBackup{accessor: accessor, records: 100, incrementalRecords: 10})
+	config := flowConfig()
+	config.BackupRoutines["routine"].AllowedWindows = []*model.TimeWindow{{Start: "22:00", End: "06:00"}}
+	backends := flowBackends(accessor)
+	backend, _ := backends.Get("routine")
+	handler, err := newBackupHandler(config, "routine", backend, backends)
+	require.NoError(t, err)
+	t.Cleanup(handler.deferred.stop)
+	return handler, clock
+}
+
+func fullBackupCount(t *testing.T, handler *BackupHandler) int {
+	t.Helper()
+	backups, err := handler.backend.FullBackupList(&model.TimeBounds{})
+	require.NoError(t, err)
+	return len(backups)
+}
+
+// runDeferred runs the deferred backup of the given type immediately.
+func runDeferred(t *testing.T, handler *BackupHandler, jobType string) {
+	t.Helper()
+	handler.deferred.mu.Lock()
+	defer handler.deferred.mu.Unlock()
+	timer, found := handler.deferred.timers[jobType]
+	require.True(t, found, "no deferred %s backup", jobType)
+	timer.Reset(0)
+}
+
+func TestBackupWindow_DeferFullBackup(t *testing.T) {
+	handler, clock := windowHandler(t)
+	skipped := backupSkipReasonCounter.WithLabelValues("routine", quartzGroupBackupFull,
+		model.WindowReasonOutsideWindow)
+	before := testutil.ToFloat64(skipped)
+
+	handler.runFullBackup(clock.Now())
+	assert.Equal(t, 0, fullBackupCount(t, handler))
+	assert.Equal(t, before+1, testutil.ToFloat64(skipped))
+
+	clock.Advance(10 * time.Hour)
+	runDeferred(t, handler, quartzGroupBackupFull)
+	assert.Eventually(t, func() bool {
+		return fullBackupCount(t, handler) == 2 && !handler.backend.FullBackupInProgress().Load()
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestBackupWindow_OverrideFullBackup(t *testing.T) {
+	handler, clock := windowHandler(t)
+
+	handler.retryFullBackup(clock.Now(), true)
+	assert.Equal(t, 2, fullBackupCount(t, handler))
+	assert.Empty(t, handler.deferred.timers)
+}
+
+func TestBackupWindow_DeferIncrementalBackup(t *testing.T) {
+	handler, clock := windowHandler(t)
+	handler.retryFullBackup(clock.Now(), true)
+	skipped := backupSkipReasonCounter.WithLabelValues("routine", quartzGroupBackupIncremental,
+		model.WindowReasonOutsideWindow)
+	before := testutil.ToFloat64(skipped)
+
+	handler.runIncrementalBackup(clock.Now().Add(time.Hour))
+	incrementalBackups, err := handler.backend.IncrementalBackupList(&model.TimeBounds{})
+	require.NoError(t, err)
+	assert.Empty(t, incrementalBackups)
+	assert.Equal(t, before+1, testutil.ToFloat64(skipped))
+
+	clock.Advance(11 * time.Hour)
+	runDeferred(t, handler, quartzGroupBackupIncremental)
+	assert.Eventually(t, func() bool {
+		incrementalBackups, err := handler.backend.IncrementalBackupList(&model.TimeBounds{})
+		return err == nil && len(incrementalBackups) == 2 && !handler.backend.incrementalInProgress.Load()
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestBackupWindow_StopDeferredOnReschedule(t *testing.T) {
+	clock := newSimulatedClock(t, time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC))
+	accessor := newMemoryAccessor()
+	useFakeBackup(t, &fakeBackup{accessor: accessor, records: 100, incrementalRecords: 10})
+	config := flowConfig()
+	config.BackupRoutines["routine"].AllowedWindows = []*model.TimeWindow{{Start: "22:00", End: "06:00"}}
+	backends := flowBackends(accessor)
+	backend, _ := backends.Get("routine")
+	// no initial full backup is needed
+	require.NoError(t, backend.writeState(&model.BackupState{LastFullRun: clock.Now()}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	scheduler, err := ScheduleBackup(ctx, config, backends)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = scheduler.Clear() })
+	handler, err := scheduledHandler(scheduler, "routine")
+	require.NoError(t, err)
+	handler.runFullBackup(clock.Now())
+	timer := handler.deferred.timers[quartzGroupBackupFull]
+	require.NotNil(t, timer)
+
+	require.NoError(t, scheduler.Clear())
+	require.NoError(t, scheduleRoutines(scheduler, config, backends, nil))
+
+	// the replaced handler defers nothing anymore
+	assert.False(t, timer.Stop(), "the deferred run is expected to be stopped")
+	assert.Empty(t, handler.deferred.timers)
+	handler.runFullBackup(clock.Now())
+	assert.Empty(t, handler.deferred.timers)
+	newHandler, err := scheduledHandler(scheduler, "routine")
+	require.NoError(t, err)
+	assert.NotSame(t, handler, newHandler)
+	t.Cleanup(newHandler.deferred.stop)
+}
+
+func TestFullBackupHeld_Skipped(t *testing.T) {
+	accessor := newMemoryAccessor()
+	useFakeBackup(t, &fakeBackup{accessor: accessor, records: 100, incrementalRecords: 10})
+	config := flowConfig()
+	config.BackupPolicies["policy"].RemoveFiles = util.Ptr(model.RemoveAll)
+	backends := &BackendHolderImpl{}
+	backends.SetData(map[string]*BackupBackend{"routine": newMemoryBackend(accessor, "routine", true)})
+	backend, _ := backends.Get("routine")
+	handler, err := newBackupHandler(config, "routine", backend, backends)
+	require.NoError(t, err)
+	skipped := backupSkipReasonCounter.WithLabelValues("routine", quartzGroupBackupFull, skipReasonHeld)
+	before := testutil.ToFloat64(skipped)
+
+	now := time.Now()
+	require.NoError(t, handler.runFullBackupInternal(now))
+	require.NoError(t, backend.PlaceHold(now.UnixMilli(), true, model.BackupHold{Reason: "audit", Actor: "auditor"}))
+	require.NoError(t, handler.runFullBackupInternal(now.Add(time.Hour)))
+
+	backups, err := backend.FullBackupList(&model.TimeBounds{})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{now.UnixMilli(), now.UnixMilli()}, createdMillis(backups))
+	assert.Equal(t, before+1, testutil.ToFloat64(skipped))
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/aerospike/backup/pkg/model"
+	"github.com/reugn/go-quartz/quartz"
+)
+
+const quartzGroupChainAnalysis = "chain-analysis"
+
+// chainAnalysisInterval is the interval of the chain issues metric refresh.
+// The analysis lists all the backups of the routine, so it does not run after every
+// incremental backup.
+const chainAnalysisInterval = time.Hour
+
+// ErrBackupChainGap is returned on an attempt to restore to a time after a gap in the
+// incremental backup chain, as the records changed during the gap would be missing.
+var ErrBackupChainGap = errors.New("incremental backup chain has a gap")
+
+// AnalyzeChains reports the gaps and overlaps in the incremental backup chains of each
+// namespace of the routine, including the backups moved to the tiering storage.
+// The chain issues metric of the routine is updated with the result.
+func AnalyzeChains(backend *BackupBackend, routineName string) (*model.ChainReport, error) {
+	allTime := &model.TimeBounds{}
+	fullBackups, err := backend.FullBackupList(allTime)
+	if err != nil {
+		return nil, err
+	}
+	incrementalBackups, err := backend.IncrementalBackupList(allTime)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &model.ChainReport{
+		Routine:                routineName,
+		Created:                currentTime(),
+		FullBackupCount:        len(fullBackups),
+		IncrementalBackupCount: len(incrementalBackups),
+		Issues:                 chainIssues(fullBackups, incrementalBackups),
+	}
+	for _, issueType := range []string{model.ChainGap, model.ChainOverlap, model.ChainMissingFull} {
+		count := len(filterIssues(report.Issues, issueType))
+		chainIssuesGauge.WithLabelValues(routineName, issueType).Set(float64(count))
+	}
+	return report, nil
+}
+
+// chainIssues returns the continuity issues of the incremental backup chains of the
+// given backups, sorted by creation time: the incremental backups without a preceding
+// full backup, and the ones that do not start where the previous backup of the
+// namespace ends.
+func chainIssues(fullBackups, incrementalBackups []model.BackupDetails) []model.ChainIssue {
+	issues := []model.ChainIssue{}
+	fullByNamespace := byNamespace(fullBackups)
+	for namespace, incrementals := range byNamespace(incrementalBackups) {
+		chains, orphans := buildBackupChains(fullByNamespace[namespace], incrementals)
+		for _, orphan := range orphans {
+			issues = append(issues, newChainIssue(model.ChainMissingFull, orphan, nil))
+		}
+		for _, chain := range chains {
+			previous := chain.created
+			slices.SortFunc(chain.incrementals, func(a, b model.BackupDetails) int {
+				return a.Created.Compare(b.Created)
+			})
+			for _, incremental := range chain.incrementals {
+				end := previous
+				switch {
+				case incremental.From.After(end):
+					issues = append(issues, newChainIssue(model.ChainGap, incremental, &end))
+				case incremental.From.Before(end):
+					issues = append(issues, newChainIssue(model.ChainOverlap, incremental, &end))
+				}
+				previous = incremental.Created
+			}
+		}
+	}
+	slices.SortFunc(issues, func(a, b model.ChainIssue) int {
+		return cmp.Or(a.Created.Compare(b.Created), cmp.Compare(a.Namespace, b.Namespace))
+	})
+	return issues
+}
+
+func newChainIssue(issueType string, backup model.BackupDetails, previous *time.Time) model.ChainIssue {
+	return model.ChainIssue{
+		Type:      issueType,
+		Namespace: backup.Namespace,
+		Key:       backupKey(backup),
+		Created:   backup.Created,
+		From:      backup.From,
+		Previous:  previous,
+	}
+}
+
+// filterIssues returns the issues of the given type.
+func filterIssues(issues []model.ChainIssue, issueType string) []model.ChainIssue {
+	return slices.DeleteFunc(slices.Clone(issues), func(issue model.ChainIssue) bool {
+		return issue.Type != issueType
+	})
+}
+
+// chainAnalysisJob implements the quartz.Job interface.
+type chainAnalysisJob struct {
+	backend     *BackupBackend
+	routineName string
+}
+
+var _ quartz.Job = (*chainAnalysisJob)(nil)
+
+// Execute is called by a Scheduler when the Trigger associated with this job fires.
+func (j *chainAnalysisJob) Execute(_ context.Context) error {
+	if _, err := AnalyzeChains(j.backend, j.routineName); err != nil {
+		slog.Warn("Could not analyze incremental backup chains", "name", j.routineName, "err", err)
+	}
+	return nil
+}
+
+// Description returns the description of the chain analysis job.
+func (j *chainAnalysisJob) Description() string {
+	return "backup chain analysis job"
+}
+
+func scheduleChainAnalysis(scheduler quartz.Scheduler, backend *BackupBackend, routineName string) error {
+	jobDetail := quartz.NewJobDetail(
+		&chainAnalysisJob{backend: backend, routineName: routineName},
+		quartz.NewJobKeyWithGroup(routineName, quartzGroupChainAnalysis),
+	)
+	return scheduler.ScheduleJob(jobDetail, quartz.NewSimpleTrigger(chainAnalysisInterval))
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aerospike/backup/pkg/model"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func chainBackup(namespace string, from, created int64) model.BackupDetails {
+	key := namespace + "/" + time.UnixMilli(created).UTC().Format(time.RFC3339)
+	return model.BackupDetails{
+		BackupMetadata: model.BackupMetadata{
+			Namespace: namespace,
+			From:      time.UnixMilli(from),
+			Created:   time.UnixMilli(created),
+		},
+		Key: &key,
+	}
+}
+
+func TestChainIssues(t *testing.T) {
+	fullBackups := []model.BackupDetails{
+		chainBackup("ns1", 0, 1000),
+		chainBackup("ns1", 0, 5000),
+	}
+	incrementalBackups := []model.BackupDetails{
+		chainBackup("ns1", 2000, 4000), // overlaps the one at 3000
+		chainBackup("ns1", 1000, 2000),
+		chainBackup("ns1", 2500, 3000), // gap since 2000
+		chainBackup("ns1", 5000, 6000),
+		chainBackup("ns2", 500, 2000), // no full backup of ns2
+	}
+
+	issues := chainIssues(fullBackups, incrementalBackups)
+
+	require.Len(t, issues, 3)
+	assert.Equal(t, model.ChainMissingFull, issues[0].Type)
+	assert.Equal(t, "ns2", issues[0].Namespace)
+	assert.Nil(t, issues[0].Previous)
+	assert.Equal(t, model.ChainGap, issues[1].Type)
+	assert.Equal(t, int64(3000), issues[1].Created.UnixMilli())
+	assert.Equal(t, int64(2000), issues[1].Previous.UnixMilli())
+	assert.Equal(t, model.ChainOverlap, issues[2].Type)
+	assert.Equal(t, int64(4000), issues[2].Created.UnixMilli())
+	assert.Equal(t, int64(3000), issues[2].Previous.UnixMilli())
+	assert.Empty(t, chainIssues(fullBackups, nil))
+}
+
+func TestAnalyzeChains(t *testing.T) {
+	_, backend := verifiedBackend(t)
+
+	report, err := AnalyzeChains(backend, "routine")
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.FullBackupCount)
+	assert.Equal(t, 6, report.IncrementalBackupCount)
+	assert.Empty(t, report.Issues)
+
+	require.NoError(t, backend.DeleteFolder("backups/routine/incremental/3000/data/ns1"))
+	report, err = AnalyzeChains(backend, "routine")
+	require.NoError(t, err)
+	require.Len(t, report.Issues, 1)
+	assert.Equal(t, model.ChainGap, report.Issues[0].Type)
+	assert.Equal(t, "memory:///backups/routine/incremental/4000/data/ns1", report.Issues[0].Key)
+	assert.Equal(t, float64(1), testutil.ToFloat64(chainIssuesGauge.WithLabelValues("routine", model.ChainGap)))
+	assert.Equal(t, float64(0), testutil.ToFloat64(chainIssuesGauge.WithLabelValues("routine", model.ChainOverlap)))
+}
+
+func TestAnalyzeChains_EmptyAndFailedIncrementals(t *testing.T) {
+	accessor := newMemoryAccessor()
+	backup := &fakeBackup{accessor: accessor, records: 100}
+	useFakeBackup(t, backup)
+	config := flowConfig()
+	backends := flowBackends(accessor)
+	backend, _ := backends.Get("routine")
+	handler, err := newBackupHandler(config, "routine", backend, backends)
+	require.NoError(t, err)
+
+	handler.runFullBackup(time.UnixMilli(1000))
+	// the empty backup is deleted, and covered by the next one
+	handler.runIncrementalBackup(time.UnixMilli(2000))
+	backup.incrementalRecords = 10
+	handler.runIncrementalBackup(time.UnixMilli(3000))
+	// the failed backup leaves a gap
+	backup.err = errors.New("backup failed")
+	handler.runIncrementalBackup(time.UnixMilli(4000))
+	backup.err = nil
+	handler.runIncrementalBackup(time.UnixMilli(5000))
+
+	report, err := AnalyzeChains(backend, "routine")
+	require.NoError(t, err)
+	assert.Equal(t, 4, report.IncrementalBackupCount)
+	require.Len(t, report.Issues, 2)
+	for _, issue := range report.Issues {
+		assert.Equal(t, model.ChainGap, issue.Type)
+		assert.Equal(t, int64(5000), issue.Created.UnixMilli())
+		assert.Equal(t, int64(3000), issue.Previous.UnixMilli())
+	}
+}
+
+func TestAnalyzeChains_EmptyIncrementalsAfterRestart(t *testing.T) {
+	accessor := newMemoryAccessor()
+	backup := &fakeBackup{accessor: accessor, records: 100}
+	useFakeBackup(t, backup)
+	config := flowConfig()
+	backends := flowBackends(accessor)
+	backend, _ := backends.Get("routine")
+	handler, err := newBackupHandler(config, "routine", backend, backends)
+	require.NoError(t, err)
+
+	handler.runFullBackup(time.UnixMilli(1000))
+	handler.runIncrementalBackup(time.UnixMilli(2000))
+
+	// the range of the empty backup is read from the state after the restart
+	handler, err = newBackupHandler(config, "routine", backend, backends)
+	require.NoError(t, err)
+	backup.incrementalRecords = 10
+	handler.runIncrementalBackup(time.UnixMilli(3000))
+
+	report, err := AnalyzeChains(backend, "routine")
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.IncrementalBackupCount)
+	assert.Empty(t, report.Issues)
+	assert.Empty(t, backend.readState().EmptyIncrementalsFrom)
+}
+
+func TestRestoreByTime_ChainGap(t *testing.T) {
+	_, restore := drillRestore(t)
+	backend, _ := restore.backends.Get("routine")
+	require.NoError(t, backend.DeleteFolder("backups/routine/incremental/2000/data/ns1"))
+	request := &model.RestoreTimestampRequest{
+		DestinationCuster: &model.AerospikeCluster{},
+		Policy:            &model.RestorePolicy{},
+		Time:              5000,
+		Routine:           "routine",
+	}
+
+	_, err := restore.RestoreByTime(request)
+	assert.ErrorIs(t, err, ErrBackupChainGap)
+
+	request.AllowGaps = true
+	jobID, err := restore.RestoreByTime(request)
+	require.NoError(t, err)
+	status := waitForRestore(t, restore, jobID)
+	assert.Equal(t, model.JobStatusDone, status.Status, status.Error)
+
+	// the restore to a time before the gap
+	request.AllowGaps = false
+	request.Time = 1500
+	_, err = restore.RestoreByTime(request)
+	assert.NoError(t, err)
+}
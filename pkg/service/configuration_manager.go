@@ -67,6 +67,8 @@ func (b *ConfigManagerBuilder) NewConfigManager(configFile string, remote bool)
 		return b.s3Builder.NewS3ConfigurationManager(configStorage)
 	case model.Local:
 		return newLocalConfigurationManager(configStorage)
+	case model.GcpGCS, model.AzureBlob, model.SFTP:
+		return newStorageConfigurationManager(configStorage)
 	default:
 		return nil, fmt.Errorf("unknown type %s", configStorage.Type)
 	}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/aerospike/backup/pkg/model"
+	"gopkg.in/yaml.v3"
+)
+
+// StorageConfigurationManager implements the ConfigurationManager interface,
+// performing I/O operations on a cloud storage through its StorageAccessor.
+type StorageConfigurationManager struct {
+	sync.Mutex
+	StorageAccessor
+	path string
+}
+
+var _ ConfigurationManager = (*StorageConfigurationManager)(nil)
+
+// newStorageConfigurationManager returns a new StorageConfigurationManager
+// for the configuration file at the path of the storage.
+func newStorageConfigurationManager(configStorage *model.Storage) (ConfigurationManager, error) {
+	accessor, path, err := newStorageAccessor(configStorage)
+	if err != nil {
+		return nil, err
+	}
+	return &StorageConfigurationManager{
+		StorageAccessor: accessor,
+		path:            path,
+	}, nil
+}
+
+// ReadConfiguration reads and returns the configuration from the storage.
+func (s *StorageConfigurationManager) ReadConfiguration() (*model.Config, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	content, err := s.read(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read file %s: %v", s.path, err)
+	}
+	config := model.NewConfigWithDefaultValues()
+	if err := yaml.Unmarshal(content, config); err != nil {
+		return nil, fmt.Errorf("in file %q: %w", s.path, err)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// WriteConfiguration writes the configuration to the storage.
+func (s *StorageConfigurationManager) WriteConfiguration(config *model.Config) error {
+	s.Lock()
+	defer s.Unlock()
+
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("failed to marshal configuration data: %w", err)
+	}
+	return s.write(s.path, data)
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aerospike/aerospike-management-lib/asconfig"
+	"github.com/aerospike/backup/pkg/model"
+	"github.com/aerospike/backup/pkg/shared"
+	"gopkg.in/yaml.v3"
+)
+
+// fakeBackup is a shared.Backup writing a deterministic placeholder backup file
+// for each namespace to the memory accessor.
+type fakeBackup struct {
+	accessor *memoryAccessor
+	// The number of records of each full and incremental backup.
+	records            uint64
+	incrementalRecords uint64
+	err                error
+}
+
+var _ shared.Backup = (*fakeBackup)(nil)
+
+func (f *fakeBackup) BackupRun(_ *model.BackupRoutine, _ *model.BackupPolicy, _ *model.AerospikeCluster,
+	_ *model.Storage, _ *model.SecretAgent, opts shared.BackupOptions, namespace *string,
+	path *string) (*shared.BackupStat, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	records := f.records
+	if opts.ModAfter != nil {
+		records = f.incrementalRecords
+	}
+	content := []byte(fmt.Sprintf("namespace: %s\nrecords: %d\n", *namespace, records))
+	if err := f.accessor.write(filepath.Join(*path, *namespace+"_0.asb"), content); err != nil {
+		return nil, err
+	}
+	return &shared.BackupStat{RecordCount: records, ByteCount: uint64(len(content)), FileCount: 1}, nil
+}
+
+// fakeRestore is a shared.Restore reading the placeholder backup files from the memory accessor.
+type fakeRestore struct {
+	sync.Mutex
+	accessor *memoryAccessor
+	restored []string // the restored backup paths, in order
+}
+
+var _ shared.Restore = (*fakeRestore)(nil)
+
+func (f *fakeRestore) RestoreRun(request *model.RestoreRequestInternal) (*model.RestoreResult, error) {
+	path := strings.TrimPrefix(*request.Dir, memoryProtocol)
+	files, err := f.accessor.lsFiles(path)
+	if err != nil {
+		return nil, err
+	}
+	result := model.NewRestoreResult()
+	for _, file := range files {
+		if !strings.HasSuffix(file, ".asb") {
+			continue
+		}
+		content, err := f.accessor.read(file)
+		if err != nil {
+			return nil, err
+		}
+		var backup struct {
+			Records uint64 `yaml:"records"`
+		}
+		if err := yaml.Unmarshal(content, &backup); err != nil {
+			return nil, err
+		}
+		result.TotalRecords += backup.Records
+		result.InsertedRecords += backup.Records
+		result.TotalBytes += uint64(len(content))
+	}
+	if result.TotalBytes == 0 {
+		return nil, fmt.Errorf("no backup files found in %s", path)
+	}
+	f.Lock()
+	defer f.Unlock()
+	f.restored = append(f.restored, memoryPath(path))
+	return result, nil
+}
+
+// useFakeBackup replaces the shared library backup with the fake one for the test,
+// along with the cluster configuration reader.
+func useFakeBackup(t *testing.T, backup *fakeBackup) {
+	t.Helper()
+	previousBackup, previousConfiguration := backupService, clusterConfiguration
+	backupService = backup
+	clusterConfiguration = func(_ *model.AerospikeCluster) ([]asconfig.DotConf, error) {
+		return []asconfig.DotConf{"namespace ns1 {\n}\n"}, nil
+	}
+	t.Cleanup(func() {
+		backupService, clusterConfiguration = previousBackup, previousConfiguration
+	})
+}
+
+// simulatedClock replaces the current time of the scheduled jobs for the test.
+type simulatedClock struct {
+	sync.Mutex
+	now time.Time
+}
+
+func newSimulatedClock(t *testing.T, now time.Time) *simulatedClock {
+	t.Helper()
+	clock := &simulatedClock{now: now}
+	previous := currentTime
+	currentTime = clock.Now
+	t.Cleanup(func() {
+		currentTime = previous
+	})
+	return clock
+}
+
+func (c *simulatedClock) Now() time.Time {
+	c.Lock()
+	defer c.Unlock()
+	return c.now
+}
+
+func (c *simulatedClock) Advance(d time.Duration) time.Time {
+	c.Lock()
+	defer c.Unlock()
+	c.now = c.now.Add(d)
+	return c.now
+}
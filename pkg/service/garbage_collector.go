@@ -0,0 +1,192 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aerospike/backup/pkg/model"
+	"github.com/reugn/go-quartz/quartz"
+)
+
+const garbageCollectorJobKey = "garbage-collector"
+
+var (
+	// garbageCollectorMutex prevents concurrent garbage collector runs.
+	garbageCollectorMutex sync.Mutex
+	// lastGarbageReport holds the report of the latest garbage collector run.
+	lastGarbageReport atomic.Pointer[model.GarbageReport]
+)
+
+// CollectGarbage finds the folders left behind by failed backups of all the routines,
+// i.e. backup folders without metadata and empty configuration folders older than
+// the grace period. The folders are deleted if configured.
+func CollectGarbage(config *model.Config, backends BackendsHolder, now time.Time) *model.GarbageReport {
+	garbageCollectorMutex.Lock()
+	defer garbageCollectorMutex.Unlock()
+
+	gcConfig := config.ServiceConfig.GarbageCollector
+	gracePeriod := time.Duration(gcConfig.GetGracePeriodOrDefault()) * time.Millisecond
+	report := &model.GarbageReport{Created: now, Folders: []model.GarbageFolder{}}
+	for routineName := range config.BackupRoutines {
+		backend, found := backends.Get(routineName)
+		if !found {
+			continue
+		}
+		for _, folder := range backend.findGarbage(routineName, now.Add(-gracePeriod)) {
+			if gcConfig.IsDelete() {
+				if err := backend.DeleteFolder(folder.Path); err != nil {
+					slog.Error("Could not delete garbage folder", "name", routineName,
+						"path", folder.Path, "err", err)
+				} else {
+					folder.Deleted = true
+					garbageDeletedCounter.Inc()
+				}
+			}
+			slog.Info("Found garbage folder", "name", routineName, "path", folder.Path,
+				"reason", folder.Reason, "deleted", folder.Deleted)
+			report.Folders = append(report.Folders, folder)
+		}
+	}
+
+	garbageFoldersGauge.Set(float64(len(report.Folders)))
+	lastGarbageReport.Store(report)
+	return report
+}
+
+// LastGarbageReport returns the report of the latest garbage collector run,
+// or nil if the garbage collector has not run yet.
+func LastGarbageReport() *model.GarbageReport {
+	return lastGarbageReport.Load()
+}
+
+// findGarbage returns the garbage folders of the routine created before the given time.
+func (b *BackupBackend) findGarbage(routineName string, before time.Time) []model.GarbageFolder {
+	var garbage []model.GarbageFolder
+	var backupsPaths []string
+	// the folder of a running backup has no metadata yet
+	if !b.incrementalInProgress.Load() {
+		backupsPaths = append(backupsPaths, b.incrementalBackupsPath)
+	}
+	if !b.fullBackupInProgress.Load() {
+		backupsPaths = append(backupsPaths, b.fullBackupsPath)
+	}
+	for _, backupsPath := range backupsPaths {
+		folders, err := b.lsDir(backupsPath)
+		if err != nil {
+			slog.Warn("Cannot list backup dir", "path", backupsPath, "err", err)
+			continue
+		}
+		for _, folder := range folders {
+			// only timestamp folders are checked, backup data of the RemoveAll policy is overwritten
+			timestamp, err := strconv.ParseInt(filepath.Base(folder), 10, 64)
+			if err != nil {
+				continue
+			}
+			created := time.UnixMilli(timestamp)
+			if !created.Before(before) {
+				continue
+			}
+			for path, reason := range b.garbageInFolder(folder) {
+				garbage = append(garbage, model.GarbageFolder{
+					Routine: routineName,
+					Path:    path,
+					Reason:  reason,
+					Created: created,
+				})
+			}
+		}
+	}
+	slices.SortFunc(garbage, func(a, b model.GarbageFolder) int {
+		return cmp.Or(a.Created.Compare(b.Created), strings.Compare(a.Path, b.Path))
+	})
+	return garbage
+}
+
+// garbageInFolder returns the garbage paths in the backup timestamp folder
+// mapped to the reason.
+func (b *BackupBackend) garbageInFolder(folder string) map[string]string {
+	garbage := make(map[string]string)
+	namespaces, err := b.lsDir(filepath.Join(folder, model.DataDirectory))
+	if err != nil {
+		slog.Warn("Cannot list backup dir", "path", folder, "err", err)
+		return garbage
+	}
+	if len(namespaces) == 0 {
+		garbage[folder] = "missing backup data"
+		return garbage
+	}
+	var partial []string
+	for _, namespacePath := range namespaces {
+		files, err := b.lsFiles(namespacePath)
+		if err != nil {
+			slog.Warn("Cannot list backup files", "path", namespacePath, "err", err)
+			return garbage
+		}
+		if !slices.ContainsFunc(files, func(file string) bool {
+			return filepath.Base(file) == metadataFile
+		}) {
+			partial = append(partial, namespacePath)
+		}
+	}
+	// the whole folder is garbage if no namespace was backed up successfully
+	if len(partial) == len(namespaces) {
+		garbage[folder] = "missing metadata"
+		return garbage
+	}
+	for _, namespacePath := range partial {
+		garbage[namespacePath] = "missing metadata"
+	}
+
+	subfolders, err := b.lsDir(folder)
+	if err != nil {
+		return garbage
+	}
+	configurationPath := filepath.Join(folder, model.ConfigurationBackupDirectory)
+	if slices.Contains(subfolders, configurationPath) {
+		files, err := b.lsFiles(configurationPath)
+		if err == nil && len(files) == 0 {
+			garbage[configurationPath] = "empty configuration"
+		}
+	}
+	return garbage
+}
+
+// garbageCollectorJob implements the quartz.Job interface.
+type garbageCollectorJob struct {
+	config   *model.Config
+	backends BackendsHolder
+}
+
+var _ quartz.Job = (*garbageCollectorJob)(nil)
+
+// Execute is called by a Scheduler when the Trigger associated with this job fires.
+func (j *garbageCollectorJob) Execute(_ context.Context) error {
+	CollectGarbage(j.config, j.backends, currentTime())
+	return nil
+}
+
+// Description returns the description of the garbage collector job.
+func (j *garbageCollectorJob) Description() string {
+	return "garbage collector job"
+}
+
+func scheduleGarbageCollector(scheduler quartz.Scheduler, config *model.Config, backends BackendsHolder) error {
+	gcConfig := config.ServiceConfig.GarbageCollector
+	if !gcConfig.IsEnabled() {
+		return nil
+	}
+	trigger := quartz.NewSimpleTrigger(time.Duration(gcConfig.GetIntervalOrDefault()) * time.Millisecond)
+	jobDetail := quartz.NewJobDetail(
+		&garbageCollectorJob{config: config, backends: backends},
+		quartz.NewJobKey(garbageCollectorJobKey),
+	)
+	return scheduler.ScheduleJob(jobDetail, trigger)
+}
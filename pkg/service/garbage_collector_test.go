@@ -0,0 +1,62 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aerospike/backup/pkg/model"
+	"github.com/aerospike/backup/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectGarbage(t *testing.T) {
+	backend := newDiskBackend(t, NewOSDiskAccessor())
+	now := time.UnixMilli(10_000_000)
+	old, recent := now.Add(-2*time.Hour), now.Add(-time.Minute)
+
+	// a complete namespace, a partial namespace and an empty configuration folder
+	complete := writeFullBackup(t, backend, model.BackupMetadata{Created: old, Namespace: "ns1"})
+	partial := getFullPath(backend.fullBackupsPath, &model.BackupPolicy{}, "ns2", old)
+	require.NoError(t, os.MkdirAll(partial, 0744))
+	require.NoError(t, os.WriteFile(filepath.Join(partial, "ns2_0.asb"), []byte{}, 0644))
+	configuration := getConfigurationPath(backend.fullBackupsPath, &model.BackupPolicy{}, old)
+	require.NoError(t, os.MkdirAll(configuration, 0744))
+	// a partial incremental backup and a recent one within the grace period
+	require.NoError(t, os.MkdirAll(getIncrementalPath(backend.incrementalBackupsPath, "ns1", old), 0744))
+	partialIncremental := filepath.Join(backend.incrementalBackupsPath, timeSuffix(old))
+	require.NoError(t, os.MkdirAll(getIncrementalPath(backend.incrementalBackupsPath, "ns1", recent), 0744))
+
+	config := model.NewConfigWithDefaultValues()
+	config.BackupRoutines["routine"] = &model.BackupRoutine{}
+	config.ServiceConfig.GarbageCollector.GracePeriod = util.Ptr(time.Hour.Milliseconds())
+	backends := &BackendHolderImpl{data: map[string]*BackupBackend{"routine": backend}}
+
+	// the folders of a running backup are not garbage
+	backend.incrementalInProgress.Store(true)
+	backend.fullBackupInProgress.Store(true)
+	assert.Empty(t, CollectGarbage(config, backends, now).Folders)
+	backend.incrementalInProgress.Store(false)
+	backend.fullBackupInProgress.Store(false)
+
+	report := CollectGarbage(config, backends, now)
+	assert.Equal(t, report, LastGarbageReport())
+	paths := make([]string, 0, len(report.Folders))
+	for _, folder := range report.Folders {
+		assert.False(t, folder.Deleted)
+		paths = append(paths, folder.Path)
+	}
+	assert.ElementsMatch(t, []string{partial, configuration, partialIncremental}, paths)
+
+	config.ServiceConfig.GarbageCollector.Delete = util.Ptr(true)
+	report = CollectGarbage(config, backends, now)
+	require.Len(t, report.Folders, 3)
+	for _, folder := range report.Folders {
+		assert.True(t, folder.Deleted)
+		assert.NoDirExists(t, folder.Path)
+	}
+	assert.DirExists(t, complete)
+	assert.Empty(t, CollectGarbage(config, backends, now).Folders)
+}
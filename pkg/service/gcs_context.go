@@ -0,0 +1,315 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/aerospike/backup/pkg/model"
+	"github.com/aerospike/backup/pkg/util"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	gcsProtocol           = "gs://"
+	gcsBucketCheckTimeout = 10 * time.Second
+)
+
+// GcsContext is responsible for performing basic operations on Google Cloud Storage.
+// The shared library cannot write to GCS directly, so the backup files are staged
+// in a local folder and uploaded once the backup is completed.
+type GcsContext struct {
+	*localStaging
+	ctx           context.Context
+	client        *storage.Client
+	bucket        *storage.BucketHandle
+	bucketName    string
+	path          string
+	metadataCache *util.LoadingCache[string, *model.BackupMetadata]
+}
+
+var _ StorageAccessor = (*GcsContext)(nil)
+var _ stagedStorage = (*GcsContext)(nil)
+
+// NewGcsContext returns a new GcsContext.
+func NewGcsContext(config *model.Storage) (*GcsContext, error) {
+	ctx := context.TODO()
+	var options []option.ClientOption
+	if config.GcpCredentialsFile != nil && *config.GcpCredentialsFile != "" {
+		options = append(options, option.WithCredentialsFile(*config.GcpCredentialsFile))
+	}
+	if config.GcpEndpointOverride != nil && *config.GcpEndpointOverride != "" {
+		options = append(options, option.WithEndpoint(*config.GcpEndpointOverride))
+		if config.GcpCredentialsFile == nil {
+			// local emulators don't require authentication
+			options = append(options, option.WithoutAuthentication())
+		}
+	}
+	client, err := storage.NewClient(ctx, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCS client: %v", err)
+	}
+
+	bucketName := *config.GcpBucket
+	bucket := client.Bucket(bucketName)
+	// Check if the bucket exists, the client retries until the context is done
+	checkCtx, cancel := context.WithTimeout(ctx, gcsBucketCheckTimeout)
+	defer cancel()
+	if _, err = bucket.Attrs(checkCtx); err != nil {
+		return nil, fmt.Errorf("error checking GCS bucket %s existence: %v", bucketName, err)
+	}
+
+	g := &GcsContext{
+		ctx:        ctx,
+		client:     client,
+		bucket:     bucket,
+		bucketName: bucketName,
+		path:       strings.Trim(*config.Path, "/"),
+	}
+	g.localStaging = newLocalStaging(g, "gcs-"+bucketName)
+
+	g.metadataCache = util.NewLoadingCache(ctx, func(path string) (*model.BackupMetadata, error) {
+		return g.readMetadata(path)
+	})
+	return g, nil
+}
+
+func (g *GcsContext) readBackupState(stateFilePath string, state *model.BackupState) error {
+	err := g.readFile(stateFilePath, state)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		slog.Debug("State file does not exist for backup", "path", stateFilePath)
+		return nil
+	}
+	return err
+}
+
+func (g *GcsContext) readBackupDetails(path string, useCache bool) (model.BackupDetails, error) {
+	var metadata *model.BackupMetadata
+	var err error
+	if useCache {
+		metadata, err = g.metadataCache.Get(path)
+	} else {
+		metadata, err = g.readMetadata(path)
+	}
+	if err != nil {
+		return model.BackupDetails{}, err
+	}
+	return model.BackupDetails{
+		BackupMetadata: *metadata,
+		Key:            util.Ptr(gcsProtocol + g.bucketName + "/" + path),
+	}, nil
+}
+
+func (g *GcsContext) read(filePath string) ([]byte, error) {
+	reader, err := g.bucket.Object(filePath).NewReader(g.ctx)
+	if err != nil {
+		if !errors.Is(err, storage.ErrObjectNotExist) {
+			slog.Warn("Failed to read file", "path", filePath, "err", err)
+		}
+		return nil, err
+	}
+	defer reader.Close()
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		slog.Warn("Couldn't read object body of a file", "path", filePath, "err", err)
+		return nil, err
+	}
+	return content, nil
+}
+
+func (g *GcsContext) open(filePath string) (io.ReadCloser, error) {
+	return g.bucket.Object(filePath).NewReader(g.ctx)
+}
+
+// create uploads the file, the object is not created if the writer is closed with an error.
+func (g *GcsContext) create(filePath string) (streamWriter, error) {
+	ctx, cancel := context.WithCancel(g.ctx)
+	writer := g.bucket.Object(filePath).NewWriter(ctx)
+	return newPipeWriter(func(reader io.Reader) error {
+		defer cancel()
+		if _, err := io.Copy(writer, reader); err != nil {
+			// cancelling the context discards the upload
+			cancel()
+			_ = writer.Close()
+			slog.Warn("Couldn't upload file", "path", filePath, "bucket", g.bucketName, "err", err)
+			return err
+		}
+		if err := writer.Close(); err != nil {
+			slog.Warn("Couldn't upload file", "path", filePath, "bucket", g.bucketName, "err", err)
+			return err
+		}
+		g.fileWritten(filePath)
+		return nil
+	}), nil
+}
+
+// readFile reads and decodes the YAML content from the given filePath into v.
+func (g *GcsContext) readFile(filePath string, v any) error {
+	content, err := g.read(filePath)
+	if err != nil {
+		return err
+	}
+	if err = yaml.Unmarshal(content, v); err != nil {
+		slog.Warn("Failed unmarshal file", "path", filePath, "err", err,
+			"content", string(content))
+		return err
+	}
+	return nil
+}
+
+// writeYaml writes v into filepath using the YAML format.
+func (g *GcsContext) writeYaml(filePath string, v any) error {
+	yamlData, err := yaml.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return g.write(filePath, yamlData)
+}
+
+func (g *GcsContext) write(filePath string, data []byte) error {
+	writer := g.bucket.Object(filePath).NewWriter(g.ctx)
+	if _, err := writer.Write(data); err != nil {
+		_ = writer.Close()
+		slog.Warn("Couldn't upload file", "path", filePath, "bucket", g.bucketName, "err", err)
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		slog.Warn("Couldn't upload file", "path", filePath, "bucket", g.bucketName, "err", err)
+		return err
+	}
+	g.fileWritten(filePath)
+	return nil
+}
+
+// fileWritten invalidates the cached metadata of the written file.
+func (g *GcsContext) fileWritten(filePath string) {
+	if filepath.Base(filePath) == metadataFile {
+		g.metadataCache.Invalidate(filepath.Dir(filePath))
+	}
+	slog.Debug("File written", "path", filePath, "bucket", g.bucketName)
+}
+
+func (g *GcsContext) fileSize(filePath string) (int64, error) {
+	attrs, err := g.bucket.Object(filePath).Attrs(g.ctx)
+	if err != nil {
+		return 0, err
+	}
+	return attrs.Size, nil
+}
+
+// lsFiles returns all files in the given GCS prefix path, recursively.
+func (g *GcsContext) lsFiles(prefix string) ([]string, error) {
+	var result []string
+	it := g.bucket.Objects(g.ctx, &storage.Query{Prefix: strings.TrimSuffix(prefix, "/") + "/"})
+	for {
+		attrs, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			slog.Warn("Couldn't list objects in folder", "prefix", prefix, "err", err)
+			return nil, err
+		}
+		result = append(result, attrs.Name)
+	}
+	return result, nil
+}
+
+// lsDir returns all subfolders in the given GCS prefix path.
+func (g *GcsContext) lsDir(prefix string) ([]string, error) {
+	result := make([]string, 0)
+	it := g.bucket.Objects(g.ctx, &storage.Query{
+		Prefix:    strings.TrimSuffix(prefix, "/") + "/",
+		Delimiter: "/",
+	})
+	for {
+		attrs, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			slog.Warn("Couldn't list objects in folder", "prefix", prefix, "err", err)
+			return nil, err
+		}
+		// objects have an empty prefix, only subfolders are returned
+		if attrs.Prefix != "" {
+			result = append(result, strings.TrimSuffix(attrs.Prefix, "/"))
+		}
+	}
+	return result, nil
+}
+
+// CreateFolder creates the local staging folder for the given path.
+// GCS doesn't require to create folders.
+func (g *GcsContext) CreateFolder(path string) {
+	g.createStagingFolder(path)
+}
+
+func (g *GcsContext) readMetadata(path string) (*model.BackupMetadata, error) {
+	metadata := &model.BackupMetadata{}
+	metadataFilePath := filepath.Join(path, metadataFile)
+	err := g.readFile(metadataFilePath, metadata)
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("Read metadata file", "path", path, "data", metadata)
+	return metadata, nil
+}
+
+func (g *GcsContext) DeleteFolder(folder string) error {
+	slog.Debug("Delete folder", "path", folder)
+	g.removeStaged(folder)
+	files, err := g.lsFiles(folder)
+	if err != nil {
+		slog.Warn("Couldn't list files in directory", "path", folder, "err", err)
+		return err
+	}
+
+	for _, file := range files {
+		if err := g.deleteFile(file); err != nil {
+			slog.Debug("Couldn't delete file", "path", file, "err", err)
+		}
+	}
+	return nil
+}
+
+func (g *GcsContext) deleteFile(path string) error {
+	if err := g.bucket.Object(path).Delete(g.ctx); err != nil {
+		return err
+	}
+	if filepath.Base(path) == metadataFile {
+		g.metadataCache.Invalidate(filepath.Dir(path))
+	}
+	return nil
+}
+
+// wrapWithPrefix returns the local staging folder of the path,
+// which is uploaded to GCS after the backup.
+func (g *GcsContext) wrapWithPrefix(path string) *string {
+	result := g.stagingFolder(path)
+	return &result
+}
+
+func (g *GcsContext) validateStorageContainsBackup() error {
+	files, err := g.lsFiles(g.path)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("given path %s not exist", g.path)
+	}
+	for _, file := range files {
+		if strings.HasSuffix(file, ".asb") {
+			return nil
+		}
+	}
+	return fmt.Errorf("no backup files found in %s", g.path)
+}
@@ -0,0 +1,126 @@
+//go:build !ci
+
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aerospike/backup/pkg/model"
+	"github.com/aws/smithy-go/ptr"
+)
+
+// newFakeGcsContext returns a GcsContext for the fake-gcs-server from docker-compose.
+func newFakeGcsContext(t *testing.T) *GcsContext {
+	t.Helper()
+	context, err := NewGcsContext(&model.Storage{
+		Type:                model.GcpGCS,
+		Path:                ptr.String("storageGcs"),
+		GcpBucket:           ptr.String("as-backup-bucket"),
+		GcpEndpointOverride: ptr.String("http://localhost:4443/storage/v1/"),
+	})
+	if err != nil {
+		t.Skip("fake GCS server is not available:", err)
+	}
+	t.Cleanup(func() {
+		_ = context.DeleteFolder(context.path)
+	})
+	return context
+}
+
+func TestGcsContext_ReadWriteMetadata(t *testing.T) {
+	context := newFakeGcsContext(t)
+	path := filepath.Join(context.path, "backup", "10", "data", "ns1")
+	metadataWrite := model.BackupMetadata{
+		Namespace: "testNS",
+		Created:   time.UnixMilli(10),
+	}
+	if err := context.writeYaml(filepath.Join(path, metadataFile), metadataWrite); err != nil {
+		t.Fatal(err)
+	}
+
+	details, err := context.readBackupDetails(path, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if details.Namespace != metadataWrite.Namespace || !details.Created.Equal(metadataWrite.Created) {
+		t.Errorf("Expected %v, got %v", metadataWrite, details.BackupMetadata)
+	}
+	if *details.Key != "gs://as-backup-bucket/"+path {
+		t.Errorf("Unexpected key %s", *details.Key)
+	}
+
+	dirs, _ := context.lsDir(filepath.Join(context.path, "backup"))
+	if len(dirs) != 1 || dirs[0] != filepath.Join(context.path, "backup", "10") {
+		t.Errorf("Unexpected subfolders %v", dirs)
+	}
+
+	state := model.NewBackupState()
+	if err := context.readBackupState(filepath.Join(context.path, model.StateFileName), state); err != nil {
+		t.Errorf("Expected no error for missing state, got %v", err)
+	}
+}
+
+func TestGcsContext_DeleteFolder(t *testing.T) {
+	context := newFakeGcsContext(t)
+	folder1 := filepath.Join(context.path, "incremental", "source-ns1")
+	folder2 := filepath.Join(context.path, "incremental", "source-ns16")
+	_ = context.writeYaml(folder1+"/file1.txt", "data")
+	_ = context.writeYaml(folder2+"/file2.txt", "data")
+
+	if err := context.DeleteFolder(folder1); err != nil {
+		t.Error("Error deleting", err)
+	}
+	if files, _ := context.lsFiles(folder1); len(files) != 0 {
+		t.Error("file 1 not deleted")
+	}
+	if files, _ := context.lsFiles(folder2); len(files) != 1 {
+		t.Error("file 2 was deleted")
+	}
+}
+
+func TestGcsContext_UploadDownload(t *testing.T) {
+	context := newFakeGcsContext(t)
+	path := filepath.Join(context.path, "backup", "data", "ns1")
+	_ = context.write(filepath.Join(path, "old.asb"), []byte("old"))
+
+	context.CreateFolder(path)
+	stagingFolder := *context.wrapWithPrefix(path)
+	_ = os.WriteFile(filepath.Join(stagingFolder, "ns1_0.asb"), []byte("data"), 0644)
+	backend := &BackupBackend{StorageAccessor: context}
+	if err := backend.completeStaged(path, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(stagingFolder); !os.IsNotExist(err) {
+		t.Errorf("Expected staging folder removed, got %v", err)
+	}
+	files, _ := context.lsFiles(path)
+	if len(files) != 1 || files[0] != filepath.Join(path, "ns1_0.asb") {
+		t.Errorf("Expected the uploaded file only, got %v", files)
+	}
+
+	localDir := t.TempDir()
+	if err := context.download(path, localDir); err != nil {
+		t.Fatal(err)
+	}
+	content, _ := os.ReadFile(filepath.Join(localDir, "ns1_0.asb"))
+	if string(content) != "data" {
+		t.Errorf("Expected downloaded content, got %s", content)
+	}
+}
+
+func TestGcsContext_ConfigurationManager(t *testing.T) {
+	context := newFakeGcsContext(t)
+	manager := &StorageConfigurationManager{
+		StorageAccessor: context,
+		path:            filepath.Join(context.path, "config.yml"),
+	}
+	if err := manager.WriteConfiguration(model.NewConfigWithDefaultValues()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := manager.ReadConfiguration(); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
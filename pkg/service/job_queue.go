@@ -0,0 +1,183 @@
+package service
+
+import (
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aerospike/backup/pkg/model"
+)
+
+// jobQueue runs the backup and restore jobs of the service in priority order, and
+// in the order of arrival within a priority, while keeping the number of running
+// jobs within the global and the per-cluster limits.
+// A waiting job whose cluster or library is busy does not hold back the other jobs.
+//
+// The asbackup and asrestore shared libraries are not reentrant, so at most one job
+// runs each of them, and a backup job may run along with a restore job only.
+// The jobs run one at a time in the exclusive mode, used when the library logs are
+// captured from the stderr of the process.
+type jobQueue struct {
+	mu        sync.Mutex
+	config    *model.JobQueueConfig
+	exclusive bool
+	nextID    int64
+	waiting   []*queueEntry
+	running   []*queueEntry
+}
+
+type queueEntry struct {
+	id    int64 // the arrival order
+	job   model.QueuedJob
+	ready chan struct{}
+}
+
+// jobs is the service-wide job queue, configured on the schedule of the routines.
+var jobs = &jobQueue{}
+
+// configure applies the job queue configuration to the waiting and the next jobs.
+func (q *jobQueue) configure(config *model.JobQueueConfig, exclusive bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.config = config
+	q.exclusive = exclusive
+	q.dispatch()
+}
+
+// sharedLibrary returns the shared library the job of the given type runs.
+func sharedLibrary(jobType string) string {
+	if jobType == model.JobTypeRestore {
+		return "asrestore"
+	}
+	return "asbackup"
+}
+
+// run waits for the turn of the job in the queue, then runs f.
+func (q *jobQueue) run(job model.QueuedJob, f func()) {
+	entry := q.enqueue(job)
+	<-entry.ready
+	waited := entry.job.Started.Sub(entry.job.Queued)
+	queueWaitHistogram.WithLabelValues(job.Type).Observe(waited.Seconds())
+	slog.Debug("Job started", "type", job.Type, "routine", job.Routine,
+		"namespace", job.Namespace, "waited", waited)
+	defer q.done(entry)
+	f()
+}
+
+func (q *jobQueue) enqueue(job model.QueuedJob) *queueEntry {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.nextID++
+	job.Queued = time.Now()
+	entry := &queueEntry{id: q.nextID, job: job, ready: make(chan struct{})}
+	q.waiting = append(q.waiting, entry)
+	q.dispatch()
+	return entry
+}
+
+func (q *jobQueue) done(entry *queueEntry) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i, running := range q.running {
+		if running == entry {
+			q.running = append(q.running[:i], q.running[i+1:]...)
+			break
+		}
+	}
+	q.dispatch()
+}
+
+// dispatch starts the waiting jobs allowed by the concurrency limits, in the
+// queue order. Must be called with the lock held.
+func (q *jobQueue) dispatch() {
+	for _, entry := range q.waiting {
+		entry.job.Priority = q.config.GetPriorityOrDefault(entry.job.Type)
+	}
+	sort.SliceStable(q.waiting, func(i, j int) bool {
+		if q.waiting[i].job.Priority != q.waiting[j].job.Priority {
+			return q.waiting[i].job.Priority > q.waiting[j].job.Priority
+		}
+		return q.waiting[i].id < q.waiting[j].id
+	})
+
+	perCluster := make(map[string]int)
+	busyLibraries := make(map[string]bool)
+	for _, entry := range q.running {
+		perCluster[entry.job.Cluster]++
+		busyLibraries[sharedLibrary(entry.job.Type)] = true
+	}
+	maxJobs := q.config.GetMaxConcurrentJobsOrDefault()
+	if q.exclusive {
+		maxJobs = 1
+	}
+	maxJobsPerCluster := q.config.GetMaxConcurrentJobsPerClusterOrDefault()
+	waiting := q.waiting[:0]
+	for _, entry := range q.waiting {
+		library := sharedLibrary(entry.job.Type)
+		if len(q.running) >= maxJobs || perCluster[entry.job.Cluster] >= maxJobsPerCluster ||
+			busyLibraries[library] {
+			waiting = append(waiting, entry)
+			continue
+		}
+		started := time.Now()
+		entry.job.Started = &started
+		perCluster[entry.job.Cluster]++
+		busyLibraries[library] = true
+		q.running = append(q.running, entry)
+		close(entry.ready)
+	}
+	clear(q.waiting[len(waiting):])
+	q.waiting = waiting
+
+	queuedJobsGauge.Reset()
+	for _, entry := range q.waiting {
+		queuedJobsGauge.WithLabelValues(entry.job.Type).Inc()
+	}
+}
+
+// list returns the running jobs, followed by the waiting jobs in the queue order.
+func (q *jobQueue) list() []model.QueuedJob {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	list := make([]model.QueuedJob, 0, len(q.running)+len(q.waiting))
+	for _, entry := range q.running {
+		job := entry.job
+		job.State = model.QueuedJobRunning
+		list = append(list, job)
+	}
+	for i, entry := range q.waiting {
+		job := entry.job
+		job.State = model.QueuedJobWaiting
+		job.Position = i + 1
+		list = append(list, job)
+	}
+	return list
+}
+
+// restorePosition returns the queue position of the waiting restore job with the
+// given id, or zero if the job is not waiting.
+func (q *jobQueue) restorePosition(jobID int) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i, entry := range q.waiting {
+		if entry.job.Type == model.JobTypeRestore && entry.job.JobID == jobID {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// QueuedJobs returns the jobs of the service-wide job queue, the running ones first,
+// followed by the waiting ones in the order they will run.
+func QueuedJobs() []model.QueuedJob {
+	return jobs.list()
+}
+
+// clusterKey identifies the cluster for the per-cluster concurrency limit.
+func clusterKey(cluster *model.AerospikeCluster) string {
+	if cluster == nil {
+		return ""
+	}
+	return *cluster.SeedNodesAsString()
+}
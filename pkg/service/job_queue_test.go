@@ -0,0 +1,162 @@
+package service
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aerospike/backup/pkg/model"
+	"github.com/aerospike/backup/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// queueRecorder runs the jobs through the queue and records the order they start in.
+type queueRecorder struct {
+	mu      sync.Mutex
+	started []string
+	wg      sync.WaitGroup
+}
+
+// submit runs the job named after its routine in the background, until release is closed.
+func (r *queueRecorder) submit(q *jobQueue, job model.QueuedJob, release <-chan struct{}) {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		q.run(job, func() {
+			r.mu.Lock()
+			r.started = append(r.started, job.Routine)
+			r.mu.Unlock()
+			<-release
+		})
+	}()
+}
+
+func (r *queueRecorder) startedJobs() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.started...)
+}
+
+func waitForQueue(t *testing.T, q *jobQueue, size int) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		return len(q.list()) == size
+	}, 5*time.Second, time.Millisecond)
+}
+
+func TestJobQueue_Priorities(t *testing.T) {
+	q := &jobQueue{}
+	recorder := &queueRecorder{}
+	blocker := make(chan struct{})
+	release := make(chan struct{})
+	close(release)
+
+	recorder.submit(q, model.QueuedJob{Type: model.JobTypeFull, Routine: "blocker", Cluster: "a"}, blocker)
+	waitForQueue(t, q, 1)
+	recorder.submit(q, model.QueuedJob{Type: model.JobTypeFull, Routine: "full1", Cluster: "a"}, release)
+	waitForQueue(t, q, 2)
+	recorder.submit(q, model.QueuedJob{Type: model.JobTypeIncremental, Routine: "incremental", Cluster: "a"}, release)
+	waitForQueue(t, q, 3)
+	recorder.submit(q, model.QueuedJob{Type: model.JobTypeFull, Routine: "full2", Cluster: "a"}, release)
+	waitForQueue(t, q, 4)
+	recorder.submit(q, model.QueuedJob{Type: model.JobTypeRestore, Routine: "restore", Cluster: "a", JobID: 7},
+		release)
+	waitForQueue(t, q, 5)
+
+	queued := q.list()
+	assert.Equal(t, model.QueuedJobRunning, queued[0].State)
+	assert.Equal(t, "blocker", queued[0].Routine)
+	assert.NotNil(t, queued[0].Started)
+	assert.Equal(t, []string{"restore", "incremental", "full1", "full2"},
+		[]string{queued[1].Routine, queued[2].Routine, queued[3].Routine, queued[4].Routine})
+	assert.Equal(t, model.QueuedJobWaiting, queued[1].State)
+	assert.Equal(t, 1, queued[1].Position)
+	assert.Equal(t, 3, queued[1].Priority)
+	assert.Equal(t, 4, queued[4].Position)
+	assert.Equal(t, 1, q.restorePosition(7))
+	assert.Equal(t, 0, q.restorePosition(8))
+
+	close(blocker)
+	recorder.wg.Wait()
+	assert.Equal(t, []string{"blocker", "restore", "incremental", "full1", "full2"}, recorder.startedJobs())
+	assert.Empty(t, q.list())
+}
+
+func TestJobQueue_ConcurrencyLimits(t *testing.T) {
+	q := &jobQueue{}
+	q.configure(&model.JobQueueConfig{MaxConcurrentJobs: util.Ptr(2)}, false)
+	recorder := &queueRecorder{}
+	release := make(chan struct{})
+
+	recorder.submit(q, model.QueuedJob{Type: model.JobTypeRestore, Routine: "a1", Cluster: "a"}, release)
+	waitForQueue(t, q, 1)
+	// the restore library is busy
+	recorder.submit(q, model.QueuedJob{Type: model.JobTypeRestore, Routine: "b1", Cluster: "b"}, release)
+	waitForQueue(t, q, 2)
+	// the cluster is at its limit
+	recorder.submit(q, model.QueuedJob{Type: model.JobTypeFull, Routine: "a2", Cluster: "a"}, release)
+	waitForQueue(t, q, 3)
+	// the lower priority job is not held back by the busy cluster and library
+	recorder.submit(q, model.QueuedJob{Type: model.JobTypeFull, Routine: "c1", Cluster: "c"}, release)
+	waitForQueue(t, q, 4)
+
+	require.Eventually(t, func() bool {
+		return len(recorder.startedJobs()) == 2
+	}, 5*time.Second, time.Millisecond)
+	assert.Equal(t, []string{"a1", "c1"}, recorder.startedJobs())
+	states := make(map[string]string)
+	for _, job := range q.list() {
+		states[job.Routine] = job.State
+	}
+	assert.Equal(t, map[string]string{"a1": model.QueuedJobRunning, "c1": model.QueuedJobRunning,
+		"b1": model.QueuedJobWaiting, "a2": model.QueuedJobWaiting}, states)
+
+	close(release)
+	recorder.wg.Wait()
+	assert.ElementsMatch(t, []string{"a1", "c1", "b1", "a2"}, recorder.startedJobs())
+}
+
+// overlapping runs the jobs through the queue, each waiting for the other one to start
+// for the given time, and returns whether both jobs ran at the same time.
+func overlapping(q *jobQueue, first, second model.QueuedJob, timeout time.Duration) bool {
+	var wg sync.WaitGroup
+	overlapped := make(chan bool, 2)
+	run := func(job model.QueuedJob, started chan<- struct{}, other <-chan struct{}) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			q.run(job, func() {
+				close(started)
+				select {
+				case <-other:
+					overlapped <- true
+				case <-time.After(timeout):
+					overlapped <- false
+				}
+			})
+		}()
+	}
+	firstStarted, secondStarted := make(chan struct{}), make(chan struct{})
+	run(first, firstStarted, secondStarted)
+	run(second, secondStarted, firstStarted)
+	wg.Wait()
+	return <-overlapped && <-overlapped
+}
+
+func TestJobQueue_Overlap(t *testing.T) {
+	backup := model.QueuedJob{Type: model.JobTypeFull, Routine: "backup", Cluster: "a"}
+	restore := model.QueuedJob{Type: model.JobTypeRestore, Routine: "restore", Cluster: "b"}
+	otherBackup := model.QueuedJob{Type: model.JobTypeIncremental, Routine: "other", Cluster: "b"}
+
+	q := &jobQueue{}
+	assert.True(t, overlapping(q, backup, restore, 5*time.Second),
+		"a backup and a restore job are expected to run at the same time")
+	assert.False(t, overlapping(q, backup, otherBackup, 50*time.Millisecond),
+		"the backup jobs are expected to run one at a time")
+
+	q.configure(nil, true)
+	assert.False(t, overlapping(q, backup, restore, 50*time.Millisecond),
+		"the jobs are expected to run one at a time in the exclusive mode")
+	assert.Empty(t, q.list())
+}
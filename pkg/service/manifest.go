@@ -0,0 +1,158 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aerospike/backup/pkg/model"
+	"gopkg.in/yaml.v3"
+)
+
+// manifestFile is the name of the file with the checksums of the backup files,
+// stored next to the metadata file.
+const manifestFile = "manifest.yaml"
+
+// manifestFolder is a folder of files to include in the backup manifest.
+type manifestFolder struct {
+	path   string
+	filter func(file string) bool
+}
+
+// backupDataFiles returns the manifest folder of the backup files of a namespace.
+func backupDataFiles(path string) manifestFolder {
+	return manifestFolder{path: path, filter: func(file string) bool {
+		return filepath.Ext(file) == ".asb"
+	}}
+}
+
+// configurationFiles returns the manifest folder of the cluster configuration files.
+func configurationFiles(path string) manifestFolder {
+	return manifestFolder{path: path, filter: func(file string) bool {
+		return filepath.Ext(file) == ".conf"
+	}}
+}
+
+// writeManifest computes the checksums of the files in the given folders and writes
+// the manifest next to the metadata of the backup at the given path.
+// The paths in the manifest are relative to the backup timestamp folder.
+func (b *BackupBackend) writeManifest(path string, folders ...manifestFolder) error {
+	// path is <backup folder>/data/<namespace>
+	root := filepath.Dir(filepath.Dir(path))
+	manifest := model.BackupManifest{Algorithm: model.ManifestAlgorithm, Files: []model.ManifestFile{}}
+	for _, folder := range folders {
+		files, err := b.manifestFiles(folder)
+		if err != nil {
+			return err
+		}
+		for _, file := range files {
+			entry, err := b.checksum(file)
+			if err != nil {
+				return fmt.Errorf("failed to compute checksum of %s: %w", file, err)
+			}
+			entry.Path, err = filepath.Rel(root, file)
+			if err != nil {
+				return err
+			}
+			manifest.Files = append(manifest.Files, entry)
+		}
+	}
+	slices.SortFunc(manifest.Files, func(a, b model.ManifestFile) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return b.writeYaml(filepath.Join(path, manifestFile), manifest)
+}
+
+// manifestFiles returns the files of the folder to include in the manifest.
+func (b *BackupBackend) manifestFiles(folder manifestFolder) ([]string, error) {
+	files, err := b.lsFiles(folder.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files in %s: %w", folder.path, err)
+	}
+	// the listing of some storages is recursive
+	return slices.DeleteFunc(files, func(file string) bool {
+		return filepath.Dir(file) != filepath.Clean(folder.path) || !folder.filter(file)
+	}), nil
+}
+
+// checksum computes the checksum of the file, streaming its content.
+func (b *BackupBackend) checksum(file string) (model.ManifestFile, error) {
+	reader, err := b.open(file)
+	if err != nil {
+		return model.ManifestFile{}, err
+	}
+	defer reader.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, reader)
+	if err != nil {
+		return model.ManifestFile{}, err
+	}
+	return model.ManifestFile{Size: size, Checksum: hex.EncodeToString(hash.Sum(nil))}, nil
+}
+
+// readManifest reads the manifest of the backup at the given path.
+func (b *BackupBackend) readManifest(path string) (*model.BackupManifest, error) {
+	content, err := b.read(filepath.Join(path, manifestFile))
+	if err != nil {
+		return nil, err
+	}
+	manifest := &model.BackupManifest{}
+	if err := yaml.Unmarshal(content, manifest); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// manifestCache keeps the manifests read to verify the signatures of the listed
+// backups, including the missing manifests of the backups created before them.
+type manifestCache struct {
+	sync.Mutex
+	manifests map[string]cachedManifest
+}
+
+// cachedManifest is the manifest of the backup created at the given time, or nil.
+type cachedManifest struct {
+	created  time.Time
+	manifest *model.BackupManifest
+}
+
+// get returns the manifest of the backup at the given path, reading it if the
+// cached one belongs to another backup, or nil if the backup has none.
+func (c *manifestCache) get(path string, created time.Time,
+	read func(path string) (*model.BackupManifest, error)) *model.BackupManifest {
+	c.Lock()
+	cached, found := c.manifests[path]
+	c.Unlock()
+	if found && cached.created.Equal(created) {
+		return cached.manifest
+	}
+	manifest, err := read(path)
+	if err != nil {
+		manifest = nil
+	}
+	c.Lock()
+	defer c.Unlock()
+	if c.manifests == nil {
+		c.manifests = make(map[string]cachedManifest)
+	}
+	c.manifests[path] = cachedManifest{created: created, manifest: manifest}
+	return manifest
+}
+
+// remove removes the cached manifests of the backups under the given folder.
+func (c *manifestCache) remove(folder string) {
+	c.Lock()
+	defer c.Unlock()
+	for path := range c.manifests {
+		if path == folder || strings.HasPrefix(path, folder+"/") {
+			delete(c.manifests, path)
+		}
+	}
+}
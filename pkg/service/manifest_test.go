@@ -0,0 +1,145 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aerospike/backup/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sha256Hex(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestWriteManifest(t *testing.T) {
+	tests := []struct {
+		name    string
+		backend func(t *testing.T) *BackupBackend
+	}{
+		{
+			name: "memory",
+			backend: func(_ *testing.T) *BackupBackend {
+				return newMemoryBackend(newMemoryAccessor(), "routine", false)
+			},
+		},
+		{
+			name: "local",
+			backend: func(t *testing.T) *BackupBackend {
+				routinePath := filepath.Join(t.TempDir(), "routine")
+				return &BackupBackend{
+					StorageAccessor:      NewOSDiskAccessor(),
+					fullBackupsPath:      filepath.Join(routinePath, model.FullBackupDirectory),
+					fullBackupInProgress: &atomic.Bool{},
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := tt.backend(t)
+			backupFolder := filepath.Join(backend.fullBackupsPath, "1000", model.DataDirectory, "ns1")
+			configurationFolder := filepath.Join(backend.fullBackupsPath, "1000",
+				model.ConfigurationBackupDirectory)
+			require.NoError(t, backend.write(filepath.Join(backupFolder, "ns1_1.asb"), []byte("first")))
+			require.NoError(t, backend.write(filepath.Join(backupFolder, "ns1_0.asb"), []byte("second")))
+			require.NoError(t, backend.write(filepath.Join(backupFolder, metadataFile), []byte("metadata")))
+			require.NoError(t, backend.write(filepath.Join(configurationFolder, "aerospike_0.conf"),
+				[]byte("configuration")))
+
+			err := backend.writeManifest(backupFolder,
+				backupDataFiles(backupFolder), configurationFiles(configurationFolder))
+			require.NoError(t, err)
+
+			manifest, err := backend.readManifest(backupFolder)
+			require.NoError(t, err)
+			assert.Equal(t, &model.BackupManifest{
+				Algorithm: model.ManifestAlgorithm,
+				Files: []model.ManifestFile{
+					{Path: "configuration/aerospike_0.conf", Size: 13, Checksum: sha256Hex("configuration")},
+					{Path: "data/ns1/ns1_0.asb", Size: 6, Checksum: sha256Hex("second")},
+					{Path: "data/ns1/ns1_1.asb", Size: 5, Checksum: sha256Hex("first")},
+				},
+			}, manifest)
+		})
+	}
+}
+
+func TestBackupDetailsManifest(t *testing.T) {
+	accessor := newMemoryAccessor()
+	useFakeBackup(t, &fakeBackup{accessor: accessor, records: 100, incrementalRecords: 10})
+	config := flowConfig()
+	backends := flowBackends(accessor)
+	backend, _ := backends.Get("routine")
+	handler, err := newBackupHandler(config, "routine", backend, backends)
+	require.NoError(t, err)
+
+	handler.runFullBackup(time.UnixMilli(1000))
+	handler.runIncrementalBackup(time.UnixMilli(2000))
+
+	fullBackups, err := backend.BackupDetails(1000, true)
+	require.NoError(t, err)
+	require.Len(t, fullBackups, 2)
+	for _, backup := range fullBackups {
+		require.NotNil(t, backup.Manifest)
+		assert.Equal(t, []string{
+			"configuration/aerospike_0.conf",
+			"data/" + backup.Namespace + "/" + backup.Namespace + "_0.asb",
+		}, manifestPaths(backup.Manifest))
+	}
+	incrementalBackups, err := backend.BackupDetails(2000, false)
+	require.NoError(t, err)
+	require.Len(t, incrementalBackups, 2)
+	for _, backup := range incrementalBackups {
+		require.NotNil(t, backup.Manifest)
+		assert.Equal(t, []string{
+			"data/" + backup.Namespace + "/" + backup.Namespace + "_0.asb",
+		}, manifestPaths(backup.Manifest))
+	}
+
+	// the manifests are not listed
+	listedBackups, _ := backend.FullBackupList(&model.TimeBounds{})
+	require.Len(t, listedBackups, 2)
+	for _, backup := range listedBackups {
+		assert.Nil(t, backup.Manifest)
+	}
+	_, err = backend.BackupDetails(3000, true)
+	assert.ErrorIs(t, err, ErrBackupNotFound)
+}
+
+func manifestPaths(manifest *model.BackupManifest) []string {
+	paths := make([]string, 0, len(manifest.Files))
+	for _, file := range manifest.Files {
+		paths = append(paths, file.Path)
+	}
+	return paths
+}
+
+func TestManifestCache(t *testing.T) {
+	var reads int
+	read := func(string) (*model.BackupManifest, error) {
+		reads++
+		return nil, os.ErrNotExist
+	}
+	cache := manifestCache{}
+
+	// the missing manifest is cached too
+	assert.Nil(t, cache.get("backup/1000/data/ns1", time.UnixMilli(1000), read))
+	assert.Nil(t, cache.get("backup/1000/data/ns1", time.UnixMilli(1000), read))
+	assert.Equal(t, 1, reads)
+
+	// the manifest of a rewritten backup is read again
+	cache.get("backup/1000/data/ns1", time.UnixMilli(2000), read)
+	assert.Equal(t, 2, reads)
+
+	cache.remove("backup/1000")
+	cache.get("backup/1000/data/ns1", time.UnixMilli(2000), read)
+	assert.Equal(t, 3, reads)
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/aerospike/backup/pkg/model"
+	"github.com/aerospike/backup/pkg/util"
+	"gopkg.in/yaml.v3"
+)
+
+const memoryProtocol = "memory://"
+
+// memoryAccessor is a StorageAccessor keeping the files in memory, for hermetic tests.
+// The folders exist implicitly as long as they contain files.
+type memoryAccessor struct {
+	sync.RWMutex
+	files map[string][]byte
+}
+
+var _ StorageAccessor = (*memoryAccessor)(nil)
+
+func newMemoryAccessor() *memoryAccessor {
+	return &memoryAccessor{files: make(map[string][]byte)}
+}
+
+// newMemoryBackend returns a BackupBackend of the routine backed by the accessor.
+func newMemoryBackend(accessor *memoryAccessor, routineName string, removeFullBackup bool) *BackupBackend {
+	routinePath := filepath.Join("backups", routineName)
+	return &BackupBackend{
+		StorageAccessor:        accessor,
+		fullBackupsPath:        filepath.Join(routinePath, model.FullBackupDirectory),
+		incrementalBackupsPath: filepath.Join(routinePath, model.IncrementalBackupDirectory),
+		stateFilePath:          filepath.Join(routinePath, model.StateFileName),
+		removeFullBackup:       removeFullBackup,
+		fullBackupInProgress:   &atomic.Bool{},
+	}
+}
+
+func memoryPath(path string) string {
+	return strings.TrimPrefix(filepath.Clean(path), "/")
+}
+
+func (m *memoryAccessor) readBackupState(stateFilePath string, state *model.BackupState) error {
+	content, err := m.read(stateFilePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(content, state)
+}
+
+func (m *memoryAccessor) readBackupDetails(path string, _ bool) (model.BackupDetails, error) {
+	content, err := m.read(filepath.Join(path, metadataFile))
+	if err != nil {
+		return model.BackupDetails{}, err
+	}
+	metadata := model.BackupMetadata{}
+	if err := yaml.Unmarshal(content, &metadata); err != nil {
+		return model.BackupDetails{}, err
+	}
+	return model.BackupDetails{
+		BackupMetadata: metadata,
+		Key:            util.Ptr(memoryProtocol + "/" + memoryPath(path)),
+	}, nil
+}
+
+func (m *memoryAccessor) read(path string) ([]byte, error) {
+	m.RLock()
+	defer m.RUnlock()
+	content, found := m.files[memoryPath(path)]
+	if !found {
+		return nil, fmt.Errorf("%w: %s", os.ErrNotExist, path)
+	}
+	return slices.Clone(content), nil
+}
+
+func (m *memoryAccessor) write(path string, data []byte) error {
+	m.Lock()
+	defer m.Unlock()
+	m.files[memoryPath(path)] = slices.Clone(data)
+	return nil
+}
+
+func (m *memoryAccessor) open(path string) (io.ReadCloser, error) {
+	content, err := m.read(path)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(content)), nil
+}
+
+func (m *memoryAccessor) create(path string) (streamWriter, error) {
+	return newPipeWriter(func(reader io.Reader) error {
+		content, err := io.ReadAll(reader)
+		if err != nil {
+			return err
+		}
+		return m.write(path, content)
+	}), nil
+}
+
+func (m *memoryAccessor) lsDir(path string) ([]string, error) {
+	m.RLock()
+	defer m.RUnlock()
+	prefix := memoryPath(path) + "/"
+	var dirs []string
+	for file := range m.files {
+		name, found := strings.CutPrefix(file, prefix)
+		if !found {
+			continue
+		}
+		if dir, _, isNested := strings.Cut(name, "/"); isNested {
+			dirs = append(dirs, prefix+dir)
+		}
+	}
+	slices.Sort(dirs)
+	return slices.Compact(dirs), nil
+}
+
+func (m *memoryAccessor) lsFiles(path string) ([]string, error) {
+	m.RLock()
+	defer m.RUnlock()
+	prefix := memoryPath(path) + "/"
+	var files []string
+	for file := range m.files {
+		if strings.HasPrefix(file, prefix) {
+			files = append(files, file)
+		}
+	}
+	slices.Sort(files)
+	return files, nil
+}
+
+func (m *memoryAccessor) fileSize(path string) (int64, error) {
+	m.RLock()
+	defer m.RUnlock()
+	content, found := m.files[memoryPath(path)]
+	if !found {
+		return 0, fmt.Errorf("%w: %s", os.ErrNotExist, path)
+	}
+	return int64(len(content)), nil
+}
+
+func (m *memoryAccessor) DeleteFolder(path string) error {
+	m.Lock()
+	defer m.Unlock()
+	prefix := memoryPath(path) + "/"
+	for file := range m.files {
+		if strings.HasPrefix(file, prefix) {
+			delete(m.files, file)
+		}
+	}
+	return nil
+}
+
+func (m *memoryAccessor) CreateFolder(_ string) {
+}
+
+func (m *memoryAccessor) wrapWithPrefix(path string) *string {
+	return &path
+}
@@ -32,6 +32,13 @@ var incrBackupSkippedCounter = prometheus.NewCounter(
 		Help: "Incremental backup skip counter.",
 	})
 
+// a counter metric for the skipped and deferred backup runs, by routine, backup type and reason
+var backupSkipReasonCounter = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "aerospike_backup_service_skip_reason_total",
+		Help: "Skipped backup runs counter, by reason.",
+	}, []string{"routine", "type", "reason"})
+
 // a counter metric for backup failure number
 var backupFailureCounter = prometheus.NewCounter(
 	prometheus.CounterOpts{
@@ -60,13 +67,103 @@ var incrBackupDurationGauge = prometheus.NewGauge(
 		Help: "Incremental backup duration in milliseconds.",
 	})
 
+// a gauge metric for the number of garbage folders found by the latest garbage collector run
+var garbageFoldersGauge = prometheus.NewGauge(
+	prometheus.GaugeOpts{
+		Name: "aerospike_backup_service_garbage_folders",
+		Help: "Garbage folders found by the latest garbage collector run.",
+	})
+
+// a counter metric for the number of garbage folders deleted
+var garbageDeletedCounter = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Name: "aerospike_backup_service_garbage_deleted_total",
+		Help: "Deleted garbage folders counter.",
+	})
+
+// a gauge metric for the estimated storage usage
+var storageUsageGauge = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "aerospike_backup_service_storage_usage_bytes",
+		Help: "Estimated storage usage in bytes.",
+	}, []string{"storage"})
+
+// a counter metric for backup replication number
+var replicationCounter = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Name: "aerospike_backup_service_replication_total",
+		Help: "Backup replication counter.",
+	})
+
+// a counter metric for backup replication failure number
+var replicationFailureCounter = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Name: "aerospike_backup_service_replication_failure_total",
+		Help: "Backup replication failure counter.",
+	})
+
+// a gauge metric for the number of failures found by the latest verification of a routine
+var verificationFailuresGauge = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "aerospike_backup_service_verification_failures",
+		Help: "Failures found by the latest backup verification.",
+	}, []string{"routine"})
+
+// a counter metric for restore drill number, by routine and result
+var restoreDrillCounter = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "aerospike_backup_service_restore_drills_total",
+		Help: "Restore drills counter.",
+	}, []string{"routine", "result"})
+
+// a gauge metric for the result of the latest restore drill of a routine, 1 if passed
+var restoreDrillPassedGauge = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "aerospike_backup_service_restore_drill_passed",
+		Help: "Whether the latest restore drill passed.",
+	}, []string{"routine"})
+
+// a gauge metric for the number of incremental backup chain issues of a routine, by type
+var chainIssuesGauge = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "aerospike_backup_service_chain_issues",
+		Help: "Gaps and overlaps found by the latest incremental backup chain analysis.",
+	}, []string{"routine", "type"})
+
+// a gauge metric for the number of the jobs waiting in the job queue, by type
+var queuedJobsGauge = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "aerospike_backup_service_queued_jobs",
+		Help: "Jobs waiting in the job queue.",
+	}, []string{"type"})
+
+// a histogram metric for the time the jobs wait in the job queue, by type
+var queueWaitHistogram = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "aerospike_backup_service_queue_wait_seconds",
+		Help:    "Time the jobs wait in the job queue in seconds.",
+		Buckets: prometheus.ExponentialBuckets(1, 4, 8), // up to 4.5 hours
+	}, []string{"type"})
+
 func init() {
 	prometheus.MustRegister(backupCounter)
 	prometheus.MustRegister(incrBackupCounter)
 	prometheus.MustRegister(backupSkippedCounter)
 	prometheus.MustRegister(incrBackupSkippedCounter)
+	prometheus.MustRegister(backupSkipReasonCounter)
 	prometheus.MustRegister(backupFailureCounter)
 	prometheus.MustRegister(incrBackupFailureCounter)
 	prometheus.MustRegister(backupDurationGauge)
 	prometheus.MustRegister(incrBackupDurationGauge)
+	prometheus.MustRegister(garbageFoldersGauge)
+	prometheus.MustRegister(garbageDeletedCounter)
+	prometheus.MustRegister(storageUsageGauge)
+	prometheus.MustRegister(replicationCounter)
+	prometheus.MustRegister(replicationFailureCounter)
+	prometheus.MustRegister(verificationFailuresGauge)
+	prometheus.MustRegister(restoreDrillCounter)
+	prometheus.MustRegister(restoreDrillPassedGauge)
+	prometheus.MustRegister(chainIssuesGauge)
+	prometheus.MustRegister(queuedJobsGauge)
+	prometheus.MustRegister(queueWaitHistogram)
 }
@@ -3,6 +3,7 @@ package service
 import (
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"log/slog"
 	"os"
@@ -69,10 +70,45 @@ func (o *OSDiskAccessor) read(filePath string) ([]byte, error) {
 	return os.ReadFile(filePath)
 }
 
+func (o *OSDiskAccessor) open(filePath string) (io.ReadCloser, error) {
+	return os.Open(filePath)
+}
+
 func (o *OSDiskAccessor) write(filePath string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0744); err != nil {
+		return err
+	}
 	return os.WriteFile(filePath, data, 0644)
 }
 
+func (o *OSDiskAccessor) create(filePath string) (streamWriter, error) {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0744); err != nil {
+		return nil, err
+	}
+	file, err := os.Create(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return newPipeWriter(func(reader io.Reader) error {
+		_, err := io.Copy(file, reader)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			_ = os.Remove(filePath)
+		}
+		return err
+	}), nil
+}
+
+func (o *OSDiskAccessor) fileSize(path string) (int64, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
 func (o *OSDiskAccessor) lsDir(path string) ([]string, error) {
 	content, err := os.ReadDir(path)
 	if err != nil {
@@ -2,11 +2,13 @@ package service
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"syscall"
 	"testing"
 	"time"
@@ -353,3 +355,25 @@ func TestReadStateNegative(t *testing.T) {
 		})
 	}
 }
+
+func TestOSDiskAccessor_Create(t *testing.T) {
+	accessor := NewOSDiskAccessor()
+	dir := t.TempDir()
+	stored := filepath.Join(dir, "nested", "stored.asb")
+	discarded := filepath.Join(dir, "discarded.asb")
+
+	writer, err := accessor.create(stored)
+	assert.NoError(t, err)
+	assert.NoError(t, copyStream(writer, strings.NewReader("content")))
+	content, err := os.ReadFile(stored)
+	assert.NoError(t, err)
+	assert.Equal(t, "content", string(content))
+
+	writer, err = accessor.create(discarded)
+	assert.NoError(t, err)
+	_, err = writer.Write([]byte("partial"))
+	assert.NoError(t, err)
+	assert.Error(t, writer.CloseWithError(errors.New("failed")))
+	_, err = os.Stat(discarded)
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/aerospike/backup/pkg/model"
+)
+
+// StorageUsage returns the estimated usage of the storage, which is the total
+// size of the backups of all the routines using it.
+func StorageUsage(config *model.Config, backends BackendsHolder, storageName string) (*model.StorageUsage, error) {
+	storage, found := config.Storage[storageName]
+	if !found {
+		return nil, fmt.Errorf("storage %s not found", storageName)
+	}
+	usage, err := storageUsage(backends, storageName, routinesOfStorage(config, storageName))
+	if err != nil {
+		return nil, err
+	}
+	usage.Quota = storage.Quota
+	return usage, nil
+}
+
+// routinesOfStorage returns the names of the routines using the storage.
+func routinesOfStorage(config *model.Config, storageName string) []string {
+	var routines []string
+	for name, routine := range config.BackupRoutines {
+		if routine.Storage == storageName {
+			routines = append(routines, name)
+		}
+	}
+	slices.Sort(routines)
+	return routines
+}
+
+func storageUsage(backends BackendsHolder, storageName string, routines []string) (*model.StorageUsage, error) {
+	usage := &model.StorageUsage{Routines: make(map[string]uint64, len(routines))}
+	allTime := &model.TimeBounds{}
+	for _, routine := range routines {
+		backend, found := backends.Get(routine)
+		if !found {
+			continue
+		}
+		// the tiered backups are not located in the storage
+		fullBackups, err := backend.fullBackupList(allTime)
+		if err != nil {
+			return nil, err
+		}
+		incrementalBackups, err := backend.incrementalBackupList(allTime)
+		if err != nil {
+			return nil, err
+		}
+		routineBytes := totalBytes(fullBackups) + totalBytes(incrementalBackups)
+		usage.Routines[routine] = routineBytes
+		usage.UsedBytes += routineBytes
+	}
+	storageUsageGauge.WithLabelValues(storageName).Set(float64(usage.UsedBytes))
+	return usage, nil
+}
+
+func totalBytes(backups []model.BackupDetails) uint64 {
+	var total uint64
+	for _, backup := range backups {
+		total += backup.ByteCount
+	}
+	return total
+}
+
+// checkQuota enforces the storage quota before a full backup.
+// The size of the new full backup is estimated from the latest one.
+// It returns false if the full backup should be skipped.
+func (h *BackupHandler) checkQuota() bool {
+	quota := h.storage.Quota
+	if quota == nil {
+		return true
+	}
+	usage, err := storageUsage(h.backends, h.backupRoutine.Storage, h.storageRoutines)
+	if err != nil {
+		slog.Warn("Could not estimate storage usage", "name", h.routineName, "err", err)
+		return true
+	}
+	allTime := &model.TimeBounds{}
+	fullBackups, err := h.backend.fullBackupList(allTime)
+	if err != nil {
+		slog.Warn("Could not read full backup list", "name", h.routineName, "err", err)
+		return true
+	}
+	incrementalBackups, err := h.backend.incrementalBackupList(allTime)
+	if err != nil {
+		slog.Warn("Could not read incremental backup list", "name", h.routineName, "err", err)
+		return true
+	}
+	chains, orphans := buildBackupChains(fullBackups, incrementalBackups)
+
+	expected := usage.UsedBytes
+	// the RemoveAll policy overwrites the previous full backup
+	if len(chains) > 0 && !h.backend.removeFullBackup {
+		expected += totalBytes(chains[len(chains)-1].full)
+	}
+	if expected <= uint64(*quota.MaxBytes) {
+		return true
+	}
+	excess := expected - uint64(*quota.MaxBytes)
+
+	switch quota.GetActionOrDefault() {
+	case model.QuotaActionSkip:
+		slog.Error("Storage quota exceeded, skipping full backup", "name", h.routineName,
+			"storage", h.backupRoutine.Storage, "usedBytes", usage.UsedBytes,
+			"expectedBytes", expected, "maxBytes", *quota.MaxBytes)
+		return false
+	case model.QuotaActionPrune:
+		backups := quotaPruneCandidates(chains, orphans, excess, currentTime())
+		if backups == nil {
+			slog.Error("Storage quota exceeded and pruning cannot free enough space, skipping full backup",
+				"name", h.routineName, "storage", h.backupRoutine.Storage, "excessBytes", excess)
+			return false
+		}
+		slog.Info("Storage quota exceeded, pruning oldest backups", "name", h.routineName,
+			"storage", h.backupRoutine.Storage, "excessBytes", excess)
+		h.pruneBackups(backups)
+		return true
+	default:
+		slog.Warn("Storage quota exceeded", "name", h.routineName,
+			"storage", h.backupRoutine.Storage, "usedBytes", usage.UsedBytes,
+			"expectedBytes", expected, "maxBytes", *quota.MaxBytes)
+		return true
+	}
+}
+
+// quotaPruneCandidates returns the oldest backups to delete to free the given number
+// of bytes, or nil if not enough space can be freed. The latest full backup and the
+// backups on hold with their chains are never pruned, nor are the backups locked by
+// the storage, as in the retention policy.
+func quotaPruneCandidates(chains []*backupChain, orphans []model.BackupDetails,
+	excess uint64, now time.Time) []model.PruneCandidate {
+	var candidates []model.PruneCandidate
+	var freed uint64
+	add := func(backup model.BackupDetails, backupType string) {
+		candidates = append(candidates, model.PruneCandidate{
+			BackupDetails: backup,
+			Type:          backupType,
+			Reasons:       []string{"quota"},
+		})
+		freed += backup.ByteCount
+	}
+
+	slices.SortFunc(orphans, func(a, b model.BackupDetails) int {
+		return a.Created.Compare(b.Created)
+	})
+	for _, backup := range orphans {
+		if freed >= excess {
+			break
+		}
+		if !backup.IsHeld() && !backup.IsLocked(now) {
+			add(backup, model.IncrementalBackupType)
+		}
+	}
+	for i := 0; i < len(chains)-1 && freed < excess; i++ {
+		chain := chains[i]
+		if heldBackups(chain.full, chain.incrementals) > 0 || latestLock(now, chain.full) != nil {
+			continue
+		}
+		for _, backup := range chain.full {
+			add(backup, model.FullBackupType)
+		}
+		// the locked incremental backups are kept, as by the retention policy
+		for _, backup := range chain.incrementals {
+			if !backup.IsLocked(now) {
+				add(backup, model.IncrementalBackupType)
+			}
+		}
+	}
+	if freed < excess {
+		return nil
+	}
+	return candidates
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aerospike/backup/pkg/model"
+	"github.com/aerospike/backup/pkg/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuotaPruneCandidates(t *testing.T) {
+	sized := func(created int64, bytes uint64, hold *model.BackupHold) model.BackupDetails {
+		return model.BackupDetails{BackupMetadata: model.BackupMetadata{
+			Created: time.UnixMilli(created), ByteCount: bytes, Hold: hold,
+		}}
+	}
+	chains, orphans := buildBackupChains(
+		[]model.BackupDetails{
+			sized(100, 10, &model.BackupHold{Reason: "audit", Actor: "auditor"}),
+			sized(200, 10, nil),
+			sized(300, 10, nil),
+		},
+		[]model.BackupDetails{sized(50, 1, nil), sized(250, 2, nil)},
+	)
+
+	tests := []struct {
+		name     string
+		excess   uint64
+		expected []int64
+	}{
+		{name: "orphans first", excess: 1, expected: []int64{50}},
+		{name: "skip held chains", excess: 5, expected: []int64{50, 200, 250}},
+		{name: "latest is never pruned", excess: 20},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			candidates := quotaPruneCandidates(chains, orphans, tt.excess, time.Now())
+			var created []int64
+			for _, candidate := range candidates {
+				created = append(created, candidate.Created.UnixMilli())
+			}
+			assert.Equal(t, tt.expected, created)
+		})
+	}
+}
+
+func TestQuotaPruneCandidates_Locked(t *testing.T) {
+	now := time.UnixMilli(1000)
+	lockedUntil := now.Add(time.Hour)
+	sized := func(created int64, bytes uint64, locked bool) model.BackupDetails {
+		backup := model.BackupDetails{BackupMetadata: model.BackupMetadata{
+			Created: time.UnixMilli(created), ByteCount: bytes,
+		}}
+		if locked {
+			backup.LockedUntil = &lockedUntil
+		}
+		return backup
+	}
+	chains, orphans := buildBackupChains(
+		[]model.BackupDetails{sized(100, 10, true), sized(200, 10, false), sized(300, 10, false)},
+		[]model.BackupDetails{sized(50, 1, true), sized(60, 1, false), sized(250, 2, true)},
+	)
+
+	tests := []struct {
+		name     string
+		excess   uint64
+		expected []int64
+	}{
+		{name: "skip locked orphans", excess: 1, expected: []int64{60}},
+		{name: "skip locked chains and incrementals", excess: 11, expected: []int64{60, 200}},
+		{name: "locked backups are not freeable", excess: 12},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			candidates := quotaPruneCandidates(chains, orphans, tt.excess, now)
+			var created []int64
+			for _, candidate := range candidates {
+				created = append(created, candidate.Created.UnixMilli())
+			}
+			assert.Equal(t, tt.expected, created)
+		})
+	}
+}
+
+func TestCheckQuota(t *testing.T) {
+	backend := newDiskBackend(t, NewOSDiskAccessor())
+	for _, created := range []int64{100, 200} {
+		writeFullBackup(t, backend, model.BackupMetadata{Created: time.UnixMilli(created), Namespace: "ns1",
+			ByteCount: 10})
+	}
+	handler := &BackupHandler{
+		backend:         backend,
+		backupRoutine:   &model.BackupRoutine{Storage: "storage"},
+		routineName:     "routine",
+		backends:        &BackendHolderImpl{data: map[string]*BackupBackend{"routine": backend}},
+		storageRoutines: []string{"routine"},
+	}
+	quota := func(maxBytes int64, action model.QuotaAction) *model.Storage {
+		return &model.Storage{Quota: &model.StorageQuota{MaxBytes: &maxBytes, Action: util.Ptr(action)}}
+	}
+
+	// the usage of 20 bytes and the next full backup of 10 bytes fit the quota
+	handler.storage = quota(30, model.QuotaActionSkip)
+	assert.True(t, handler.checkQuota())
+
+	handler.storage = quota(25, model.QuotaActionWarn)
+	assert.True(t, handler.checkQuota())
+
+	handler.storage = quota(25, model.QuotaActionSkip)
+	assert.False(t, handler.checkQuota())
+
+	handler.storage = quota(5, model.QuotaActionPrune)
+	assert.False(t, handler.checkQuota())
+	fullBackups, _ := backend.FullBackupList(&model.TimeBounds{})
+	assert.Len(t, fullBackups, 2)
+
+	handler.storage = quota(25, model.QuotaActionPrune)
+	assert.True(t, handler.checkQuota())
+	fullBackups, _ = backend.FullBackupList(&model.TimeBounds{})
+	assert.Equal(t, []int64{200}, createdMillis(fullBackups))
+}
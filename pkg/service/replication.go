@@ -0,0 +1,354 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/aerospike/backup/pkg/model"
+)
+
+// replicator copies the completed backups of a routine to its secondary storages.
+// The copies run in the background and failed copies are retried with the retry
+// settings of the backup policy, so they never block the next scheduled backup.
+// The pending replications are recorded in the backup metadata, and the ones
+// interrupted by a restart or a configuration change are resumed.
+type replicator struct {
+	ctx              context.Context
+	cancel           context.CancelFunc
+	routineName      string
+	source           *BackupBackend
+	storageNames     []string
+	storages         map[string]*model.Storage
+	removeFullBackup bool
+	retryDelay       time.Duration
+	maxRetries       int32
+	targetsMutex     sync.Mutex
+	targets          map[string]*BackupBackend
+	runningMutex     sync.Mutex
+	running          sync.WaitGroup
+}
+
+// newReplicator returns a new replicator for the routine,
+// or nil if the routine has no secondary storages.
+func newReplicator(config *model.Config, routineName string, source *BackupBackend) *replicator {
+	routine := config.BackupRoutines[routineName]
+	if len(routine.ReplicateTo) == 0 {
+		return nil
+	}
+	backupPolicy := config.BackupPolicies[routine.BackupPolicy]
+	storages := make(map[string]*model.Storage, len(routine.ReplicateTo))
+	for _, storageName := range routine.ReplicateTo {
+		storages[storageName] = config.Storage[storageName]
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &replicator{
+		ctx:              ctx,
+		cancel:           cancel,
+		routineName:      routineName,
+		source:           source,
+		storageNames:     routine.ReplicateTo,
+		storages:         storages,
+		removeFullBackup: backupPolicy.RemoveFiles.RemoveFullBackup(),
+		retryDelay:       time.Duration(backupPolicy.GetRetryDelayOrDefault()) * time.Millisecond,
+		maxRetries:       backupPolicy.GetMaxRetriesOrDefault(),
+		targets:          make(map[string]*BackupBackend),
+	}
+}
+
+// replicateBackup copies the backup folder of a namespace to the secondary storages
+// and records the replication status in the backup metadata.
+func (r *replicator) replicateBackup(path string) {
+	if r == nil {
+		return
+	}
+	for _, storageName := range r.storageNames {
+		// recorded before the copy, to be resumed if the service is restarted
+		err := r.setStatus(path, model.ReplicationStatus{
+			Storage: storageName,
+			State:   model.ReplicationPending,
+			Updated: time.Now(),
+		})
+		if err != nil {
+			slog.Warn("Couldn't record pending replication", "name", r.routineName,
+				"path", path, "storage", storageName, "err", err)
+		}
+		r.run(path, storageName, true, 0)
+	}
+}
+
+// replicateFolder copies a folder without metadata, such as the cluster configuration,
+// to the secondary storages. These copies are not resumed after a restart.
+func (r *replicator) replicateFolder(path string) {
+	if r == nil {
+		return
+	}
+	for _, storageName := range r.storageNames {
+		r.run(path, storageName, false, 0)
+	}
+}
+
+// resume restarts in the background the replications of the routine backups
+// left pending by a restart or a configuration change.
+func (r *replicator) resume() {
+	if r == nil {
+		return
+	}
+	r.runningMutex.Lock()
+	defer r.runningMutex.Unlock()
+	r.running.Add(1)
+	go func() {
+		defer r.running.Done()
+		for _, path := range r.backupPaths() {
+			details, err := r.source.readBackupDetails(path, true)
+			if err != nil {
+				continue
+			}
+			for _, status := range details.Replication {
+				if status.State == model.ReplicationPending && r.storages[status.Storage] != nil {
+					slog.Info("Resume backup replication", "name", r.routineName,
+						"path", path, "storage", status.Storage)
+					r.run(path, status.Storage, true, status.Attempts)
+				}
+			}
+		}
+	}()
+}
+
+// backupPaths returns the namespace folders of the routine backups in the source storage.
+func (r *replicator) backupPaths() []string {
+	backupFolders, _ := r.source.lsDir(r.source.incrementalBackupsPath)
+	if r.removeFullBackup {
+		backupFolders = append(backupFolders, r.source.fullBackupsPath)
+	} else {
+		fullBackupFolders, _ := r.source.lsDir(r.source.fullBackupsPath)
+		backupFolders = append(backupFolders, fullBackupFolders...)
+	}
+	var paths []string
+	for _, folder := range backupFolders {
+		namespaces, err := r.source.lsDir(filepath.Join(folder, model.DataDirectory))
+		if err != nil {
+			continue
+		}
+		paths = append(paths, namespaces...)
+	}
+	return paths
+}
+
+// stop aborts the running copies and waits for them to return.
+// The aborted replications stay pending, to be resumed by the next replicator of the routine.
+func (r *replicator) stop() {
+	if r == nil {
+		return
+	}
+	r.runningMutex.Lock()
+	r.cancel()
+	r.runningMutex.Unlock()
+	r.running.Wait()
+}
+
+// run copies the folder to the storage in the background, retrying on failure.
+// The given number of attempts were made before.
+func (r *replicator) run(path string, storageName string, withMetadata bool, attempts int32) {
+	r.runningMutex.Lock()
+	defer r.runningMutex.Unlock()
+	if r.ctx.Err() != nil {
+		// stopped, the next replicator resumes the pending replication
+		return
+	}
+	r.running.Add(1)
+	go func() {
+		defer r.running.Done()
+		for attempt := attempts + 1; ; attempt++ {
+			bytes, err := r.copy(path, storageName, withMetadata)
+			if r.ctx.Err() != nil {
+				slog.Info("Backup replication stopped", "name", r.routineName,
+					"path", path, "storage", storageName)
+				return
+			}
+			status := model.ReplicationStatus{
+				Storage:  storageName,
+				State:    model.ReplicationDone,
+				Attempts: attempt,
+				Bytes:    bytes,
+				Updated:  time.Now(),
+			}
+			if err != nil {
+				status.State = model.ReplicationPending
+				status.Error = err.Error()
+				if attempt > r.maxRetries {
+					status.State = model.ReplicationFailed
+				}
+			}
+			if withMetadata {
+				if err := r.setStatus(path, status); errors.Is(err, ErrBackupNotFound) {
+					slog.Info("Backup was deleted, stop replication", "name", r.routineName,
+						"path", path, "storage", storageName)
+					return
+				}
+			}
+
+			switch status.State {
+			case model.ReplicationDone:
+				replicationCounter.Inc()
+				slog.Info("Backup replicated", "name", r.routineName, "path", path,
+					"storage", storageName, "bytes", bytes)
+				return
+			case model.ReplicationFailed:
+				replicationFailureCounter.Inc()
+				slog.Error("Backup replication failed, no retry attempts left", "name", r.routineName,
+					"path", path, "storage", storageName, "err", err)
+				return
+			default:
+				slog.Warn("Backup replication failed, retry scheduled", "name", r.routineName,
+					"path", path, "storage", storageName, "retryInterval", r.retryDelay, "err", err)
+				select {
+				case <-time.After(r.retryDelay):
+				case <-r.ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+}
+
+// copy copies the folder to the secondary storage.
+// The metadata is copied last, so that an incomplete copy is never listed as a backup.
+func (r *replicator) copy(path string, storageName string, withMetadata bool) (uint64, error) {
+	target, err := r.target(storageName)
+	if err != nil {
+		return 0, err
+	}
+	targetPath, bytes, err := copyFiles(r.ctx, r.source, target, path)
+	if err != nil || !withMetadata {
+		return bytes, err
+	}
+	details, err := r.source.readBackupDetails(path, false)
+	if err != nil {
+		return bytes, err
+	}
+	metadata := details.BackupMetadata
+	metadata.Replication = nil
+	if err := target.writeBackupMetadata(targetPath, metadata); err != nil {
+		return bytes, err
+	}
+	return bytes, nil
+}
+
+// copyFiles copies the files of the folder, except the metadata, to the same
+// location of the routine in the target storage, verifying their sizes.
+// The files are streamed until the context is done.
+// It returns the path of the copy and the number of bytes copied.
+func copyFiles(ctx context.Context, source, target *BackupBackend, path string) (string, uint64, error) {
+	relativePath, err := filepath.Rel(source.routinePath(), path)
+	if err != nil {
+		return "", 0, err
+	}
+	targetPath := filepath.Join(target.routinePath(), relativePath)
+
+	files, err := source.lsFiles(path)
+	if err != nil {
+		return targetPath, 0, err
+	}
+	if len(files) == 0 {
+		return targetPath, 0, fmt.Errorf("no files found in %s", path)
+	}
+	// remove a previous or partial copy
+	if previousFiles, err := target.lsFiles(targetPath); err == nil && len(previousFiles) > 0 {
+		if err := target.DeleteFolder(targetPath); err != nil {
+			return targetPath, 0, err
+		}
+	}
+
+	var bytes uint64
+	for _, file := range files {
+		if filepath.Base(file) == metadataFile {
+			continue
+		}
+		relativeFile, err := filepath.Rel(path, file)
+		if err != nil {
+			return targetPath, bytes, err
+		}
+		targetFile := filepath.Join(targetPath, relativeFile)
+		copied, err := copyFile(ctx, source, target, file, targetFile)
+		if err != nil {
+			return targetPath, bytes, err
+		}
+		size, err := target.fileSize(targetFile)
+		if err != nil {
+			return targetPath, bytes, err
+		}
+		if size != copied {
+			return targetPath, bytes, fmt.Errorf("size mismatch for %s, expected %d, got %d",
+				targetFile, copied, size)
+		}
+		bytes += uint64(size)
+	}
+	return targetPath, bytes, nil
+}
+
+// copyFile streams the file to the target file and returns the number of bytes copied.
+func copyFile(ctx context.Context, source, target *BackupBackend, file, targetFile string) (int64, error) {
+	reader, err := source.open(file)
+	if err != nil {
+		return 0, err
+	}
+	defer reader.Close()
+	writer, err := target.create(targetFile)
+	if err != nil {
+		return 0, err
+	}
+	copied, err := io.Copy(writer, contextReader{ctx: ctx, Reader: reader})
+	if err != nil {
+		_ = writer.CloseWithError(err)
+		return 0, err
+	}
+	return copied, writer.Close()
+}
+
+// contextReader stops reading once the context is done.
+type contextReader struct {
+	ctx context.Context
+	io.Reader
+}
+
+func (r contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.Reader.Read(p)
+}
+
+// target returns the backend of the secondary storage, connecting on first use.
+func (r *replicator) target(storageName string) (*BackupBackend, error) {
+	r.targetsMutex.Lock()
+	defer r.targetsMutex.Unlock()
+	if target, found := r.targets[storageName]; found {
+		return target, nil
+	}
+	target, err := newStorageBackend(r.storages[storageName], r.routineName, r.removeFullBackup)
+	if err != nil {
+		return nil, fmt.Errorf("cannot access storage %s: %w", storageName, err)
+	}
+	r.targets[storageName] = target
+	return target, nil
+}
+
+// setStatus records the replication status in the metadata of the backup.
+func (r *replicator) setStatus(path string, status model.ReplicationStatus) error {
+	return r.source.updateMetadata(path, func(metadata *model.BackupMetadata) bool {
+		for i := range metadata.Replication {
+			if metadata.Replication[i].Storage == status.Storage {
+				metadata.Replication[i] = status
+				return true
+			}
+		}
+		metadata.Replication = append(metadata.Replication, status)
+		return true
+	})
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aerospike/backup/pkg/model"
+	"github.com/aerospike/backup/pkg/util"
+)
+
+func replicationConfig(t *testing.T, targetType model.StorageType) *model.Config {
+	t.Helper()
+	return &model.Config{
+		Storage: map[string]*model.Storage{
+			"source": {Type: model.Local, Path: util.Ptr(t.TempDir())},
+			"target": {Type: targetType, Path: util.Ptr(t.TempDir())},
+		},
+		BackupPolicies: map[string]*model.BackupPolicy{
+			"policy": {MaxRetries: util.Ptr[int32](1), RetryDelay: util.Ptr[int32](1)},
+		},
+		BackupRoutines: map[string]*model.BackupRoutine{
+			"routine": {Storage: "source", BackupPolicy: "policy", ReplicateTo: []string{"target"}},
+		},
+	}
+}
+
+func TestReplicator_ReplicateBackup(t *testing.T) {
+	config := replicationConfig(t, model.Local)
+	source := newBackend(config, "routine", nil)
+	replicator := newReplicator(config, "routine", source)
+
+	path := getFullPath(source.fullBackupsPath, &model.BackupPolicy{}, "ns1", time.UnixMilli(10))
+	source.CreateFolder(path)
+	_ = source.write(filepath.Join(path, "ns1_0.asb"), []byte("data"))
+	_ = source.writeBackupMetadata(path, model.BackupMetadata{Created: time.UnixMilli(10), Namespace: "ns1"})
+	configPath := getConfigurationPath(source.fullBackupsPath, &model.BackupPolicy{}, time.UnixMilli(10))
+	_ = source.write(filepath.Join(configPath, "aerospike_0.conf"), []byte("conf"))
+
+	replicator.replicateBackup(path)
+	replicator.replicateFolder(configPath)
+	replicator.running.Wait()
+
+	details, err := source.readBackupDetails(path, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(details.Replication) != 1 || details.Replication[0].State != model.ReplicationDone ||
+		details.Replication[0].Bytes != 4 {
+		t.Errorf("Expected replication done, got %v", details.Replication)
+	}
+
+	target, _ := replicator.target("target")
+	list, _ := target.FullBackupList(&model.TimeBounds{})
+	if len(list) != 1 || list[0].Namespace != "ns1" || list[0].Replication != nil {
+		t.Errorf("Expected replicated backup, got %v", list)
+	}
+	content, _ := os.ReadFile(filepath.Join(target.fullBackupsPath, "10", model.DataDirectory, "ns1", "ns1_0.asb"))
+	if string(content) != "data" {
+		t.Errorf("Expected replicated backup file, got %s", content)
+	}
+	configuration, err := target.ReadClusterConfiguration(
+		filepath.Join(target.fullBackupsPath, "10", model.ConfigurationBackupDirectory))
+	if err != nil || len(configuration) == 0 {
+		t.Errorf("Expected replicated configuration, got %v", err)
+	}
+}
+
+func TestReplicator_Failed(t *testing.T) {
+	config := replicationConfig(t, model.SFTP)
+	config.Storage["target"].SftpHost = util.Ptr("localhost")
+	config.Storage["target"].SftpKnownHostsFile = util.Ptr(filepath.Join(t.TempDir(), "known_hosts"))
+	source := newBackend(config, "routine", nil)
+	replicator := newReplicator(config, "routine", source)
+
+	path := getIncrementalPath(source.incrementalBackupsPath, "ns1", time.UnixMilli(20))
+	source.CreateFolder(path)
+	_ = source.write(filepath.Join(path, "ns1_0.asb"), []byte("data"))
+	_ = source.writeBackupMetadata(path, model.BackupMetadata{Created: time.UnixMilli(20), Namespace: "ns1"})
+
+	replicator.replicateBackup(path)
+	replicator.running.Wait()
+
+	details, _ := source.readBackupDetails(path, false)
+	if len(details.Replication) != 1 || details.Replication[0].State != model.ReplicationFailed ||
+		details.Replication[0].Attempts != 2 || details.Replication[0].Error == "" {
+		t.Errorf("Expected replication failed after 2 attempts, got %v", details.Replication)
+	}
+}
+
+func TestReplicator_Resume(t *testing.T) {
+	config := replicationConfig(t, model.Local)
+	source := newBackend(config, "routine", nil)
+	stopped := newReplicator(config, "routine", source)
+	stopped.stop()
+
+	path := getIncrementalPath(source.incrementalBackupsPath, "ns1", time.UnixMilli(20))
+	source.CreateFolder(path)
+	_ = source.write(filepath.Join(path, "ns1_0.asb"), []byte("data"))
+	_ = source.writeBackupMetadata(path, model.BackupMetadata{Created: time.UnixMilli(20), Namespace: "ns1"})
+
+	// the replication is recorded but not started once the replicator is stopped
+	stopped.replicateBackup(path)
+	stopped.running.Wait()
+	details, _ := source.readBackupDetails(path, false)
+	if len(details.Replication) != 1 || details.Replication[0].State != model.ReplicationPending {
+		t.Fatalf("Expected replication pending, got %v", details.Replication)
+	}
+
+	replicator := newReplicator(config, "routine", source)
+	replicator.resume()
+	replicator.running.Wait()
+
+	details, _ = source.readBackupDetails(path, false)
+	if len(details.Replication) != 1 || details.Replication[0].State != model.ReplicationDone ||
+		details.Replication[0].Attempts != 1 {
+		t.Errorf("Expected replication resumed, got %v", details.Replication)
+	}
+}
+
+func TestReplicator_NotConfigured(t *testing.T) {
+	config := replicationConfig(t, model.Local)
+	config.BackupRoutines["routine"].ReplicateTo = nil
+	replicator := newReplicator(config, "routine", newBackend(config, "routine", nil))
+	if replicator != nil {
+		t.Fatal("Expected no replicator")
+	}
+	replicator.replicateBackup("path") // no-op
+}
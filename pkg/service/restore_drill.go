@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/aerospike/backup/pkg/model"
+	"github.com/reugn/go-quartz/quartz"
+)
+
+const quartzGroupRestoreDrill = "restore-drill"
+
+// restoreDrillHistorySize is the number of drill results kept per routine.
+const restoreDrillHistorySize = 50
+
+// restoreDrills holds the latest drill results of each routine, the latest first.
+var restoreDrills = struct {
+	sync.Mutex
+	history map[string][]model.RestoreDrillResult
+}{
+	history: make(map[string][]model.RestoreDrillResult),
+}
+
+// RestoreDrillHistory returns the latest restore drill results of the routine, the latest first.
+func RestoreDrillHistory(routineName string) []model.RestoreDrillResult {
+	restoreDrills.Lock()
+	defer restoreDrills.Unlock()
+	return slices.Clone(restoreDrills.history[routineName])
+}
+
+func recordRestoreDrill(result *model.RestoreDrillResult) {
+	restoreDrills.Lock()
+	defer restoreDrills.Unlock()
+	history := append([]model.RestoreDrillResult{*result}, restoreDrills.history[result.Routine]...)
+	restoreDrills.history[result.Routine] = history[:min(len(history), restoreDrillHistorySize)]
+}
+
+// RunRestoreDrill restores the latest backup of the drill namespace of the routine
+// to the drill destination, and compares the number of restored records with the
+// metadata of the applied backups. The result is recorded in the drill history.
+func (r *RestoreMemory) RunRestoreDrill(routineName string, now time.Time) *model.RestoreDrillResult {
+	result := &model.RestoreDrillResult{Routine: routineName, Started: now}
+	err := r.restoreDrill(routineName, now, result)
+	switch {
+	case err != nil:
+		result.Error = err.Error()
+	case result.Result.TotalRecords != result.ExpectedRecords:
+		result.Error = fmt.Sprintf("restored %d records, expected %d",
+			result.Result.TotalRecords, result.ExpectedRecords)
+	default:
+		result.Passed = true
+	}
+	result.Finished = currentTime()
+
+	if result.Passed {
+		slog.Info("Restore drill passed", "name", routineName, "namespace", result.Namespace,
+			"records", result.Result.TotalRecords)
+		restoreDrillCounter.WithLabelValues(routineName, "pass").Inc()
+		restoreDrillPassedGauge.WithLabelValues(routineName).Set(1)
+	} else {
+		slog.Warn("Restore drill failed", "name", routineName, "namespace", result.Namespace,
+			"err", result.Error)
+		restoreDrillCounter.WithLabelValues(routineName, "fail").Inc()
+		restoreDrillPassedGauge.WithLabelValues(routineName).Set(0)
+	}
+	recordRestoreDrill(result)
+	return result
+}
+
+func (r *RestoreMemory) restoreDrill(routineName string, now time.Time, result *model.RestoreDrillResult) error {
+	routine, found := r.config.BackupRoutines[routineName]
+	if !found || routine.RestoreDrill == nil {
+		return fmt.Errorf("restore drill is not configured for routine %s", routineName)
+	}
+	drill := routine.RestoreDrill
+	cluster, found := r.config.AerospikeClusters[drill.DestinationCluster]
+	if !found {
+		return fmt.Errorf("restore drill cluster %s not found", drill.DestinationCluster)
+	}
+	reader, found := r.backends.GetReader(routineName)
+	if !found {
+		return fmt.Errorf("backend '%s' not found for restore", routineName)
+	}
+
+	namespace := *drill.Namespace.Source
+	result.Namespace = namespace
+	fullBackups, err := r.findLastFullBackup(reader, now.UnixMilli())
+	if err != nil {
+		return fmt.Errorf("last full backup not found: %w", err)
+	}
+	i := slices.IndexFunc(fullBackups, func(backup model.BackupDetails) bool {
+		return backup.Namespace == namespace
+	})
+	if i < 0 {
+		return fmt.Errorf("no full backup of namespace %s found", namespace)
+	}
+	fullBackup := fullBackups[i]
+	incrementalBackups, err := r.findIncrementalBackupsForNamespace(
+		reader, fullBackup.Created.UnixMilli(), now.UnixMilli(), namespace)
+	if err != nil {
+		return fmt.Errorf("could not find incremental backups for namespace %s: %w", namespace, err)
+	}
+	if err := r.checkSignatures(append([]model.BackupDetails{fullBackup}, incrementalBackups...)...); err != nil {
+		return err
+	}
+	result.BackupTime = &fullBackup.Created
+	result.BackupCount = 1 + len(incrementalBackups)
+	result.ExpectedRecords = fullBackup.RecordCount
+	for _, backup := range incrementalBackups {
+		result.ExpectedRecords += backup.RecordCount
+	}
+
+	request := &model.RestoreTimestampRequest{
+		DestinationCuster: cluster,
+		Policy:            &model.RestorePolicy{Namespace: drill.Namespace},
+		Time:              now.UnixMilli(),
+		Routine:           routineName,
+	}
+	jobID := r.restoreJobs.newJob()
+	if err := r.restoreNamespace(reader, request, jobID, fullBackup); err != nil {
+		r.restoreJobs.setFailed(jobID, err)
+		return err
+	}
+	r.restoreJobs.setDone(jobID)
+	status, err := r.restoreJobs.getStatus(jobID)
+	if err != nil {
+		return err
+	}
+	result.Result = status.RestoreResult
+	return nil
+}
+
+// restoreDrillJob implements the quartz.Job interface.
+type restoreDrillJob struct {
+	restore     *RestoreMemory
+	routineName string
+}
+
+var _ quartz.Job = (*restoreDrillJob)(nil)
+
+// Execute is called by a Scheduler when the Trigger associated with this job fires.
+func (j *restoreDrillJob) Execute(_ context.Context) error {
+	j.restore.RunRestoreDrill(j.routineName, currentTime())
+	return nil
+}
+
+// Description returns the description of the restore drill job.
+func (j *restoreDrillJob) Description() string {
+	return "restore drill job"
+}
+
+func scheduleRestoreDrill(scheduler quartz.Scheduler, restore *RestoreMemory,
+	routine *model.BackupRoutine, routineName string) error {
+	trigger, err := quartz.NewCronTrigger(routine.RestoreDrill.IntervalCron)
+	if err != nil {
+		return err
+	}
+	jobDetail := quartz.NewJobDetail(
+		&restoreDrillJob{restore: restore, routineName: routineName},
+		quartz.NewJobKeyWithGroup(routineName, quartzGroupRestoreDrill),
+	)
+	return scheduler.ScheduleJob(jobDetail, trigger)
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aerospike/backup/pkg/model"
+	"github.com/aerospike/backup/pkg/util"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// drillRestore returns the restore service of the routine with a restore drill, with
+// a full backup at 1000 and incremental backups at 2000 and 3000.
+func drillRestore(t *testing.T) (*memoryAccessor, *RestoreMemory) {
+	t.Helper()
+	accessor := newMemoryAccessor()
+	useFakeBackup(t, &fakeBackup{accessor: accessor, records: 100, incrementalRecords: 10})
+	config := flowConfig()
+	config.AerospikeClusters["scratch"] = &model.AerospikeCluster{}
+	config.BackupRoutines["routine"].RestoreDrill = &model.RestoreDrill{
+		IntervalCron:       yearlyCron,
+		DestinationCluster: "scratch",
+		Namespace: &model.RestoreNamespace{
+			Source:      util.Ptr("ns1"),
+			Destination: util.Ptr("drill"),
+		},
+	}
+	backends := flowBackends(accessor)
+	backend, _ := backends.Get("routine")
+	handler, err := newBackupHandler(config, "routine", backend, backends)
+	require.NoError(t, err)
+	handler.runFullBackup(time.UnixMilli(1000))
+	handler.runIncrementalBackup(time.UnixMilli(2000))
+	handler.runIncrementalBackup(time.UnixMilli(3000))
+
+	restore := newRestoreMemory(backends, config, nil)
+	restore.restoreService = &fakeRestore{accessor: accessor}
+	return accessor, restore
+}
+
+func TestRunRestoreDrill(t *testing.T) {
+	accessor, restore := drillRestore(t)
+
+	result := restore.RunRestoreDrill("routine", time.UnixMilli(5000))
+
+	assert.True(t, result.Passed, result.Error)
+	assert.Equal(t, "ns1", result.Namespace)
+	assert.Equal(t, int64(1000), result.BackupTime.UnixMilli())
+	assert.Equal(t, 3, result.BackupCount)
+	assert.Equal(t, uint64(120), result.ExpectedRecords)
+	assert.Equal(t, uint64(120), result.Result.TotalRecords)
+	assert.Equal(t, []string{
+		"backups/routine/backup/1000/data/ns1",
+		"backups/routine/incremental/2000/data/ns1",
+		"backups/routine/incremental/3000/data/ns1",
+	}, restore.restoreService.(*fakeRestore).restored)
+	assert.Equal(t, float64(1), testutil.ToFloat64(restoreDrillPassedGauge.WithLabelValues("routine")))
+
+	// a record is lost from an incremental backup
+	require.NoError(t, accessor.write("backups/routine/incremental/3000/data/ns1/ns1_0.asb",
+		[]byte("namespace: ns1\nrecords: 9\n")))
+	result = restore.RunRestoreDrill("routine", time.UnixMilli(6000))
+
+	assert.False(t, result.Passed)
+	assert.Equal(t, "restored 119 records, expected 120", result.Error)
+	assert.Equal(t, float64(0), testutil.ToFloat64(restoreDrillPassedGauge.WithLabelValues("routine")))
+
+	history := RestoreDrillHistory("routine")
+	require.Len(t, history, 2)
+	assert.False(t, history[0].Passed, "the latest result first")
+	assert.True(t, history[1].Passed)
+}
+
+func TestRunRestoreDrill_NoBackup(t *testing.T) {
+	_, restore := drillRestore(t)
+
+	result := restore.RunRestoreDrill("routine", time.UnixMilli(500))
+
+	assert.False(t, result.Passed)
+	assert.Contains(t, result.Error, "last full backup not found")
+	assert.Zero(t, result.BackupCount)
+}
+
+func TestRecordRestoreDrill(t *testing.T) {
+	for i := range restoreDrillHistorySize + 5 {
+		recordRestoreDrill(&model.RestoreDrillResult{Routine: "history", Started: time.UnixMilli(int64(i))})
+	}
+
+	history := RestoreDrillHistory("history")
+
+	assert.Len(t, history, restoreDrillHistorySize)
+	assert.Equal(t, int64(restoreDrillHistorySize+4), history[0].Started.UnixMilli())
+	assert.Empty(t, RestoreDrillHistory("unknown"))
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log/slog"
 	"net/url"
+	"os"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -22,17 +23,28 @@ type RestoreMemory struct {
 	restoreJobs    *JobsHolder
 	restoreService shared.Restore
 	backends       BackendsHolder
+	signer         *backupSigner // verifies the restored backups, if configured
 }
 
 var _ RestoreService = (*RestoreMemory)(nil)
 
 // NewRestoreMemory returns a new RestoreMemory instance.
-func NewRestoreMemory(backends BackendsHolder, config *model.Config) *RestoreMemory {
+// It returns an error if the configured signing keys cannot be loaded.
+func NewRestoreMemory(backends BackendsHolder, config *model.Config) (*RestoreMemory, error) {
+	signer, err := loadSigner(config)
+	if err != nil {
+		return nil, err
+	}
+	return newRestoreMemory(backends, config, signer), nil
+}
+
+func newRestoreMemory(backends BackendsHolder, config *model.Config, signer *backupSigner) *RestoreMemory {
 	return &RestoreMemory{
 		restoreJobs:    NewJobsHolder(),
 		restoreService: shared.NewRestore(),
 		backends:       backends,
 		config:         config,
+		signer:         signer,
 	}
 }
 
@@ -41,8 +53,11 @@ func (r *RestoreMemory) Restore(request *model.RestoreRequestInternal) (int, err
 	if err := validateStorageContainsBackup(request.SourceStorage); err != nil {
 		return 0, err
 	}
+	if err := r.checkStoredSignature(request.SourceStorage); err != nil {
+		return 0, err
+	}
 	go func() {
-		restoreResult, err := r.runRestoreService(request)
+		restoreResult, err := r.runRestoreService(request, restoreJob(jobID, "", request))
 		if err != nil {
 			r.restoreJobs.setFailed(jobID, fmt.Errorf("failed restore operation: %w", err))
 			return
@@ -53,18 +68,76 @@ func (r *RestoreMemory) Restore(request *model.RestoreRequestInternal) (int, err
 	return jobID, nil
 }
 
-func (r *RestoreMemory) runRestoreService(request *model.RestoreRequestInternal) (*model.RestoreResult, error) {
+// runRestoreService runs the restore through the job queue.
+func (r *RestoreMemory) runRestoreService(request *model.RestoreRequestInternal,
+	job model.QueuedJob) (*model.RestoreResult, error) {
+	cleanup, err := stageBackupFiles(request)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
 	var result *model.RestoreResult
-	var err error
 	restoreRunFunc := func() {
 		request.SourceStorage.SetDefaultProfile()
 		result, err = r.restoreService.RestoreRun(request)
 	}
-	out := stdio.Stderr.Capture(restoreRunFunc)
+	var out string
+	jobs.run(job, func() {
+		out = stdio.Stderr.Capture(restoreRunFunc)
+	})
 	util.LogCaptured(out)
 	return result, err
 }
 
+// restoreJob returns the job queue entry of the restore job.
+func restoreJob(jobID int, routine string, request *model.RestoreRequestInternal) model.QueuedJob {
+	var namespace string
+	if request.Policy != nil && request.Policy.Namespace != nil && request.Policy.Namespace.Source != nil {
+		namespace = *request.Policy.Namespace.Source
+	}
+	return model.QueuedJob{
+		Type:      model.JobTypeRestore,
+		Routine:   routine,
+		Namespace: namespace,
+		Cluster:   clusterKey(request.DestinationCuster),
+		JobID:     jobID,
+	}
+}
+
+// stageBackupFiles downloads the backup files from the storage types not supported
+// by the shared library into a local folder, and points the request to it.
+// The returned function removes the local folder.
+func stageBackupFiles(request *model.RestoreRequestInternal) (func(), error) {
+	if !isStaged(request.SourceStorage) {
+		return func() {}, nil
+	}
+	// the backup key includes the protocol and the bucket
+	parsed, err := url.Parse(*request.Dir)
+	if err != nil {
+		return nil, err
+	}
+	accessor, _, err := newStorageAccessor(request.SourceStorage)
+	if err != nil {
+		return nil, err
+	}
+	localDir, err := os.MkdirTemp("", "aerospike-restore-")
+	if err != nil {
+		return nil, err
+	}
+	cleanup := func() {
+		if err := os.RemoveAll(localDir); err != nil {
+			slog.Warn("Could not remove staging folder", "path", localDir, "err", err)
+		}
+	}
+	if err := accessor.(stagedStorage).download(strings.Trim(parsed.Path, "/"), localDir); err != nil {
+		cleanup()
+		return nil, fmt.Errorf("could not download backup files from %s: %w", *request.Dir, err)
+	}
+	request.Dir = &localDir
+	return cleanup, nil
+}
+
 func (r *RestoreMemory) RestoreByTime(request *model.RestoreTimestampRequest) (int, error) {
 	reader, found := r.backends.GetReader(request.Routine)
 	if !found {
@@ -74,11 +147,81 @@ func (r *RestoreMemory) RestoreByTime(request *model.RestoreTimestampRequest) (i
 	if err != nil {
 		return 0, fmt.Errorf("last full backup not found: %v", err)
 	}
+	if err := r.checkBackupChains(reader, request, fullBackups); err != nil {
+		return 0, err
+	}
 	jobID := r.restoreJobs.newJob()
 	go r.restoreByTimeSync(reader, request, jobID, fullBackups)
 	return jobID, nil
 }
 
+// checkBackupChains returns an error if the backups to restore are not validly signed,
+// when the signatures are required, or if there is a gap in the incremental backup chain
+// of any namespace before the requested time, unless the request allows gaps.
+func (r *RestoreMemory) checkBackupChains(backend BackupListReader,
+	request *model.RestoreTimestampRequest,
+	fullBackups []model.BackupDetails,
+) error {
+	for _, fullBackup := range fullBackups {
+		incrementalBackups, err := r.findIncrementalBackupsForNamespace(
+			backend, fullBackup.Created.UnixMilli(), request.Time, fullBackup.Namespace)
+		if err != nil {
+			return fmt.Errorf("could not find incremental backups for namespace %s: %v", fullBackup.Namespace, err)
+		}
+		if err := r.checkSignatures(append([]model.BackupDetails{fullBackup}, incrementalBackups...)...); err != nil {
+			return err
+		}
+		gaps := filterIssues(chainIssues([]model.BackupDetails{fullBackup}, incrementalBackups), model.ChainGap)
+		if len(gaps) == 0 {
+			continue
+		}
+		gap := gaps[0]
+		if !request.AllowGaps {
+			return fmt.Errorf("%w in namespace %s between %s and %s", ErrBackupChainGap, gap.Namespace,
+				gap.Previous.Format(time.RFC3339), gap.From.Format(time.RFC3339))
+		}
+		slog.Warn("Restoring past a gap in the incremental backup chain", "routine", request.Routine,
+			"namespace", gap.Namespace, "since", gap.Previous, "until", gap.From)
+	}
+	return nil
+}
+
+// checkSignatures returns an error if any of the backups is not validly signed,
+// when the signatures are required.
+func (r *RestoreMemory) checkSignatures(backups ...model.BackupDetails) error {
+	if !r.signer.isRequired() {
+		return nil
+	}
+	for _, backup := range backups {
+		if backup.SignatureValid == nil || !*backup.SignatureValid {
+			return fmt.Errorf("%w: %s", ErrBackupSignature, backupKey(backup))
+		}
+	}
+	return nil
+}
+
+// checkStoredSignature returns an error if the backup in the storage is not validly
+// signed, when the signatures are required. The storage path is expected to point
+// to the namespace folder of the backup, which holds its metadata.
+func (r *RestoreMemory) checkStoredSignature(storage *model.Storage) error {
+	if !r.signer.isRequired() {
+		return nil
+	}
+	accessor, path, err := newStorageAccessor(storage)
+	if err != nil {
+		return err
+	}
+	backend := &BackupBackend{StorageAccessor: accessor, signer: r.signer}
+	details, err := backend.readBackupDetails(path, false)
+	if err != nil {
+		return fmt.Errorf("%w: no backup metadata found in %s", ErrBackupSignature, path)
+	}
+	details.Key = &path
+	details.Manifest, _ = backend.readManifest(path)
+	details.SignatureValid = backend.signatureValid(details)
+	return r.checkSignatures(details)
+}
+
 func (r *RestoreMemory) restoreByTimeSync(backend BackupListReader,
 	request *model.RestoreTimestampRequest,
 	jobID int,
@@ -99,7 +242,7 @@ func (r *RestoreMemory) restoreNamespace(
 	request *model.RestoreTimestampRequest,
 	jobID int, fullBackup model.BackupDetails,
 ) error {
-	result, err := r.restoreFromPath(request, fullBackup.Key)
+	result, err := r.restoreFromPath(request, jobID, fullBackup)
 	if err != nil {
 		return fmt.Errorf("could not restore full backup for namespace %s: %v", fullBackup.Namespace, err)
 	}
@@ -112,7 +255,7 @@ func (r *RestoreMemory) restoreNamespace(
 	}
 	slog.Info("Apply incremental backups", "size", len(incrementalBackups))
 	for _, incrBackup := range incrementalBackups {
-		result, err := r.restoreFromPath(request, incrBackup.Key)
+		result, err := r.restoreFromPath(request, jobID, incrBackup)
 		if err != nil {
 			return fmt.Errorf("could not restore incremental backup %s: %v", *incrBackup.Key, err)
 		}
@@ -123,15 +266,16 @@ func (r *RestoreMemory) restoreNamespace(
 
 func (r *RestoreMemory) restoreFromPath(
 	request *model.RestoreTimestampRequest,
-	backupPath *string,
+	jobID int,
+	backup model.BackupDetails,
 ) (*model.RestoreResult, error) {
-	restoreRequest := r.toRestoreRequest(request)
-	restoreResult, err := r.runRestoreService(&model.RestoreRequestInternal{
-		RestoreRequest: *restoreRequest,
-		Dir:            backupPath,
-	})
+	restoreRequest := &model.RestoreRequestInternal{
+		RestoreRequest: *r.toRestoreRequest(request, backup.Storage),
+		Dir:            backup.Key,
+	}
+	restoreResult, err := r.runRestoreService(restoreRequest, restoreJob(jobID, request.Routine, restoreRequest))
 	if err != nil {
-		return nil, fmt.Errorf("could not restore backup at %s: %w", *backupPath, err)
+		return nil, fmt.Errorf("could not restore backup at %s: %w", *backup.Key, err)
 	}
 
 	return restoreResult, nil
@@ -231,9 +375,17 @@ func calculateConfigurationBackupPath(path string) (string, error) {
 	return filepath.Join(base, model.ConfigurationBackupDirectory), nil
 }
 
-func (r *RestoreMemory) toRestoreRequest(request *model.RestoreTimestampRequest) *model.RestoreRequest {
+// toRestoreRequest returns the restore request for a backup located in the given storage,
+// the routine storage is used if it is not specified.
+func (r *RestoreMemory) toRestoreRequest(request *model.RestoreTimestampRequest,
+	storageName *string) *model.RestoreRequest {
 	routine := r.config.BackupRoutines[request.Routine]
 	storage := r.config.Storage[routine.Storage]
+	if storageName != nil {
+		if backupStorage, found := r.config.Storage[*storageName]; found {
+			storage = backupStorage
+		}
+	}
 	return model.NewRestoreRequest(
 		request.DestinationCuster,
 		request.Policy,
@@ -244,7 +396,14 @@ func (r *RestoreMemory) toRestoreRequest(request *model.RestoreTimestampRequest)
 
 // JobStatus returns the status of the job with the given id.
 func (r *RestoreMemory) JobStatus(jobID int) (*model.RestoreJobStatus, error) {
-	return r.restoreJobs.getStatus(jobID)
+	status, err := r.restoreJobs.getStatus(jobID)
+	if err != nil {
+		return nil, err
+	}
+	if status.Status == model.JobStatusRunning {
+		status.QueuePosition = jobs.restorePosition(jobID)
+	}
+	return status, nil
 }
 
 func validateStorageContainsBackup(storage *model.Storage) error {
@@ -257,6 +416,24 @@ func validateStorageContainsBackup(storage *model.Storage) error {
 			return err
 		}
 		return context.validateStorageContainsBackup()
+	case model.GcpGCS:
+		context, err := NewGcsContext(storage)
+		if err != nil {
+			return err
+		}
+		return context.validateStorageContainsBackup()
+	case model.AzureBlob:
+		context, err := NewAzureContext(storage)
+		if err != nil {
+			return err
+		}
+		return context.validateStorageContainsBackup()
+	case model.SFTP:
+		context, err := NewSftpContext(storage)
+		if err != nil {
+			return err
+		}
+		return context.validateStorageContainsBackup()
 	}
 	return nil
 }
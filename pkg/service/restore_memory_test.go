@@ -3,6 +3,7 @@ package service
 import (
 	"errors"
 	"os"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -63,7 +64,7 @@ func makeTestRestoreService() *RestoreMemory {
 	}
 
 	backends := BackendHolderMock{}
-	return NewRestoreMemory(&backends, config)
+	return newRestoreMemory(&backends, config, nil)
 }
 
 type BackendMock struct {
@@ -168,7 +169,7 @@ func TestLatestFullBackupBeforeTime(t *testing.T) {
 	if len(result) != 2 {
 		t.Errorf("Expected 2 backups")
 	}
-	if result[0] != backupList[1] {
+	if !reflect.DeepEqual(result[0], backupList[1]) {
 		t.Errorf("Expected the latest backup, but got %+v", result)
 	}
 }
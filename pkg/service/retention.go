@@ -0,0 +1,279 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/aerospike/backup/pkg/model"
+)
+
+// backupChain is a full backup together with the incremental backups created on top of it.
+type backupChain struct {
+	// The creation time of the full backup, shared by all the namespaces in it.
+	created time.Time
+	// The full backup details, one per namespace.
+	full []model.BackupDetails
+	// The incremental backups created after the full backup and before the next one.
+	incrementals []model.BackupDetails
+}
+
+// buildBackupChains groups the given backups into chains, sorted by creation time.
+// Incremental backups created before the first full backup are returned separately.
+func buildBackupChains(fullBackups, incrementalBackups []model.BackupDetails,
+) (chains []*backupChain, orphans []model.BackupDetails) {
+	byTime := make(map[int64]*backupChain)
+	for _, full := range fullBackups {
+		key := full.Created.UnixMilli()
+		chain, found := byTime[key]
+		if !found {
+			chain = &backupChain{created: full.Created}
+			byTime[key] = chain
+			chains = append(chains, chain)
+		}
+		chain.full = append(chain.full, full)
+	}
+	slices.SortFunc(chains, func(a, b *backupChain) int {
+		return a.created.Compare(b.created)
+	})
+
+	for _, incr := range incrementalBackups {
+		// the latest full backup created before the incremental one
+		i, _ := slices.BinarySearchFunc(chains, incr.Created, func(c *backupChain, t time.Time) int {
+			return c.created.Compare(t)
+		})
+		if i == 0 {
+			orphans = append(orphans, incr)
+			continue
+		}
+		chains[i-1].incrementals = append(chains[i-1].incrementals, incr)
+	}
+	return chains, orphans
+}
+
+// retainedChains returns the creation times (epoch millis) of the chains
+// retained by the policy, mapped to the rules retaining them.
+// The chains are expected to be sorted by creation time.
+func retainedChains(policy *model.RetentionPolicy, chains []*backupChain, now time.Time) map[int64][]string {
+	retained := make(map[int64][]string, len(chains))
+	if len(chains) == 0 {
+		return retained
+	}
+	retain := func(chain *backupChain, reason string) {
+		key := chain.created.UnixMilli()
+		retained[key] = append(retained[key], reason)
+	}
+	// the latest full backup is never pruned
+	retain(chains[len(chains)-1], "latest")
+
+	if policy.KeepFull != nil {
+		for i := max(0, len(chains)-*policy.KeepFull); i < len(chains); i++ {
+			retain(chains[i], "keep-full")
+		}
+	}
+	if policy.KeepDays != nil {
+		threshold := now.AddDate(0, 0, -*policy.KeepDays)
+		for _, chain := range chains {
+			if chain.created.After(threshold) {
+				retain(chain, "keep-days")
+			}
+		}
+	}
+	if policy.GFS != nil {
+		for _, tier := range gfsTiers(policy.GFS) {
+			for _, chain := range tier.retain(chains) {
+				retain(chain, "gfs-"+tier.name)
+			}
+		}
+	}
+	return retained
+}
+
+// gfsTier is a single grandfather-father-son retention tier.
+type gfsTier struct {
+	// The name of the tier.
+	name string
+	// The number of periods to keep a full backup for.
+	keep int
+	// period returns the identifier of the period the given time belongs to.
+	period func(t time.Time) string
+}
+
+func gfsTiers(gfs *model.GFSRetention) []gfsTier {
+	var tiers []gfsTier
+	if gfs.Daily != nil {
+		tiers = append(tiers, gfsTier{name: "daily", keep: *gfs.Daily, period: func(t time.Time) string {
+			return t.UTC().Format(time.DateOnly)
+		}})
+	}
+	if gfs.Weekly != nil {
+		tiers = append(tiers, gfsTier{name: "weekly", keep: *gfs.Weekly, period: func(t time.Time) string {
+			year, week := t.UTC().ISOWeek()
+			return fmt.Sprintf("%d-W%02d", year, week)
+		}})
+	}
+	if gfs.Monthly != nil {
+		tiers = append(tiers, gfsTier{name: "monthly", keep: *gfs.Monthly, period: func(t time.Time) string {
+			return t.UTC().Format("2006-01")
+		}})
+	}
+	if gfs.Yearly != nil {
+		tiers = append(tiers, gfsTier{name: "yearly", keep: *gfs.Yearly, period: func(t time.Time) string {
+			return t.UTC().Format("2006")
+		}})
+	}
+	return tiers
+}
+
+// retain returns the latest chain of each of the most recent periods of the tier.
+// The chains are expected to be sorted by creation time.
+func (t gfsTier) retain(chains []*backupChain) []*backupChain {
+	var selected []*backupChain
+	var lastPeriod string
+	for i := len(chains) - 1; i >= 0 && len(selected) < t.keep; i-- {
+		period := t.period(chains[i].created)
+		if period != lastPeriod {
+			selected = append(selected, chains[i])
+			lastPeriod = period
+		}
+	}
+	return selected
+}
+
+// evaluateRetention decides which of the given backups are kept and which are
+// deleted by the retention policy. A nil policy keeps all the backups.
+func evaluateRetention(policy *model.RetentionPolicy, fullBackups, incrementalBackups []model.BackupDetails,
+	now time.Time) *model.PrunePreview {
+	preview := &model.PrunePreview{Delete: []model.PruneCandidate{}, Keep: []model.PruneCandidate{}}
+	add := func(backup model.BackupDetails, backupType string, keep bool, reasons ...string) {
+		candidate := model.PruneCandidate{BackupDetails: backup, Type: backupType, Reasons: reasons}
+		if keep {
+			preview.Keep = append(preview.Keep, candidate)
+			return
+		}
+		preview.Delete = append(preview.Delete, candidate)
+		preview.ReclaimedBytes += backup.ByteCount
+	}
+
+	if policy == nil {
+		for _, backup := range fullBackups {
+			add(backup, model.FullBackupType, true, "no retention policy")
+		}
+		for _, backup := range incrementalBackups {
+			add(backup, model.IncrementalBackupType, true, "no retention policy")
+		}
+		return preview
+	}
+
+	chains, orphans := buildBackupChains(fullBackups, incrementalBackups)
+	retained := retainedChains(policy, chains, now)
+	for _, chain := range chains {
+		// a chain with backups on hold is kept to keep them restorable
+		if heldBackups(chain.full, chain.incrementals) > 0 {
+			key := chain.created.UnixMilli()
+			retained[key] = append(retained[key], "hold")
+		}
+		// the storage refuses to delete locked backups before the lock expires
+		if latestLock(now, chain.full) != nil {
+			key := chain.created.UnixMilli()
+			retained[key] = append(retained[key], "object-lock")
+		}
+	}
+	for _, chain := range chains {
+		reasons, keep := retained[chain.created.UnixMilli()]
+		if !keep {
+			reasons = []string{"not retained by any rule"}
+		}
+		for _, backup := range chain.full {
+			add(backup, model.FullBackupType, keep, reasons...)
+		}
+		for _, backup := range chain.incrementals {
+			switch {
+			case keep:
+				add(backup, model.IncrementalBackupType, true, "full backup retained")
+			case backup.IsLocked(now):
+				add(backup, model.IncrementalBackupType, true, "object-lock")
+			case !policy.IsPruneIncrementals():
+				add(backup, model.IncrementalBackupType, true, "prune-incrementals disabled")
+			default:
+				add(backup, model.IncrementalBackupType, false, "full backup pruned")
+			}
+		}
+	}
+	for _, backup := range orphans {
+		if backup.IsHeld() {
+			add(backup, model.IncrementalBackupType, true, "hold")
+			continue
+		}
+		if backup.IsLocked(now) {
+			add(backup, model.IncrementalBackupType, true, "object-lock")
+			continue
+		}
+		add(backup, model.IncrementalBackupType, !policy.IsPruneIncrementals(),
+			"created before the first full backup")
+	}
+
+	byCreated := func(a, b model.PruneCandidate) int {
+		return a.Created.Compare(b.Created)
+	}
+	slices.SortStableFunc(preview.Delete, byCreated)
+	slices.SortStableFunc(preview.Keep, byCreated)
+	return preview
+}
+
+// applyRetention prunes the backups of the routine that are not retained by
+// the retention policy.
+func (h *BackupHandler) applyRetention(now time.Time) {
+	policy := h.backupFullPolicy.Retention
+	if policy == nil {
+		return
+	}
+
+	preview, err := h.backend.PrunePreview(policy, now)
+	if err != nil {
+		slog.Error("Could not evaluate retention policy", "name", h.routineName, "err", err)
+		return
+	}
+
+	h.pruneBackups(preview.Delete)
+}
+
+// pruneBackups deletes the given backups. The incremental backups are expected
+// after the full backup of their chain, and they are kept if it could not be deleted.
+func (h *BackupHandler) pruneBackups(backups []model.PruneCandidate) {
+	// backups of all namespaces created at the same time share the same folder
+	deleted := make(map[string]bool)
+	fullPruneFailed := false
+	for _, backup := range backups {
+		key := fmt.Sprintf("%s/%d", backup.Type, backup.Created.UnixMilli())
+		if deleted[key] {
+			continue
+		}
+		deleted[key] = true
+		if backup.Type != model.FullBackupType && fullPruneFailed {
+			slog.Warn("Full backup of the chain was not pruned, keeping incremental backup",
+				"name", h.routineName, "created", backup.Created)
+			continue
+		}
+		var err error
+		if backup.Type == model.FullBackupType {
+			err = h.backend.storageBackend(backup.BackupDetails).deleteFullBackup(backup.Created)
+			fullPruneFailed = err != nil
+		} else {
+			err = h.backend.storageBackend(backup.BackupDetails).deleteIncrementalBackup(backup.Created)
+		}
+		if errors.Is(err, ErrBackupLocked) {
+			slog.Warn("Backup is locked and cannot be pruned yet", "name", h.routineName,
+				"type", backup.Type, "created", backup.Created, "err", err)
+			continue
+		}
+		if err != nil {
+			slog.Error("Could not prune backup", "name", h.routineName, "type", backup.Type,
+				"created", backup.Created, "err", err)
+			continue
+		}
+		slog.Info("Pruned backup", "name", h.routineName, "type", backup.Type, "created", backup.Created)
+	}
+}
@@ -0,0 +1,375 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aerospike/backup/pkg/model"
+	"github.com/aerospike/backup/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func backupDetails(created int64, namespace string) model.BackupDetails {
+	return model.BackupDetails{
+		BackupMetadata: model.BackupMetadata{
+			Created:   time.UnixMilli(created),
+			Namespace: namespace,
+		},
+	}
+}
+
+func TestBuildBackupChains(t *testing.T) {
+	full := []model.BackupDetails{
+		backupDetails(30, "ns1"),
+		backupDetails(10, "ns1"),
+		backupDetails(10, "ns2"),
+	}
+	incremental := []model.BackupDetails{
+		backupDetails(5, "ns1"),
+		backupDetails(15, "ns1"),
+		backupDetails(20, "ns2"),
+		backupDetails(35, "ns1"),
+	}
+
+	chains, orphans := buildBackupChains(full, incremental)
+
+	require.Len(t, chains, 2)
+	assert.Equal(t, int64(10), chains[0].created.UnixMilli())
+	assert.Len(t, chains[0].full, 2)
+	assert.Len(t, chains[0].incrementals, 2)
+	assert.Equal(t, int64(30), chains[1].created.UnixMilli())
+	assert.Len(t, chains[1].full, 1)
+	assert.Len(t, chains[1].incrementals, 1)
+	assert.Equal(t, []model.BackupDetails{backupDetails(5, "ns1")}, orphans)
+}
+
+func TestRetainedChains(t *testing.T) {
+	now := time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC)
+	var chains []*backupChain
+	for days := 10; days > 0; days-- {
+		chains = append(chains, &backupChain{created: now.AddDate(0, 0, -days)})
+	}
+	day := func(days int) int64 {
+		return now.AddDate(0, 0, -days).UnixMilli()
+	}
+
+	tests := []struct {
+		name     string
+		policy   *model.RetentionPolicy
+		expected []int64
+	}{
+		{
+			name:     "keep full",
+			policy:   &model.RetentionPolicy{KeepFull: util.Ptr(3)},
+			expected: []int64{day(3), day(2), day(1)},
+		},
+		{
+			name:     "keep days",
+			policy:   &model.RetentionPolicy{KeepDays: util.Ptr(2)},
+			expected: []int64{day(1)},
+		},
+		{
+			name:     "keep days keeps latest",
+			policy:   &model.RetentionPolicy{KeepDays: util.Ptr(1)},
+			expected: []int64{day(1)},
+		},
+		{
+			name:     "union of rules",
+			policy:   &model.RetentionPolicy{KeepFull: util.Ptr(1), KeepDays: util.Ptr(4)},
+			expected: []int64{day(3), day(2), day(1)},
+		},
+		{
+			name:     "keep more than exist",
+			policy:   &model.RetentionPolicy{KeepFull: util.Ptr(20)},
+			expected: []int64{day(10), day(9), day(8), day(7), day(6), day(5), day(4), day(3), day(2), day(1)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retained := retainedChains(tt.policy, chains, now)
+			assert.ElementsMatch(t, tt.expected, retainedKeys(retained))
+		})
+	}
+}
+
+func TestApplyRetention(t *testing.T) {
+	backend := newDiskBackend(t, NewOSDiskAccessor())
+	for _, created := range []int64{100, 200, 300} {
+		writeFullBackup(t, backend, model.BackupMetadata{Created: time.UnixMilli(created), Namespace: "ns1"})
+		writeIncrementalBackup(t, backend,
+			model.BackupMetadata{Created: time.UnixMilli(created + 50), Namespace: "ns1"})
+	}
+
+	handler := &BackupHandler{
+		backend: backend,
+		backupFullPolicy: &model.BackupPolicy{
+			Retention: &model.RetentionPolicy{KeepFull: util.Ptr(2)},
+		},
+		routineName: "routine",
+	}
+	handler.applyRetention(time.UnixMilli(400))
+
+	allTime := &model.TimeBounds{}
+	fullBackups, _ := backend.FullBackupList(allTime)
+	incrementalBackups, _ := backend.IncrementalBackupList(allTime)
+	assert.ElementsMatch(t, []int64{200, 300}, createdMillis(fullBackups))
+	assert.ElementsMatch(t, []int64{250, 350}, createdMillis(incrementalBackups))
+}
+
+// failingFullDeleteAccessor fails to delete the full backups of the given time.
+type failingFullDeleteAccessor struct {
+	*memoryAccessor
+	failing string
+}
+
+func (a *failingFullDeleteAccessor) DeleteFolder(path string) error {
+	if strings.Contains(path, model.FullBackupDirectory) && strings.HasSuffix(path, a.failing) {
+		return errors.New("delete failed")
+	}
+	return a.memoryAccessor.DeleteFolder(path)
+}
+
+func TestApplyRetention_FullDeleteFailed(t *testing.T) {
+	accessor := &failingFullDeleteAccessor{memoryAccessor: newMemoryAccessor(), failing: "100"}
+	backend := newMemoryBackend(accessor.memoryAccessor, "routine", false)
+	backend.StorageAccessor = accessor
+	write := func(path string, created int64) {
+		require.NoError(t, backend.writeBackupMetadata(path,
+			model.BackupMetadata{Created: time.UnixMilli(created), Namespace: "ns1"}))
+	}
+	for _, created := range []int64{100, 200, 300} {
+		write(getFullPath(backend.fullBackupsPath, &model.BackupPolicy{}, "ns1", time.UnixMilli(created)), created)
+		write(getIncrementalPath(backend.incrementalBackupsPath, "ns1", time.UnixMilli(created+50)), created+50)
+	}
+
+	handler := &BackupHandler{
+		backend: backend,
+		backupFullPolicy: &model.BackupPolicy{
+			Retention: &model.RetentionPolicy{KeepFull: util.Ptr(1)},
+		},
+		routineName: "routine",
+	}
+	handler.applyRetention(time.UnixMilli(400))
+
+	// the incremental backups of the chain whose full backup was not deleted are kept
+	allTime := &model.TimeBounds{}
+	fullBackups, _ := backend.FullBackupList(allTime)
+	incrementalBackups, _ := backend.IncrementalBackupList(allTime)
+	assert.ElementsMatch(t, []int64{100, 300}, createdMillis(fullBackups))
+	assert.ElementsMatch(t, []int64{150, 350}, createdMillis(incrementalBackups))
+}
+
+func retainedKeys(retained map[int64][]string) []int64 {
+	keys := make([]int64, 0, len(retained))
+	for created := range retained {
+		keys = append(keys, created)
+	}
+	return keys
+}
+
+func createdMillis(backups []model.BackupDetails) []int64 {
+	result := make([]int64, 0, len(backups))
+	for _, backup := range backups {
+		result = append(result, backup.Created.UnixMilli())
+	}
+	return result
+}
+
+func TestRetainedChains_GFS(t *testing.T) {
+	// daily full backups for 400 days, the latest on Wednesday, 2024-03-20
+	now := time.Date(2024, 3, 20, 12, 0, 0, 0, time.UTC)
+	var chains []*backupChain
+	for days := 399; days >= 0; days-- {
+		chains = append(chains, &backupChain{created: now.AddDate(0, 0, -days)})
+	}
+	date := func(year int, month time.Month, day int) int64 {
+		return time.Date(year, month, day, 12, 0, 0, 0, time.UTC).UnixMilli()
+	}
+
+	tests := []struct {
+		name     string
+		gfs      *model.GFSRetention
+		expected []int64
+	}{
+		{
+			name: "daily",
+			gfs:  &model.GFSRetention{Daily: util.Ptr(3)},
+			expected: []int64{
+				date(2024, 3, 20), date(2024, 3, 19), date(2024, 3, 18),
+			},
+		},
+		{
+			name: "weekly",
+			gfs:  &model.GFSRetention{Weekly: util.Ptr(3)},
+			expected: []int64{
+				date(2024, 3, 20), date(2024, 3, 17), date(2024, 3, 10),
+			},
+		},
+		{
+			name: "monthly",
+			gfs:  &model.GFSRetention{Monthly: util.Ptr(3)},
+			expected: []int64{
+				date(2024, 3, 20), date(2024, 2, 29), date(2024, 1, 31),
+			},
+		},
+		{
+			name: "yearly",
+			gfs:  &model.GFSRetention{Yearly: util.Ptr(5)},
+			expected: []int64{
+				date(2024, 3, 20), date(2023, 12, 31),
+			},
+		},
+		{
+			name: "combined tiers",
+			gfs:  &model.GFSRetention{Daily: util.Ptr(2), Weekly: util.Ptr(2), Monthly: util.Ptr(2)},
+			expected: []int64{
+				date(2024, 3, 20), date(2024, 3, 19), date(2024, 3, 17), date(2024, 2, 29),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retained := retainedChains(&model.RetentionPolicy{GFS: tt.gfs}, chains, now)
+			assert.ElementsMatch(t, tt.expected, retainedKeys(retained))
+		})
+	}
+}
+
+func TestRetainedChains_Reasons(t *testing.T) {
+	now := time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC)
+	chains := []*backupChain{
+		{created: now.AddDate(0, 0, -3)},
+		{created: now.AddDate(0, 0, -2)},
+		{created: now.AddDate(0, 0, -1)},
+	}
+	policy := &model.RetentionPolicy{KeepFull: util.Ptr(2), KeepDays: util.Ptr(1)}
+
+	retained := retainedChains(policy, chains, now)
+
+	assert.Equal(t, map[int64][]string{
+		now.AddDate(0, 0, -2).UnixMilli(): {"keep-full"},
+		now.AddDate(0, 0, -1).UnixMilli(): {"latest", "keep-full"},
+	}, retained)
+}
+
+func TestEvaluateRetention(t *testing.T) {
+	full := []model.BackupDetails{
+		{BackupMetadata: model.BackupMetadata{Created: time.UnixMilli(100), ByteCount: 10}},
+		{BackupMetadata: model.BackupMetadata{Created: time.UnixMilli(200), ByteCount: 20}},
+	}
+	incremental := []model.BackupDetails{
+		{BackupMetadata: model.BackupMetadata{Created: time.UnixMilli(50), ByteCount: 1}},
+		{BackupMetadata: model.BackupMetadata{Created: time.UnixMilli(150), ByteCount: 2}},
+		{BackupMetadata: model.BackupMetadata{Created: time.UnixMilli(250), ByteCount: 4}},
+	}
+
+	tests := []struct {
+		name      string
+		policy    *model.RetentionPolicy
+		deleted   []int64
+		reclaimed uint64
+	}{
+		{
+			name:    "no policy",
+			deleted: []int64{},
+		},
+		{
+			name:      "prune incrementals",
+			policy:    &model.RetentionPolicy{KeepFull: util.Ptr(1)},
+			deleted:   []int64{50, 100, 150},
+			reclaimed: 13,
+		},
+		{
+			name:      "keep incrementals",
+			policy:    &model.RetentionPolicy{KeepFull: util.Ptr(1), PruneIncrementals: util.Ptr(false)},
+			deleted:   []int64{100},
+			reclaimed: 10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			preview := evaluateRetention(tt.policy, full, incremental, time.UnixMilli(300))
+			deleted := make([]int64, 0)
+			for _, backup := range preview.Delete {
+				deleted = append(deleted, backup.Created.UnixMilli())
+				assert.NotEmpty(t, backup.Reasons)
+			}
+			assert.Equal(t, tt.deleted, deleted)
+			assert.Len(t, preview.Keep, len(full)+len(incremental)-len(tt.deleted))
+			assert.Equal(t, tt.reclaimed, preview.ReclaimedBytes)
+		})
+	}
+}
+
+func TestPrunePreview_DeletesNothing(t *testing.T) {
+	backend := newDiskBackend(t, NewOSDiskAccessor())
+	for _, created := range []int64{100, 200} {
+		writeFullBackup(t, backend, model.BackupMetadata{Created: time.UnixMilli(created), Namespace: "ns1",
+			ByteCount: 5})
+	}
+
+	preview, err := backend.PrunePreview(&model.RetentionPolicy{KeepFull: util.Ptr(1)}, time.UnixMilli(300))
+
+	require.NoError(t, err)
+	require.Len(t, preview.Delete, 1)
+	assert.Equal(t, model.FullBackupType, preview.Delete[0].Type)
+	assert.Equal(t, int64(100), preview.Delete[0].Created.UnixMilli())
+	assert.Equal(t, uint64(5), preview.ReclaimedBytes)
+	fullBackups, _ := backend.FullBackupList(&model.TimeBounds{})
+	assert.Len(t, fullBackups, 2)
+}
+
+func TestEvaluateRetention_Hold(t *testing.T) {
+	hold := &model.BackupHold{Reason: "audit", Actor: "auditor"}
+	full := []model.BackupDetails{
+		{BackupMetadata: model.BackupMetadata{Created: time.UnixMilli(100), Hold: hold}},
+		{BackupMetadata: model.BackupMetadata{Created: time.UnixMilli(200)}},
+		{BackupMetadata: model.BackupMetadata{Created: time.UnixMilli(300)}},
+	}
+	incremental := []model.BackupDetails{
+		{BackupMetadata: model.BackupMetadata{Created: time.UnixMilli(50), Hold: hold}},
+		{BackupMetadata: model.BackupMetadata{Created: time.UnixMilli(250), Hold: hold}},
+	}
+
+	preview := evaluateRetention(&model.RetentionPolicy{KeepFull: util.Ptr(1)}, full, incremental, time.UnixMilli(400))
+
+	assert.Empty(t, preview.Delete)
+	for _, backup := range preview.Keep {
+		if backup.Type == model.FullBackupType && backup.Created.UnixMilli() != 300 {
+			assert.Contains(t, backup.Reasons, "hold")
+		}
+	}
+}
+
+func TestEvaluateRetention_ObjectLock(t *testing.T) {
+	lockedUntil := time.UnixMilli(500)
+	full := []model.BackupDetails{
+		{BackupMetadata: model.BackupMetadata{Created: time.UnixMilli(100), LockedUntil: &lockedUntil}},
+		{BackupMetadata: model.BackupMetadata{Created: time.UnixMilli(200)}},
+		{BackupMetadata: model.BackupMetadata{Created: time.UnixMilli(300)}},
+	}
+	incremental := []model.BackupDetails{
+		{BackupMetadata: model.BackupMetadata{Created: time.UnixMilli(50), LockedUntil: &lockedUntil}},
+		{BackupMetadata: model.BackupMetadata{Created: time.UnixMilli(250), LockedUntil: &lockedUntil}},
+	}
+	policy := &model.RetentionPolicy{KeepFull: util.Ptr(1)}
+
+	preview := evaluateRetention(policy, full, incremental, time.UnixMilli(400))
+	require.Len(t, preview.Delete, 1)
+	assert.Equal(t, int64(200), preview.Delete[0].Created.UnixMilli())
+	for _, backup := range preview.Keep {
+		if backup.Created.UnixMilli() != 300 {
+			assert.Contains(t, backup.Reasons, "object-lock")
+		}
+	}
+
+	// the expired locks do not retain the backups
+	preview = evaluateRetention(policy, full, incremental, time.UnixMilli(600))
+	assert.Len(t, preview.Delete, 4)
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/aerospike/backup/pkg/model"
+	"github.com/aerospike/backup/pkg/util"
+	"github.com/reugn/go-quartz/quartz"
+)
+
+// ErrRoutineNotFound is returned for the routines without scheduled backups.
+var ErrRoutineNotFound = errors.New("routine not found")
+
+// routineJobKeys returns the keys of the scheduled full and incremental backup jobs
+// of the routine.
+func routineJobKeys(routineName string) []*quartz.JobKey {
+	return []*quartz.JobKey{
+		quartz.NewJobKeyWithGroup(routineName, quartzGroupBackupFull),
+		quartz.NewJobKeyWithGroup(routineName, quartzGroupBackupIncremental),
+	}
+}
+
+// scheduledHandler returns the backup handler of the scheduled routine.
+func scheduledHandler(scheduler quartz.Scheduler, routineName string) (*BackupHandler, error) {
+	scheduled, err := scheduler.GetScheduledJob(quartz.NewJobKeyWithGroup(routineName, quartzGroupBackupFull))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrRoutineNotFound, routineName)
+	}
+	job, ok := scheduled.JobDetail().Job().(*backupJob)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrRoutineNotFound, routineName)
+	}
+	return job.handler, nil
+}
+
+// PauseRoutine suspends the scheduled full and incremental backups of the routine.
+// The paused state is recorded in the routine backup state, so that the backups
+// stay paused over the service restarts and the configuration changes.
+// The running backups are not interrupted, and the ad-hoc backups are still allowed.
+func PauseRoutine(scheduler quartz.Scheduler, routineName string) error {
+	handler, err := scheduledHandler(scheduler, routineName)
+	if err != nil {
+		return err
+	}
+	if handler.state.IsPaused() {
+		return nil
+	}
+	if err := pauseRoutineJobs(scheduler, routineName); err != nil {
+		return err
+	}
+	handler.state.SetPaused(util.Ptr(time.Now()))
+	if err := handler.backend.writeState(handler.state); err != nil {
+		return fmt.Errorf("failed to write state of routine %s: %w", routineName, err)
+	}
+	slog.Info("Routine paused", "name", routineName)
+	return nil
+}
+
+// ResumeRoutine resumes the scheduled backups of the paused routine.
+// The backups missed while paused are not run.
+func ResumeRoutine(scheduler quartz.Scheduler, routineName string) error {
+	handler, err := scheduledHandler(scheduler, routineName)
+	if err != nil {
+		return err
+	}
+	if !handler.state.IsPaused() {
+		return nil
+	}
+	for _, jobKey := range routineJobKeys(routineName) {
+		scheduled, err := scheduler.GetScheduledJob(jobKey)
+		if err != nil || !scheduled.JobDetail().Options().Suspended {
+			continue
+		}
+		if err := scheduler.ResumeJob(jobKey); err != nil {
+			return fmt.Errorf("failed to resume job %s: %w", jobKey, err)
+		}
+	}
+	handler.state.SetPaused(nil)
+	if err := handler.backend.writeState(handler.state); err != nil {
+		return fmt.Errorf("failed to write state of routine %s: %w", routineName, err)
+	}
+	slog.Info("Routine resumed", "name", routineName)
+	return nil
+}
+
+// pauseRoutineJobs suspends the scheduled backup jobs of the routine.
+func pauseRoutineJobs(scheduler quartz.Scheduler, routineName string) error {
+	for _, jobKey := range routineJobKeys(routineName) {
+		scheduled, err := scheduler.GetScheduledJob(jobKey)
+		if err != nil || scheduled.JobDetail().Options().Suspended {
+			continue // not scheduled or already paused
+		}
+		if err := scheduler.PauseJob(jobKey); err != nil {
+			return fmt.Errorf("failed to pause job %s: %w", jobKey, err)
+		}
+	}
+	return nil
+}
+
+// GetRoutineStatus returns the scheduling status of the routine.
+func GetRoutineStatus(scheduler quartz.Scheduler, routineName string) (*model.RoutineStatus, error) {
+	handler, err := scheduledHandler(scheduler, routineName)
+	if err != nil {
+		return nil, err
+	}
+	status := &model.RoutineStatus{
+		Routine:  routineName,
+		PausedAt: handler.state.GetPausedAt(),
+	}
+	status.Paused = status.PausedAt != nil
+
+	handler.state.Lock()
+	status.LastFullRun = nonZeroTime(handler.state.LastFullRun)
+	status.LastIncrementalRun = nonZeroTime(handler.state.LastIncrRun)
+	handler.state.Unlock()
+
+	if !status.Paused {
+		keys := routineJobKeys(routineName)
+		status.NextFullRun = nextRunTime(scheduler, keys[0])
+		status.NextIncrementalRun = nextRunTime(scheduler, keys[1])
+	}
+	return status, nil
+}
+
+func nonZeroTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// nextRunTime returns the next run time of the scheduled job, or nil if it is not scheduled.
+func nextRunTime(scheduler quartz.Scheduler, jobKey *quartz.JobKey) *time.Time {
+	scheduled, err := scheduler.GetScheduledJob(jobKey)
+	if err != nil || scheduled.NextRunTime() == math.MaxInt64 {
+		return nil
+	}
+	next := time.Unix(0, scheduled.NextRunTime())
+	return &next
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aerospike/backup/pkg/model"
+	"github.com/reugn/go-quartz/quartz"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pausedJobs returns whether the full and incremental backup jobs of the routine are suspended.
+func pausedJobs(t *testing.T, scheduler quartz.Scheduler) []bool {
+	t.Helper()
+	var paused []bool
+	for _, jobKey := range routineJobKeys("routine") {
+		scheduled, err := scheduler.GetScheduledJob(jobKey)
+		require.NoError(t, err)
+		paused = append(paused, scheduled.JobDetail().Options().Suspended)
+	}
+	return paused
+}
+
+func TestPauseAndResumeRoutine(t *testing.T) {
+	accessor := newMemoryAccessor()
+	useFakeBackup(t, &fakeBackup{accessor: accessor, records: 100, incrementalRecords: 10})
+	config := flowConfig()
+	backends := flowBackends(accessor)
+	backend, _ := backends.Get("routine")
+	// no initial full backup is needed
+	require.NoError(t, backend.writeState(&model.BackupState{LastFullRun: time.Now()}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	scheduler, err := ScheduleBackup(ctx, config, backends)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = scheduler.Clear() })
+
+	status, err := GetRoutineStatus(scheduler, "routine")
+	require.NoError(t, err)
+	assert.False(t, status.Paused)
+	assert.NotNil(t, status.LastFullRun)
+	assert.NotNil(t, status.NextFullRun)
+	assert.NotNil(t, status.NextIncrementalRun)
+
+	require.NoError(t, PauseRoutine(scheduler, "routine"))
+	require.NoError(t, PauseRoutine(scheduler, "routine"))
+	assert.Equal(t, []bool{true, true}, pausedJobs(t, scheduler))
+	assert.True(t, backend.readState().IsPaused())
+	status, err = GetRoutineStatus(scheduler, "routine")
+	require.NoError(t, err)
+	assert.True(t, status.Paused)
+	assert.NotNil(t, status.PausedAt)
+	assert.Nil(t, status.NextFullRun)
+
+	// the routine stays paused after the configuration change
+	require.NoError(t, scheduler.Clear())
+	require.NoError(t, scheduleRoutines(scheduler, config, backends, nil))
+	assert.Equal(t, []bool{true, true}, pausedJobs(t, scheduler))
+	status, err = GetRoutineStatus(scheduler, "routine")
+	require.NoError(t, err)
+	assert.True(t, status.Paused)
+
+	require.NoError(t, ResumeRoutine(scheduler, "routine"))
+	require.NoError(t, ResumeRoutine(scheduler, "routine"))
+	assert.Equal(t, []bool{false, false}, pausedJobs(t, scheduler))
+	assert.False(t, backend.readState().IsPaused())
+	status, err = GetRoutineStatus(scheduler, "routine")
+	require.NoError(t, err)
+	assert.False(t, status.Paused)
+	assert.NotNil(t, status.NextFullRun)
+
+	assert.ErrorIs(t, PauseRoutine(scheduler, "unknown"), ErrRoutineNotFound)
+	_, err = GetRoutineStatus(scheduler, "unknown")
+	assert.ErrorIs(t, err, ErrRoutineNotFound)
+}
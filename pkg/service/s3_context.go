@@ -3,46 +3,116 @@ package service
 import (
 	"bytes"
 	"context"
+	"crypto/md5" //nolint:gosec // the SSE-C key digest is required by the S3 API
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/url"
 	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/aerospike/backup/pkg/model"
+	"github.com/aerospike/backup/pkg/shared"
 	"github.com/aerospike/backup/pkg/util"
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/aws/smithy-go"
 	"gopkg.in/yaml.v3"
 )
 
-const s3Protocol = "s3://"
+const (
+	s3Protocol = "s3://"
+	// s3UploadPartSize is the part size of the multipart uploads. An upload has up to
+	// 10,000 parts, so the files are limited to 156 GiB, and buffers up to 5 parts in memory.
+	s3UploadPartSize = 16 * 1024 * 1024
+)
 
 // S3Context is responsible for performing basic operations on S3.
 type S3Context struct {
 	ctx           context.Context
 	client        *s3.Client
+	uploader      *manager.Uploader
 	bucket        string
 	path          string
+	objectOptions s3ObjectOptions
 	metadataCache *util.LoadingCache[string, *model.BackupMetadata]
 }
 
 var _ StorageAccessor = (*S3Context)(nil)
 
+// s3ObjectOptions are the server-side encryption and the storage class
+// applied to the written objects.
+type s3ObjectOptions struct {
+	sse            types.ServerSideEncryption
+	kmsKeyID       *string
+	customerKey    *string
+	customerKeyMD5 *string
+	storageClass   types.StorageClass
+	lockMode       types.ObjectLockMode
+	lockDays       int
+}
+
+func newS3ObjectOptions(storage *model.Storage) (s3ObjectOptions, error) {
+	options := s3ObjectOptions{}
+	if storage.S3ServerSideEncryption != nil {
+		switch *storage.S3ServerSideEncryption {
+		case model.SseS3:
+			options.sse = types.ServerSideEncryptionAes256
+		case model.SseKms:
+			options.sse = types.ServerSideEncryptionAwsKms
+			options.kmsKeyID = storage.S3SseKmsKeyID
+		case model.SseC:
+			encodedKey, err := shared.ReadSecret(storage.S3SseCustomerKeyEnv, storage.S3SseCustomerKeyFile)
+			if err != nil {
+				return options, fmt.Errorf("failed to read s3 sse customer key: %w", err)
+			}
+			key, err := base64.StdEncoding.DecodeString(encodedKey)
+			if err != nil || len(key) != 32 {
+				return options, errors.New("s3 sse customer key should be a base64-encoded 256-bit key")
+			}
+			digest := md5.Sum(key) //nolint:gosec
+			options.customerKey = aws.String(encodedKey)
+			options.customerKeyMD5 = aws.String(base64.StdEncoding.EncodeToString(digest[:]))
+		}
+	}
+	if storage.S3StorageClass != nil {
+		options.storageClass = types.StorageClass(*storage.S3StorageClass)
+	}
+	if storage.S3ObjectLockMode != nil {
+		options.lockMode = types.ObjectLockMode(*storage.S3ObjectLockMode)
+		options.lockDays = *storage.S3ObjectLockDays
+	}
+	return options, nil
+}
+
+// customerAlgorithm returns the SSE-C algorithm, if SSE-C is used.
+func (o s3ObjectOptions) customerAlgorithm() *string {
+	if o.customerKey == nil {
+		return nil
+	}
+	return aws.String(string(types.ServerSideEncryptionAes256))
+}
+
 // NewS3Context returns a new S3Context.
 // Panics on any error during initialization.
 func NewS3Context(storage *model.Storage) (*S3Context, error) {
-	// Load the SDK's configuration from environment and shared config, and
-	// create the client with this.
+	// Load the SDK's configuration from environment and shared config, or the
+	// explicit credentials of the storage, and create the client with this.
 	ctx := context.TODO()
-	cfg, err := createConfig(ctx, storage)
+	cfg, err := shared.LoadS3Config(ctx, storage)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load S3 SDK configuration: %v", err)
 	}
+	objectOptions, err := newS3ObjectOptions(storage)
+	if err != nil {
+		return nil, err
+	}
 
 	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
 		if storage.S3EndpointOverride != nil && *storage.S3EndpointOverride != "" {
@@ -64,12 +134,23 @@ func NewS3Context(storage *model.Storage) (*S3Context, error) {
 	if err != nil {
 		return nil, fmt.Errorf("error checking S3 bucket %s existence: %v", bucketName, err)
 	}
+	if storage.S3ObjectLockMode != nil {
+		if err := checkObjectLockEnabled(ctx, client, bucketName); err != nil {
+			return nil, err
+		}
+	}
+
+	uploader := manager.NewUploader(client, func(u *manager.Uploader) {
+		u.PartSize = s3UploadPartSize
+	})
 
 	s := &S3Context{
-		ctx:    ctx,
-		client: client,
-		bucket: bucketName,
-		path:   strings.TrimPrefix(parsed.Path, "/"),
+		ctx:           ctx,
+		client:        client,
+		uploader:      uploader,
+		bucket:        bucketName,
+		path:          strings.TrimPrefix(parsed.Path, "/"),
+		objectOptions: objectOptions,
 	}
 
 	s.metadataCache = util.NewLoadingCache(ctx, func(path string) (*model.BackupMetadata, error) {
@@ -78,13 +159,19 @@ func NewS3Context(storage *model.Storage) (*S3Context, error) {
 	return s, nil
 }
 
-func createConfig(ctx context.Context, storage *model.Storage) (aws.Config, error) {
-	storage.SetDefaultProfile()
-	return config.LoadDefaultConfig(
-		ctx,
-		config.WithSharedConfigProfile(*storage.S3Profile),
-		config.WithRegion(*storage.S3Region),
-	)
+// checkObjectLockEnabled returns an error if Object Lock is not enabled on the bucket.
+func checkObjectLockEnabled(ctx context.Context, client *s3.Client, bucketName string) error {
+	result, err := client.GetObjectLockConfiguration(ctx, &s3.GetObjectLockConfigurationInput{
+		Bucket: aws.String(bucketName),
+	})
+	if err != nil {
+		return fmt.Errorf("error checking S3 bucket %s object lock configuration: %v", bucketName, err)
+	}
+	if result.ObjectLockConfiguration == nil ||
+		result.ObjectLockConfiguration.ObjectLockEnabled != types.ObjectLockEnabledEnabled {
+		return fmt.Errorf("object lock is not enabled on S3 bucket %s", bucketName)
+	}
+	return nil
 }
 
 func (s *S3Context) readBackupState(stateFilePath string, state *model.BackupState) error {
@@ -110,13 +197,17 @@ func (s *S3Context) readBackupDetails(path string, useCache bool) (model.BackupD
 
 func (s *S3Context) read(filePath string) ([]byte, error) {
 	result, err := s.client.GetObject(s.ctx, &s3.GetObjectInput{
-		Bucket: aws.String(s.bucket),
-		Key:    aws.String(filePath),
+		Bucket:               aws.String(s.bucket),
+		Key:                  aws.String(filePath),
+		SSECustomerAlgorithm: s.objectOptions.customerAlgorithm(),
+		SSECustomerKey:       s.objectOptions.customerKey,
+		SSECustomerKeyMD5:    s.objectOptions.customerKeyMD5,
 	})
 	if err != nil {
 		var opErr *smithy.OperationError
 		if errors.As(err, &opErr) &&
-			(strings.Contains(filePath, model.StateFileName) || strings.Contains(filePath, metadataFile)) &&
+			(strings.Contains(filePath, model.StateFileName) || strings.Contains(filePath, metadataFile) ||
+				strings.Contains(filePath, manifestFile)) &&
 			strings.Contains(opErr.Unwrap().Error(), "StatusCode: 404") {
 			return nil, err
 		}
@@ -133,6 +224,21 @@ func (s *S3Context) read(filePath string) ([]byte, error) {
 	return content, nil
 }
 
+// open returns the body of the given object, to be read as a stream.
+func (s *S3Context) open(filePath string) (io.ReadCloser, error) {
+	result, err := s.client.GetObject(s.ctx, &s3.GetObjectInput{
+		Bucket:               aws.String(s.bucket),
+		Key:                  aws.String(filePath),
+		SSECustomerAlgorithm: s.objectOptions.customerAlgorithm(),
+		SSECustomerKey:       s.objectOptions.customerKey,
+		SSECustomerKeyMD5:    s.objectOptions.customerKeyMD5,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.Body, nil
+}
+
 // readFile reads and decodes the YAML content from the given filePath into v.
 func (s *S3Context) readFile(filePath string, v any) error {
 	content, err := s.read(filePath)
@@ -157,20 +263,93 @@ func (s *S3Context) writeYaml(filePath string, v any) error {
 }
 
 func (s *S3Context) write(filePath string, data []byte) error {
-	_, err := s.client.PutObject(s.ctx, &s3.PutObjectInput{
-		Bucket: aws.String(s.bucket),
-		Key:    aws.String(filePath),
-		Body:   bytes.NewReader(data),
-	})
+	return s.writeLockedUntil(filePath, data, s.lockedUntil(time.Now()))
+}
+
+func (s *S3Context) writeLockedUntil(filePath string, data []byte, lockedUntil *time.Time) error {
+	_, err := s.client.PutObject(s.ctx, s.putObjectInput(filePath, bytes.NewReader(data), lockedUntil))
 	if err != nil {
 		slog.Warn("Couldn't upload file", "path", filePath,
 			"bucket", s.bucket, "err", err)
 		return err
 	}
-	slog.Debug("File written", "path", filePath, "bucket", s.bucket)
+	s.fileWritten(filePath)
 	return nil
 }
 
+// create uploads the file with a multipart upload, the parts are buffered
+// in memory one at a time.
+func (s *S3Context) create(filePath string) (streamWriter, error) {
+	return newPipeWriter(func(reader io.Reader) error {
+		_, err := s.uploader.Upload(s.ctx, s.putObjectInput(filePath, reader, s.lockedUntil(time.Now())))
+		if err != nil {
+			slog.Warn("Couldn't upload file", "path", filePath,
+				"bucket", s.bucket, "err", err)
+			return err
+		}
+		s.fileWritten(filePath)
+		return nil
+	}), nil
+}
+
+// putObjectInput returns the input to write the object with the object options,
+// locked until the given time.
+func (s *S3Context) putObjectInput(filePath string, body io.Reader, lockedUntil *time.Time) *s3.PutObjectInput {
+	input := &s3.PutObjectInput{
+		Bucket:               aws.String(s.bucket),
+		Key:                  aws.String(filePath),
+		Body:                 body,
+		ServerSideEncryption: s.objectOptions.sse,
+		SSEKMSKeyId:          s.objectOptions.kmsKeyID,
+		SSECustomerAlgorithm: s.objectOptions.customerAlgorithm(),
+		SSECustomerKey:       s.objectOptions.customerKey,
+		SSECustomerKeyMD5:    s.objectOptions.customerKeyMD5,
+		StorageClass:         s.objectOptions.storageClass,
+	}
+	// the state file is overwritten on each backup and the storage check file
+	// is deleted right away, so they are not locked
+	if lockedUntil != nil && lockedUntil.After(time.Now()) &&
+		!slices.Contains([]string{model.StateFileName, storageCheckFile}, filepath.Base(filePath)) {
+		input.ObjectLockMode = s.objectOptions.lockMode
+		input.ObjectLockRetainUntilDate = lockedUntil
+		// a checksum is required to write a locked object
+		input.ChecksumAlgorithm = types.ChecksumAlgorithmCrc32
+	}
+	return input
+}
+
+// fileWritten invalidates the cached metadata of the written file.
+func (s *S3Context) fileWritten(filePath string) {
+	if filepath.Base(filePath) == metadataFile {
+		s.metadataCache.Invalidate(filepath.Dir(filePath))
+	}
+	slog.Debug("File written", "path", filePath, "bucket", s.bucket)
+}
+
+// lockedUntil returns the time the objects written at the given time are locked until,
+// or nil if Object Lock is not configured.
+func (s *S3Context) lockedUntil(now time.Time) *time.Time {
+	if s.objectOptions.lockMode == "" {
+		return nil
+	}
+	lockedUntil := now.AddDate(0, 0, s.objectOptions.lockDays)
+	return &lockedUntil
+}
+
+func (s *S3Context) fileSize(filePath string) (int64, error) {
+	result, err := s.client.HeadObject(s.ctx, &s3.HeadObjectInput{
+		Bucket:               aws.String(s.bucket),
+		Key:                  aws.String(filePath),
+		SSECustomerAlgorithm: s.objectOptions.customerAlgorithm(),
+		SSECustomerKey:       s.objectOptions.customerKey,
+		SSECustomerKeyMD5:    s.objectOptions.customerKeyMD5,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return aws.ToInt64(result.ContentLength), nil
+}
+
 // lsFiles returns all files in the given s3 prefix path.
 func (s *S3Context) lsFiles(prefix string) ([]string, error) {
 	var nextContinuationToken *string
@@ -267,32 +446,111 @@ func (s *S3Context) readMetadata(path string) (*model.BackupMetadata, error) {
 
 func (s *S3Context) DeleteFolder(folder string) error {
 	slog.Debug("Delete folder", "path", folder)
-	result, err := s.client.ListObjectsV2(s.ctx, &s3.ListObjectsV2Input{
-		Bucket: aws.String(s.bucket),
-		Prefix: aws.String(strings.TrimSuffix(folder, "/") + "/"),
-	})
+	files, err := s.lsFiles(folder)
 	if err != nil {
 		slog.Warn("Couldn't list files in directory", "path", folder, "err", err)
 		return err
 	}
 
-	if len(result.Contents) == 0 {
+	if len(files) == 0 {
 		slog.Debug("No files to delete")
 		return nil
 	}
 
-	for _, file := range result.Contents {
-		_, err := s.client.DeleteObject(s.ctx, &s3.DeleteObjectInput{
-			Bucket: aws.String(s.bucket),
-			Key:    file.Key,
+	if s.objectOptions.lockMode != "" {
+		return s.deleteLockedFolder(folder, files)
+	}
+
+	for _, file := range files {
+		if err := s.deleteFile(file); err != nil {
+			slog.Debug("Couldn't delete file", "path", file, "err", err)
+		}
+	}
+	return nil
+}
+
+// deleteLockedFolder deletes all the versions of the objects in the folder of a
+// bucket with Object Lock, as deleting the objects only hides the locked versions.
+// Nothing is deleted if any of the objects is still locked.
+func (s *S3Context) deleteLockedFolder(folder string, files []string) error {
+	now := time.Now()
+	var locked []string
+	var lockedUntil time.Time
+	for _, file := range files {
+		result, err := s.client.HeadObject(s.ctx, &s3.HeadObjectInput{
+			Bucket:               aws.String(s.bucket),
+			Key:                  aws.String(file),
+			SSECustomerAlgorithm: s.objectOptions.customerAlgorithm(),
+			SSECustomerKey:       s.objectOptions.customerKey,
+			SSECustomerKeyMD5:    s.objectOptions.customerKeyMD5,
 		})
 		if err != nil {
-			slog.Debug("Couldn't delete file", "path", *file.Key, "err", err)
+			return err
+		}
+		if result.ObjectLockRetainUntilDate != nil && result.ObjectLockRetainUntilDate.After(now) {
+			locked = append(locked, file)
+			if result.ObjectLockRetainUntilDate.After(lockedUntil) {
+				lockedUntil = *result.ObjectLockRetainUntilDate
+			}
+		}
+	}
+	if len(locked) > 0 {
+		slog.Warn("Locked objects cannot be deleted yet", "path", folder,
+			"lockedUntil", lockedUntil, "objects", locked)
+		return fmt.Errorf("%w: %d objects in %s are locked until %s",
+			ErrBackupLocked, len(locked), folder, lockedUntil.Format(time.RFC3339))
+	}
+
+	paginator := s3.NewListObjectVersionsPaginator(s.client, &s3.ListObjectVersionsInput{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(strings.TrimSuffix(folder, "/") + "/"),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(s.ctx)
+		if err != nil {
+			return err
+		}
+		versions := make([]types.ObjectIdentifier, 0, len(page.Versions)+len(page.DeleteMarkers))
+		for _, version := range page.Versions {
+			versions = append(versions, types.ObjectIdentifier{Key: version.Key, VersionId: version.VersionId})
+		}
+		for _, marker := range page.DeleteMarkers {
+			versions = append(versions, types.ObjectIdentifier{Key: marker.Key, VersionId: marker.VersionId})
+		}
+		for _, version := range versions {
+			_, err := s.client.DeleteObject(s.ctx, &s3.DeleteObjectInput{
+				Bucket:    aws.String(s.bucket),
+				Key:       version.Key,
+				VersionId: version.VersionId,
+			})
+			if err != nil {
+				return fmt.Errorf("couldn't delete version %s of %s: %w",
+					aws.ToString(version.VersionId), aws.ToString(version.Key), err)
+			}
+		}
+	}
+	for _, file := range files {
+		if filepath.Base(file) == metadataFile {
+			s.metadataCache.Invalidate(filepath.Dir(file))
 		}
 	}
 	return nil
 }
 
+func (s *S3Context) deleteFile(path string) error {
+	_, err := s.client.DeleteObject(s.ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(path),
+	})
+	if err != nil {
+		return err
+	}
+	if filepath.Base(path) == metadataFile {
+		s.metadataCache.Invalidate(filepath.Dir(path))
+	}
+	return nil
+}
+
 func (s *S3Context) wrapWithPrefix(path string) *string {
 	result := s3Protocol + s.bucket + "/" + path + "/"
 	return &result
@@ -313,3 +571,40 @@ func (s *S3Context) validateStorageContainsBackup() error {
 	}
 	return fmt.Errorf("no backup files found in %s", s.path)
 }
+
+// stagedS3Context is the accessor of an S3 storage with object options or explicit
+// credentials the shared library does not support: its S3 configuration only has the
+// endpoint, region, profile and log level. The backup files are staged in a local
+// folder and streamed to multipart uploads with the object options and the credentials
+// of the storage once the backup is completed.
+type stagedS3Context struct {
+	*S3Context
+	*localStaging
+}
+
+var _ StorageAccessor = (*stagedS3Context)(nil)
+var _ stagedStorage = (*stagedS3Context)(nil)
+
+func newStagedS3Context(s3Context *S3Context) *stagedS3Context {
+	return &stagedS3Context{
+		S3Context:    s3Context,
+		localStaging: newLocalStaging(s3Context, "s3-"+s3Context.bucket),
+	}
+}
+
+// CreateFolder creates the local staging folder for the given path.
+func (s *stagedS3Context) CreateFolder(path string) {
+	s.createStagingFolder(path)
+}
+
+func (s *stagedS3Context) DeleteFolder(folder string) error {
+	s.removeStaged(folder)
+	return s.S3Context.DeleteFolder(folder)
+}
+
+// wrapWithPrefix returns the local staging folder of the path,
+// which is uploaded to S3 after the backup.
+func (s *stagedS3Context) wrapWithPrefix(path string) *string {
+	result := s.stagingFolder(path)
+	return &result
+}
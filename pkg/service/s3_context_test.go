@@ -3,6 +3,8 @@
 package service
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -127,3 +129,55 @@ func runDeleteFolderTest(t *testing.T, context S3Context) {
 		t.Error("file 2 not deleted")
 	}
 }
+
+func TestS3Context_StagedObjectOptions(t *testing.T) {
+	accessor, _, err := newStorageAccessor(&model.Storage{
+		Type:               model.S3,
+		Path:               ptr.String("s3://as-backup-bucket/storageMinioStaged"),
+		S3Profile:          ptr.String("minio"),
+		S3Region:           ptr.String("eu-central-1"),
+		S3EndpointOverride: ptr.String("http://localhost:9000"),
+		S3StorageClass:     ptr.String("REDUCED_REDUNDANCY"),
+	})
+	if err != nil {
+		t.Skip("minio is not available")
+	}
+	context, ok := accessor.(*stagedS3Context)
+	if !ok {
+		t.Fatalf("Expected staged S3 accessor, got %T", accessor)
+	}
+
+	path := context.path + "/routine/backup/10/data/ns1"
+	context.CreateFolder(path)
+	_ = os.WriteFile(filepath.Join(*context.wrapWithPrefix(path), "ns1_0.asb"), []byte("data"), 0644)
+	backend := &BackupBackend{StorageAccessor: context}
+	if err := backend.completeStaged(path, nil); err != nil {
+		t.Fatal(err)
+	}
+	if size, err := context.fileSize(path + "/ns1_0.asb"); err != nil || size != 4 {
+		t.Errorf("Expected uploaded file of 4 bytes, got %d, %v", size, err)
+	}
+	_ = context.DeleteFolder(path)
+}
+
+func TestNewS3ObjectOptions_CustomerKey(t *testing.T) {
+	t.Setenv("S3_SSE_CUSTOMER_KEY", "MDEyMzQ1Njc4OTAxMjM0NTY3ODkwMTIzNDU2Nzg5MDE=") // 32 bytes
+	storage := &model.Storage{
+		Type:                   model.S3,
+		S3ServerSideEncryption: ptr.String(model.SseC),
+		S3SseCustomerKeyEnv:    ptr.String("S3_SSE_CUSTOMER_KEY"),
+	}
+	options, err := newS3ObjectOptions(storage)
+	if err != nil || options.customerKey == nil || options.customerKeyMD5 == nil {
+		t.Fatalf("Expected customer key, got %v, %v", options, err)
+	}
+
+	t.Setenv("S3_SSE_CUSTOMER_KEY", "a2V5")
+	if _, err := newS3ObjectOptions(storage); err == nil {
+		t.Error("Expected error for a short customer key")
+	}
+	storage.S3SseCustomerKeyEnv = ptr.String("S3_SSE_CUSTOMER_KEY_MISSING")
+	if _, err := newS3ObjectOptions(storage); err == nil {
+		t.Error("Expected error for a missing customer key")
+	}
+}
@@ -0,0 +1,295 @@
+package service
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aerospike/backup/pkg/model"
+	"github.com/aerospike/backup/pkg/util"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeObjectVersion is a version of an object in a fakeS3 bucket.
+type fakeObjectVersion struct {
+	id           string
+	data         []byte
+	deleteMarker bool
+	retainUntil  time.Time
+}
+
+// fakeS3 is a minimal S3 server with versioning and Object Lock, as MinIO serves it.
+// It handles the requests of the S3Context to list, read and delete the objects
+// of a single bucket, with path-style addressing.
+type fakeS3 struct {
+	sync.Mutex
+	bucket   string
+	versions map[string][]fakeObjectVersion
+	next     int
+}
+
+func newFakeS3(t *testing.T, bucket string) (*fakeS3, *S3Context) {
+	t.Helper()
+	fake := &fakeS3{bucket: bucket, versions: make(map[string][]fakeObjectVersion)}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	client := s3.New(s3.Options{
+		BaseEndpoint: aws.String(server.URL),
+		Region:       "us-east-1",
+		Credentials:  aws.AnonymousCredentials{},
+		UsePathStyle: true,
+	})
+	s3Context := &S3Context{
+		ctx:    context.Background(),
+		client: client,
+		bucket: bucket,
+		objectOptions: s3ObjectOptions{
+			lockMode: types.ObjectLockModeGovernance,
+			lockDays: 1,
+		},
+	}
+	s3Context.metadataCache = util.NewLoadingCache(s3Context.ctx, func(path string) (*model.BackupMetadata, error) {
+		return s3Context.readMetadata(path)
+	})
+	return fake, s3Context
+}
+
+// put adds a version of the object, locked until the given time.
+func (f *fakeS3) put(key string, data []byte, retainUntil time.Time) {
+	f.Lock()
+	defer f.Unlock()
+	f.next++
+	f.versions[key] = append(f.versions[key], fakeObjectVersion{
+		id: strconv.Itoa(f.next), data: data, retainUntil: retainUntil,
+	})
+}
+
+// hide adds a delete marker to the object, as a delete without version id does.
+func (f *fakeS3) hide(key string) {
+	f.Lock()
+	defer f.Unlock()
+	f.next++
+	f.versions[key] = append(f.versions[key], fakeObjectVersion{id: strconv.Itoa(f.next), deleteMarker: true})
+}
+
+// versionCount returns the number of versions and delete markers under the prefix.
+func (f *fakeS3) versionCount(prefix string) int {
+	f.Lock()
+	defer f.Unlock()
+	var count int
+	for key, versions := range f.versions {
+		if strings.HasPrefix(key, prefix) {
+			count += len(versions)
+		}
+	}
+	return count
+}
+
+// latest returns the current version of the object, if it is not deleted.
+func (f *fakeS3) latest(key string) (fakeObjectVersion, bool) {
+	versions := f.versions[key]
+	if len(versions) == 0 || versions[len(versions)-1].deleteMarker {
+		return fakeObjectVersion{}, false
+	}
+	return versions[len(versions)-1], true
+}
+
+func (f *fakeS3) sortedKeys(prefix string) []string {
+	var keys []string
+	for key := range f.versions {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.Lock()
+	defer f.Unlock()
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucket != f.bucket {
+		writeFakeS3Error(w, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+	query := r.URL.Query()
+	switch {
+	case key == "" && r.Method == http.MethodGet && query.Has("versions"):
+		f.listVersions(w, query.Get("prefix"))
+	case key == "" && r.Method == http.MethodGet:
+		f.listObjects(w, query.Get("prefix"), query.Get("delimiter"))
+	case r.Method == http.MethodHead || r.Method == http.MethodGet:
+		version, found := f.latest(key)
+		if !found {
+			writeFakeS3Error(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		w.Header().Set("x-amz-version-id", version.id)
+		w.Header().Set("Content-Length", strconv.Itoa(len(version.data)))
+		if !version.retainUntil.IsZero() {
+			w.Header().Set("x-amz-object-lock-mode", string(types.ObjectLockModeGovernance))
+			w.Header().Set("x-amz-object-lock-retain-until-date", version.retainUntil.UTC().Format(time.RFC3339))
+		}
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(version.data)
+		}
+	case r.Method == http.MethodDelete && query.Has("versionId"):
+		versions := f.versions[key]
+		index := slices.IndexFunc(versions, func(version fakeObjectVersion) bool {
+			return version.id == query.Get("versionId")
+		})
+		if index >= 0 && versions[index].retainUntil.After(time.Now()) {
+			writeFakeS3Error(w, http.StatusForbidden, "AccessDenied")
+			return
+		}
+		if index >= 0 {
+			f.versions[key] = slices.Delete(versions, index, index+1)
+			if len(f.versions[key]) == 0 {
+				delete(f.versions, key)
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodDelete:
+		f.next++
+		f.versions[key] = append(f.versions[key], fakeObjectVersion{id: strconv.Itoa(f.next), deleteMarker: true})
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeFakeS3Error(w, http.StatusNotImplemented, "NotImplemented")
+	}
+}
+
+func (f *fakeS3) listObjects(w http.ResponseWriter, prefix, delimiter string) {
+	type content struct {
+		Key  string
+		Size int
+	}
+	type commonPrefix struct {
+		Prefix string
+	}
+	result := struct {
+		XMLName        xml.Name `xml:"ListBucketResult"`
+		Name           string
+		Prefix         string
+		KeyCount       int
+		IsTruncated    bool
+		Contents       []content
+		CommonPrefixes []commonPrefix
+	}{Name: f.bucket, Prefix: prefix}
+	for _, key := range f.sortedKeys(prefix) {
+		version, found := f.latest(key)
+		if !found {
+			continue
+		}
+		if delimiter != "" {
+			if before, _, cut := strings.Cut(strings.TrimPrefix(key, prefix), delimiter); cut {
+				folder := commonPrefix{Prefix: prefix + before + delimiter}
+				if !slices.Contains(result.CommonPrefixes, folder) {
+					result.CommonPrefixes = append(result.CommonPrefixes, folder)
+				}
+				continue
+			}
+		}
+		result.Contents = append(result.Contents, content{Key: key, Size: len(version.data)})
+	}
+	result.KeyCount = len(result.Contents) + len(result.CommonPrefixes)
+	writeFakeS3Result(w, result)
+}
+
+func (f *fakeS3) listVersions(w http.ResponseWriter, prefix string) {
+	type version struct {
+		Key       string
+		VersionId string
+		IsLatest  bool
+	}
+	result := struct {
+		XMLName      xml.Name `xml:"ListVersionsResult"`
+		Name         string
+		Prefix       string
+		IsTruncated  bool
+		Version      []version
+		DeleteMarker []version
+	}{Name: f.bucket, Prefix: prefix}
+	for _, key := range f.sortedKeys(prefix) {
+		versions := f.versions[key]
+		for i, objectVersion := range versions {
+			listed := version{Key: key, VersionId: objectVersion.id, IsLatest: i == len(versions)-1}
+			if objectVersion.deleteMarker {
+				result.DeleteMarker = append(result.DeleteMarker, listed)
+			} else {
+				result.Version = append(result.Version, listed)
+			}
+		}
+	}
+	writeFakeS3Result(w, result)
+}
+
+func writeFakeS3Result(w http.ResponseWriter, result any) {
+	w.Header().Set("Content-Type", "application/xml")
+	_ = xml.NewEncoder(w).Encode(result)
+}
+
+func writeFakeS3Error(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	_ = xml.NewEncoder(w).Encode(struct {
+		XMLName xml.Name `xml:"Error"`
+		Code    string
+	}{Code: code})
+}
+
+func TestS3Context_DeleteLockedFolder(t *testing.T) {
+	fake, s3Context := newFakeS3(t, "bucket")
+	expired := time.Now().Add(-time.Hour)
+	locked := time.Now().Add(time.Hour)
+	fake.put("routine/incremental/1000/data/ns1/ns1_0.asb", []byte("old"), expired)
+	fake.put("routine/incremental/1000/data/ns1/ns1_0.asb", []byte("data"), expired)
+	fake.put("routine/incremental/1000/data/ns1/ns1_1.asb", []byte("data"), expired)
+	fake.put("routine/incremental/1000/data/ns1/ns1_2.asb", []byte("data"), expired)
+	fake.hide("routine/incremental/1000/data/ns1/ns1_2.asb")
+	fake.put("routine/incremental/2000/data/ns1/ns1_0.asb", []byte("data"), locked)
+	fake.put("routine/incremental/2000/data/ns1/ns1_1.asb", []byte("data"), expired)
+
+	// all the versions and the delete markers of the expired objects are deleted
+	require.NoError(t, s3Context.DeleteFolder("routine/incremental/1000"))
+	assert.Zero(t, fake.versionCount("routine/incremental/1000/"))
+
+	// nothing is deleted while an object of the folder is locked
+	err := s3Context.DeleteFolder("routine/incremental/2000")
+	assert.ErrorIs(t, err, ErrBackupLocked)
+	assert.Equal(t, 2, fake.versionCount("routine/incremental/2000/"))
+}
+
+func TestCleanIncrementalBackups_Locked(t *testing.T) {
+	fake, s3Context := newFakeS3(t, "bucket")
+	expired := time.Now().Add(-time.Hour)
+	fake.put("routine/incremental/1000/data/ns1/ns1_0.asb", []byte("data"), expired)
+	fake.put("routine/incremental/2000/data/ns1/ns1_0.asb", []byte("data"), time.Now().Add(time.Hour))
+	fake.put("routine/incremental/3000/data/ns1/ns1_0.asb", []byte("data"), expired)
+	handler := &BackupHandler{
+		backend: &BackupBackend{
+			StorageAccessor:        s3Context,
+			incrementalBackupsPath: "routine/incremental",
+		},
+		backupIncrPolicy: &model.BackupPolicy{RemoveFiles: util.Ptr(model.RemoveIncremental)},
+		routineName:      "routine",
+	}
+
+	handler.cleanIncrementalBackups()
+
+	// the locked backup does not prevent the deletion of the others
+	assert.Zero(t, fake.versionCount("routine/incremental/1000/"))
+	assert.Equal(t, 1, fake.versionCount("routine/incremental/2000/"))
+	assert.Zero(t, fake.versionCount("routine/incremental/3000/"))
+}
@@ -0,0 +1,414 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aerospike/backup/pkg/model"
+	"github.com/aerospike/backup/pkg/shared"
+	"github.com/aerospike/backup/pkg/util"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	sftpProtocol    = "sftp://"
+	sftpDialTimeout = 10 * time.Second
+)
+
+// SftpContext is responsible for performing basic operations on an SFTP host.
+// The shared library cannot write to SFTP directly, so the backup files are
+// staged in a local folder and uploaded once the backup is completed.
+// The paths are relative to the root of the host file system.
+type SftpContext struct {
+	*localStaging
+	address       string
+	host          string
+	sshConfig     *ssh.ClientConfig
+	path          string
+	clientMutex   sync.Mutex
+	client        *sftp.Client
+	metadataCache *util.LoadingCache[string, *model.BackupMetadata]
+}
+
+var _ StorageAccessor = (*SftpContext)(nil)
+var _ stagedStorage = (*SftpContext)(nil)
+
+// NewSftpContext returns a new SftpContext.
+func NewSftpContext(config *model.Storage) (*SftpContext, error) {
+	sshConfig, err := newSSHClientConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load SFTP configuration: %v", err)
+	}
+
+	s := &SftpContext{
+		address:   net.JoinHostPort(*config.SftpHost, strconv.Itoa(config.GetSftpPortOrDefault())),
+		host:      *config.SftpHost,
+		sshConfig: sshConfig,
+		path:      strings.Trim(*config.Path, "/"),
+	}
+	// Check if the host is reachable
+	if _, err := s.sftpClient(); err != nil {
+		return nil, fmt.Errorf("error connecting to SFTP host %s: %v", s.address, err)
+	}
+	s.localStaging = newLocalStaging(s, "sftp-"+s.host)
+	s.metadataCache = util.NewLoadingCache(context.TODO(), func(path string) (*model.BackupMetadata, error) {
+		return s.readMetadata(path)
+	})
+	return s, nil
+}
+
+func newSSHClientConfig(config *model.Storage) (*ssh.ClientConfig, error) {
+	hostKeyCallback, err := knownhosts.New(*config.SftpKnownHostsFile)
+	if err != nil {
+		return nil, err
+	}
+	var auth []ssh.AuthMethod
+	if config.SftpKeyFile != nil {
+		key, err := os.ReadFile(*config.SftpKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		signer, err := ssh.ParsePrivateKey(key)
+		if err != nil {
+			return nil, err
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+	if config.SftpPasswordEnv != nil || config.SftpPasswordFile != nil {
+		password, err := shared.ReadSecret(config.SftpPasswordEnv, config.SftpPasswordFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read sftp password: %w", err)
+		}
+		auth = append(auth, ssh.Password(password))
+	}
+	return &ssh.ClientConfig{
+		User:            *config.SftpUser,
+		Auth:            auth,
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         sftpDialTimeout,
+	}, nil
+}
+
+// sftpClient returns the connected client, connecting if required.
+func (s *SftpContext) sftpClient() (*sftp.Client, error) {
+	s.clientMutex.Lock()
+	defer s.clientMutex.Unlock()
+	if s.client != nil {
+		return s.client, nil
+	}
+	conn, err := ssh.Dial("tcp", s.address, s.sshConfig)
+	if err != nil {
+		return nil, err
+	}
+	client, err := sftp.NewClient(conn)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	s.client = client
+	return client, nil
+}
+
+// withClient runs the operation with the connected client.
+// The client is dropped if the connection is lost, the next operation reconnects.
+func (s *SftpContext) withClient(operation func(client *sftp.Client) error) error {
+	client, err := s.sftpClient()
+	if err != nil {
+		return err
+	}
+	err = operation(client)
+	if errors.Is(err, sftp.ErrSSHFxConnectionLost) {
+		s.clientMutex.Lock()
+		if s.client == client {
+			_ = client.Close()
+			s.client = nil
+		}
+		s.clientMutex.Unlock()
+	}
+	return err
+}
+
+// remotePath returns the absolute path on the host.
+func (s *SftpContext) remotePath(path string) string {
+	return filepath.Join("/", path)
+}
+
+func (s *SftpContext) readBackupState(stateFilePath string, state *model.BackupState) error {
+	err := s.readFile(stateFilePath, state)
+	if errors.Is(err, os.ErrNotExist) {
+		slog.Debug("State file does not exist for backup", "path", stateFilePath)
+		return nil
+	}
+	return err
+}
+
+func (s *SftpContext) readBackupDetails(path string, useCache bool) (model.BackupDetails, error) {
+	var metadata *model.BackupMetadata
+	var err error
+	if useCache {
+		metadata, err = s.metadataCache.Get(path)
+	} else {
+		metadata, err = s.readMetadata(path)
+	}
+	if err != nil {
+		return model.BackupDetails{}, err
+	}
+	return model.BackupDetails{
+		BackupMetadata: *metadata,
+		Key:            util.Ptr(sftpProtocol + s.host + s.remotePath(path)),
+	}, nil
+}
+
+func (s *SftpContext) read(filePath string) ([]byte, error) {
+	var content []byte
+	err := s.withClient(func(client *sftp.Client) error {
+		file, err := client.Open(s.remotePath(filePath))
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		content, err = io.ReadAll(file)
+		return err
+	})
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			slog.Warn("Failed to read file", "path", filePath, "err", err)
+		}
+		return nil, err
+	}
+	return content, nil
+}
+
+func (s *SftpContext) open(filePath string) (io.ReadCloser, error) {
+	var file *sftp.File
+	err := s.withClient(func(client *sftp.Client) error {
+		var err error
+		file, err = client.Open(s.remotePath(filePath))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// create uploads the file, the partial file is removed if the writer is closed with an error.
+func (s *SftpContext) create(filePath string) (streamWriter, error) {
+	return newPipeWriter(func(reader io.Reader) error {
+		err := s.withClient(func(client *sftp.Client) error {
+			remotePath := s.remotePath(filePath)
+			if err := client.MkdirAll(filepath.Dir(remotePath)); err != nil {
+				return err
+			}
+			file, err := client.Create(remotePath)
+			if err != nil {
+				return err
+			}
+			_, err = file.ReadFrom(reader)
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				_ = client.Remove(remotePath)
+			}
+			return err
+		})
+		if err != nil {
+			slog.Warn("Couldn't upload file", "path", filePath, "host", s.host, "err", err)
+			return err
+		}
+		s.fileWritten(filePath)
+		return nil
+	}), nil
+}
+
+// readFile reads and decodes the YAML content from the given filePath into v.
+func (s *SftpContext) readFile(filePath string, v any) error {
+	content, err := s.read(filePath)
+	if err != nil {
+		return err
+	}
+	if err = yaml.Unmarshal(content, v); err != nil {
+		slog.Warn("Failed unmarshal file", "path", filePath, "err", err,
+			"content", string(content))
+		return err
+	}
+	return nil
+}
+
+func (s *SftpContext) write(filePath string, data []byte) error {
+	err := s.withClient(func(client *sftp.Client) error {
+		remotePath := s.remotePath(filePath)
+		if err := client.MkdirAll(filepath.Dir(remotePath)); err != nil {
+			return err
+		}
+		file, err := client.Create(remotePath)
+		if err != nil {
+			return err
+		}
+		if _, err := file.Write(data); err != nil {
+			_ = file.Close()
+			return err
+		}
+		return file.Close()
+	})
+	if err != nil {
+		slog.Warn("Couldn't upload file", "path", filePath, "host", s.host, "err", err)
+		return err
+	}
+	s.fileWritten(filePath)
+	return nil
+}
+
+// fileWritten invalidates the cached metadata of the written file.
+func (s *SftpContext) fileWritten(filePath string) {
+	if filepath.Base(filePath) == metadataFile {
+		s.metadataCache.Invalidate(filepath.Dir(filePath))
+	}
+	slog.Debug("File written", "path", filePath, "host", s.host)
+}
+
+func (s *SftpContext) fileSize(filePath string) (int64, error) {
+	var size int64
+	err := s.withClient(func(client *sftp.Client) error {
+		info, err := client.Stat(s.remotePath(filePath))
+		if err != nil {
+			return err
+		}
+		size = info.Size()
+		return nil
+	})
+	return size, err
+}
+
+// lsFiles returns all files in the given path, recursively.
+func (s *SftpContext) lsFiles(path string) ([]string, error) {
+	result := make([]string, 0)
+	err := s.withClient(func(client *sftp.Client) error {
+		walker := client.Walk(s.remotePath(path))
+		for walker.Step() {
+			if err := walker.Err(); err != nil {
+				if errors.Is(err, os.ErrNotExist) {
+					continue
+				}
+				return err
+			}
+			if !walker.Stat().IsDir() {
+				result = append(result, strings.TrimPrefix(walker.Path(), "/"))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		slog.Warn("Couldn't list files in folder", "path", path, "err", err)
+		return nil, err
+	}
+	return result, nil
+}
+
+// lsDir returns all subfolders in the given path.
+func (s *SftpContext) lsDir(path string) ([]string, error) {
+	result := make([]string, 0)
+	err := s.withClient(func(client *sftp.Client) error {
+		entries, err := client.ReadDir(s.remotePath(path))
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		for _, entry := range entries {
+			if entry.IsDir() {
+				result = append(result, filepath.Join(path, entry.Name()))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		slog.Warn("Couldn't list folder", "path", path, "err", err)
+		return nil, err
+	}
+	return result, nil
+}
+
+// CreateFolder creates the local staging folder for the given path.
+// The folders on the host are created on upload.
+func (s *SftpContext) CreateFolder(path string) {
+	s.createStagingFolder(path)
+}
+
+func (s *SftpContext) readMetadata(path string) (*model.BackupMetadata, error) {
+	metadata := &model.BackupMetadata{}
+	metadataFilePath := filepath.Join(path, metadataFile)
+	err := s.readFile(metadataFilePath, metadata)
+	if err != nil {
+		return nil, err
+	}
+	slog.Debug("Read metadata file", "path", path, "data", metadata)
+	return metadata, nil
+}
+
+// DeleteFolder removes the folder and invalidates the cached metadata under it.
+func (s *SftpContext) DeleteFolder(folder string) error {
+	slog.Debug("Delete folder", "path", folder)
+	s.removeStaged(folder)
+	s.metadataCache.InvalidateIf(func(path string) bool {
+		return path == folder || strings.HasPrefix(path, folder+"/")
+	})
+	return s.withClient(func(client *sftp.Client) error {
+		err := client.RemoveAll(s.remotePath(folder))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	})
+}
+
+func (s *SftpContext) deleteFile(path string) error {
+	err := s.withClient(func(client *sftp.Client) error {
+		return client.Remove(s.remotePath(path))
+	})
+	if err != nil {
+		return err
+	}
+	if filepath.Base(path) == metadataFile {
+		s.metadataCache.Invalidate(filepath.Dir(path))
+	}
+	return nil
+}
+
+// wrapWithPrefix returns the local staging folder of the path,
+// which is uploaded to the SFTP host after the backup.
+func (s *SftpContext) wrapWithPrefix(path string) *string {
+	result := s.stagingFolder(path)
+	return &result
+}
+
+func (s *SftpContext) validateStorageContainsBackup() error {
+	files, err := s.lsFiles(s.path)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("given path %s not exist", s.path)
+	}
+	for _, file := range files {
+		if strings.HasSuffix(file, ".asb") {
+			return nil
+		}
+	}
+	return fmt.Errorf("no backup files found in %s", s.path)
+}
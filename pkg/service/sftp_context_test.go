@@ -0,0 +1,203 @@
+package service
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aerospike/backup/pkg/model"
+	"github.com/aerospike/backup/pkg/util"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	sftpTestUser     = "backup"
+	sftpTestPassword = "secret"
+)
+
+// startSftpServer starts an in-process SFTP server serving the local file system
+// and returns the storage configuration to connect to it.
+func startSftpServer(t *testing.T) *model.Storage {
+	t.Helper()
+	t.Setenv("SFTP_TEST_PASSWORD", sftpTestPassword)
+	_, hostKey, _ := ed25519.GenerateKey(rand.Reader)
+	signer, err := ssh.NewSignerFromKey(hostKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(conn ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			if conn.User() == sftpTestUser && string(password) == sftpTestPassword {
+				return nil, nil
+			}
+			return nil, errors.New("access denied")
+		},
+	}
+	config.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveSftp(conn, config)
+		}
+	}()
+
+	address := listener.Addr().(*net.TCPAddr)
+	knownHostsFile := filepath.Join(t.TempDir(), "known_hosts")
+	line := knownhosts.Line([]string{knownhosts.Normalize(address.String())}, signer.PublicKey())
+	if err := os.WriteFile(knownHostsFile, []byte(line+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return &model.Storage{
+		Type:               model.SFTP,
+		Path:               util.Ptr(t.TempDir()),
+		SftpHost:           util.Ptr(address.IP.String()),
+		SftpPort:           util.Ptr(address.Port),
+		SftpUser:           util.Ptr(sftpTestUser),
+		SftpPasswordEnv:    util.Ptr("SFTP_TEST_PASSWORD"),
+		SftpKnownHostsFile: util.Ptr(knownHostsFile),
+	}
+}
+
+func serveSftp(conn net.Conn, config *ssh.ServerConfig) {
+	_, channels, requests, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(requests)
+	for newChannel := range channels {
+		if newChannel.ChannelType() != "session" {
+			_ = newChannel.Reject(ssh.UnknownChannelType, "unknown channel type")
+			continue
+		}
+		channel, channelRequests, err := newChannel.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			for request := range channelRequests {
+				isSftp := request.Type == "subsystem" && len(request.Payload) > 4 && string(request.Payload[4:]) == "sftp"
+				_ = request.Reply(isSftp, nil)
+			}
+		}()
+		server, err := sftp.NewServer(channel)
+		if err != nil {
+			return
+		}
+		go func() {
+			_ = server.Serve()
+			_ = server.Close()
+		}()
+	}
+}
+
+func TestSftpContext(t *testing.T) {
+	storage := startSftpServer(t)
+	sftpContext, err := NewSftpContext(storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(sftpContext.path, "routine", "incremental", "10", "data", "ns1")
+	metadata, _ := yaml.Marshal(model.BackupMetadata{Namespace: "ns1", Created: time.UnixMilli(10)})
+	if err := sftpContext.write(filepath.Join(path, metadataFile), metadata); err != nil {
+		t.Fatal(err)
+	}
+	details, err := sftpContext.readBackupDetails(path, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if details.Namespace != "ns1" || *details.Key != "sftp://127.0.0.1/"+path {
+		t.Errorf("Unexpected backup details %v, key %s", details.BackupMetadata, *details.Key)
+	}
+	dirs, _ := sftpContext.lsDir(filepath.Join(sftpContext.path, "routine", "incremental"))
+	if len(dirs) != 1 || dirs[0] != filepath.Join(sftpContext.path, "routine", "incremental", "10") {
+		t.Errorf("Unexpected subfolders %v", dirs)
+	}
+	state := model.NewBackupState()
+	if err := sftpContext.readBackupState(filepath.Join(sftpContext.path, model.StateFileName), state); err != nil {
+		t.Errorf("Expected no error for missing state, got %v", err)
+	}
+
+	// the cached metadata of the nested backup folder is invalidated
+	if err := sftpContext.DeleteFolder(filepath.Dir(path)); err != nil {
+		t.Fatal(err)
+	}
+	if files, _ := sftpContext.lsFiles(path); len(files) != 0 {
+		t.Errorf("Expected files deleted, got %v", files)
+	}
+	if _, err := sftpContext.readBackupDetails(path, true); err == nil {
+		t.Error("Expected no cached details for the deleted backup")
+	}
+}
+
+func TestSftpContext_UploadDownload(t *testing.T) {
+	storage := startSftpServer(t)
+	sftpContext, err := NewSftpContext(storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(sftpContext.path, "routine", "backup", strconv.Itoa(10), "data", "ns1")
+	sftpContext.CreateFolder(path)
+	stagingFolder := *sftpContext.wrapWithPrefix(path)
+	_ = os.WriteFile(filepath.Join(stagingFolder, "ns1_0.asb"), []byte("data"), 0644)
+	backend := &BackupBackend{StorageAccessor: sftpContext}
+	if err := backend.completeStaged(path, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(stagingFolder); !os.IsNotExist(err) {
+		t.Errorf("Expected staging folder removed, got %v", err)
+	}
+	if err := sftpContext.validateStorageContainsBackup(); err != nil {
+		t.Errorf("Expected backup found, got %v", err)
+	}
+
+	localDir := t.TempDir()
+	if err := sftpContext.download(path, localDir); err != nil {
+		t.Fatal(err)
+	}
+	content, _ := os.ReadFile(filepath.Join(localDir, "ns1_0.asb"))
+	if string(content) != "data" {
+		t.Errorf("Expected downloaded content, got %s", content)
+	}
+
+	request := &model.RestoreRequestInternal{
+		RestoreRequest: model.RestoreRequest{SourceStorage: storage},
+		Dir:            util.Ptr("sftp://127.0.0.1/" + path),
+	}
+	cleanup, err := stageBackupFiles(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(*request.Dir, "ns1_0.asb")); err != nil {
+		t.Errorf("Expected backup files staged for restore, got %v", err)
+	}
+	cleanup()
+}
+
+func TestSftpContext_UnknownHost(t *testing.T) {
+	storage := startSftpServer(t)
+	storage.SftpKnownHostsFile = util.Ptr(filepath.Join(t.TempDir(), "known_hosts"))
+	_ = os.WriteFile(*storage.SftpKnownHostsFile, nil, 0600)
+	if _, err := NewSftpContext(storage); err == nil {
+		t.Error("Expected error for unknown host key")
+	}
+}
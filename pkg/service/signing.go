@@ -0,0 +1,207 @@
+package service
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/aerospike/backup/pkg/model"
+)
+
+// ErrBackupSignature is returned on an attempt to restore an unsigned backup or a
+// backup with invalid signature, when the signatures are required.
+var ErrBackupSignature = errors.New("backup signature is missing or invalid")
+
+// ErrSigningKeys is returned when the configured signing keys cannot be loaded.
+var ErrSigningKeys = errors.New("cannot load backup signing keys")
+
+// backupSigner signs the backups with the active Ed25519 key, and verifies the
+// signatures with the public key of the key ID recorded in the backup metadata.
+type backupSigner struct {
+	keyID      string
+	privateKey ed25519.PrivateKey
+	publicKeys map[string]ed25519.PublicKey
+	required   bool // whether to refuse to restore the backups not validly signed
+}
+
+// newBackupSigner loads the configured signing keys.
+// It returns nil if the backup signing is not configured.
+func newBackupSigner(config *model.SigningConfig) (*backupSigner, error) {
+	if config == nil {
+		return nil, nil
+	}
+	signer := &backupSigner{
+		keyID:      config.KeyID,
+		publicKeys: make(map[string]ed25519.PublicKey, len(config.Keys)),
+		required:   config.IsSignatureRequired(),
+	}
+	for keyID, key := range config.Keys {
+		var privateKey ed25519.PrivateKey
+		if key.PrivateKeyFile != nil {
+			var err error
+			privateKey, err = readPrivateKey(*key.PrivateKeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("cannot read private key %s: %w", keyID, err)
+			}
+			signer.publicKeys[keyID] = privateKey.Public().(ed25519.PublicKey)
+		}
+		if key.PublicKeyFile != nil {
+			publicKey, err := readPublicKey(*key.PublicKeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("cannot read public key %s: %w", keyID, err)
+			}
+			if privateKey != nil && !publicKey.Equal(signer.publicKeys[keyID]) {
+				return nil, fmt.Errorf("public key %s does not match the private key", keyID)
+			}
+			signer.publicKeys[keyID] = publicKey
+		}
+		if keyID == config.KeyID {
+			signer.privateKey = privateKey
+		}
+	}
+	if signer.privateKey == nil {
+		return nil, fmt.Errorf("private key of the active signing key %s not found", config.KeyID)
+	}
+	return signer, nil
+}
+
+// loadSigner loads the configured signing keys once for all the routines.
+// The backups would be left unsigned, so the configuration is not applied if the
+// keys cannot be read.
+func loadSigner(config *model.Config) (*backupSigner, error) {
+	if config.ServiceConfig == nil {
+		return nil, nil
+	}
+	signer, err := newBackupSigner(config.ServiceConfig.Signing)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSigningKeys, err)
+	}
+	return signer, nil
+}
+
+func readPrivateKey(path string) (ed25519.PrivateKey, error) {
+	der, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+	privateKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an Ed25519 private key", path)
+	}
+	return privateKey, nil
+}
+
+func readPublicKey(path string) (ed25519.PublicKey, error) {
+	der, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, err
+	}
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an Ed25519 public key", path)
+	}
+	return publicKey, nil
+}
+
+func readPEM(path string) ([]byte, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found in %s", path)
+	}
+	return block.Bytes, nil
+}
+
+// sign records the active key ID and the signature of the metadata and the manifest
+// in the metadata.
+func (s *backupSigner) sign(metadata *model.BackupMetadata, manifest *model.BackupManifest) error {
+	metadata.KeyID = s.keyID
+	payload, err := signedPayload(*metadata, manifest)
+	if err != nil {
+		return err
+	}
+	metadata.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(s.privateKey, payload))
+	return nil
+}
+
+// verify returns true if the backup is signed with a known key, and the signature
+// matches the metadata and the manifest.
+func (s *backupSigner) verify(metadata model.BackupMetadata, manifest *model.BackupManifest) bool {
+	publicKey, found := s.publicKeys[metadata.KeyID]
+	if !found || metadata.Signature == "" {
+		return false
+	}
+	signature, err := base64.StdEncoding.DecodeString(metadata.Signature)
+	if err != nil {
+		return false
+	}
+	payload, err := signedPayload(metadata, manifest)
+	if err != nil {
+		return false
+	}
+	return ed25519.Verify(publicKey, payload, signature)
+}
+
+// isRequired returns true if the backups not validly signed should not be restored.
+func (s *backupSigner) isRequired() bool {
+	return s != nil && s.required
+}
+
+// signedPayload returns the signed content of the backup: the metadata, except
+// for the fields updated after the backup is created, and the manifest.
+func signedPayload(metadata model.BackupMetadata, manifest *model.BackupManifest) ([]byte, error) {
+	metadata.Hold = nil
+	metadata.HoldHistory = nil
+	metadata.Replication = nil
+	metadata.LockedUntil = nil
+	metadata.Signature = ""
+	// the time zone is not preserved by all the storages
+	metadata.Created = metadata.Created.UTC()
+	metadata.From = metadata.From.UTC()
+	return json.Marshal(struct {
+		Metadata model.BackupMetadata  `json:"metadata"`
+		Manifest *model.BackupManifest `json:"manifest,omitempty"`
+	}{metadata, manifest})
+}
+
+// signBackup signs the metadata and the manifest of the backup at the given path,
+// if the backup signing is configured.
+func (b *BackupBackend) signBackup(path string, metadata *model.BackupMetadata) error {
+	if b.signer == nil {
+		return nil
+	}
+	// the backup is signed without manifest if it could not be written
+	manifest, err := b.readManifest(path)
+	if err != nil {
+		slog.Debug("Signing backup without manifest", "path", path, "err", err)
+		manifest = nil
+	}
+	return b.signer.sign(metadata, manifest)
+}
+
+// signatureValid returns whether the signature of the backup is valid, or nil
+// if the backup signing is not configured.
+func (b *BackupBackend) signatureValid(details model.BackupDetails) *bool {
+	if b.signer == nil {
+		return nil
+	}
+	valid := b.signer.verify(details.BackupMetadata, details.Manifest)
+	return &valid
+}
@@ -0,0 +1,285 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aerospike/backup/pkg/model"
+	"github.com/aerospike/backup/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeSigningKey writes a new Ed25519 key pair to PEM files in the folder.
+func writeSigningKey(t *testing.T, folder, keyID string) *model.SigningKey {
+	t.Helper()
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	privateDER, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	require.NoError(t, err)
+	publicDER, err := x509.MarshalPKIXPublicKey(publicKey)
+	require.NoError(t, err)
+
+	key := &model.SigningKey{
+		PrivateKeyFile: util.Ptr(filepath.Join(folder, keyID+".pem")),
+		PublicKeyFile:  util.Ptr(filepath.Join(folder, keyID+".pub")),
+	}
+	require.NoError(t, os.WriteFile(*key.PrivateKeyFile,
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}), 0o600))
+	require.NoError(t, os.WriteFile(*key.PublicKeyFile,
+		pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), 0o600))
+	return key
+}
+
+// signingConfig returns the signing configuration with new keys of the given IDs,
+// the first one active.
+func signingConfig(t *testing.T, keyIDs ...string) *model.SigningConfig {
+	t.Helper()
+	folder := t.TempDir()
+	config := &model.SigningConfig{KeyID: keyIDs[0], Keys: map[string]*model.SigningKey{}}
+	for _, keyID := range keyIDs {
+		config.Keys[keyID] = writeSigningKey(t, folder, keyID)
+	}
+	return config
+}
+
+func TestNewBackupSigner(t *testing.T) {
+	signer, err := newBackupSigner(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, signer)
+
+	config := signingConfig(t, "new", "old")
+	// only the public key of the rotated out key is needed
+	config.Keys["old"].PrivateKeyFile = nil
+	// the public key of the active key is derived from the private one
+	config.Keys["new"].PublicKeyFile = nil
+	signer, err = newBackupSigner(config)
+	require.NoError(t, err)
+	assert.Equal(t, "new", signer.keyID)
+	assert.Len(t, signer.publicKeys, 2)
+
+	config.KeyID = "old"
+	_, err = newBackupSigner(config)
+	assert.ErrorContains(t, err, "private key of the active signing key old not found")
+
+	config = signingConfig(t, "new", "old")
+	config.Keys["new"].PublicKeyFile = config.Keys["old"].PublicKeyFile
+	_, err = newBackupSigner(config)
+	assert.ErrorContains(t, err, "does not match the private key")
+
+	config.Keys["new"].PublicKeyFile = config.Keys["new"].PrivateKeyFile
+	_, err = newBackupSigner(config)
+	assert.Error(t, err)
+}
+
+func TestBackupSigner_SignAndVerify(t *testing.T) {
+	signer, err := newBackupSigner(signingConfig(t, "key"))
+	require.NoError(t, err)
+	metadata := model.BackupMetadata{Created: time.UnixMilli(1000), Namespace: "ns1", RecordCount: 10}
+	manifest := &model.BackupManifest{Algorithm: model.ManifestAlgorithm, Files: []model.ManifestFile{
+		{Path: "data/ns1/ns1_0.asb", Size: 10, Checksum: "abc"},
+	}}
+
+	require.NoError(t, signer.sign(&metadata, manifest))
+	assert.Equal(t, "key", metadata.KeyID)
+	assert.True(t, signer.verify(metadata, manifest))
+
+	// the mutable fields are not signed
+	updated := metadata
+	updated.Hold = &model.BackupHold{Reason: "audit"}
+	updated.LockedUntil = util.Ptr(time.UnixMilli(2000))
+	updated.Replication = []model.ReplicationStatus{{Storage: "secondary"}}
+	updated.Created = updated.Created.In(time.FixedZone("UTC+2", 2*60*60))
+	assert.True(t, signer.verify(updated, manifest))
+
+	tampered := metadata
+	tampered.RecordCount++
+	assert.False(t, signer.verify(tampered, manifest))
+	assert.False(t, signer.verify(metadata, nil))
+	tamperedManifest := *manifest
+	tamperedManifest.Files = []model.ManifestFile{{Path: "data/ns1/ns1_0.asb", Size: 10, Checksum: "abd"}}
+	assert.False(t, signer.verify(metadata, &tamperedManifest))
+	unknownKey := metadata
+	unknownKey.KeyID = "unknown"
+	assert.False(t, signer.verify(unknownKey, manifest))
+	assert.False(t, signer.verify(model.BackupMetadata{Created: time.UnixMilli(1000)}, nil))
+}
+
+// signedBackups runs a full backup at 1000 and an incremental backup at 2000 of the
+// namespaces ns1 and ns2, signed with the given configuration.
+func signedBackups(t *testing.T, signing *model.SigningConfig) (*memoryAccessor, *RestoreMemory) {
+	t.Helper()
+	accessor := newMemoryAccessor()
+	useFakeBackup(t, &fakeBackup{accessor: accessor, records: 100, incrementalRecords: 10})
+	config := flowConfig()
+	config.ServiceConfig.Signing = signing
+	backends := flowBackends(accessor)
+	backend, _ := backends.Get("routine")
+	signer, err := loadSigner(config)
+	require.NoError(t, err)
+	backend.signer = signer
+	handler, err := newBackupHandler(config, "routine", backend, backends)
+	require.NoError(t, err)
+	handler.runFullBackup(time.UnixMilli(1000))
+	handler.runIncrementalBackup(time.UnixMilli(2000))
+
+	restore := newRestoreMemory(backends, config, signer)
+	restore.restoreService = &fakeRestore{accessor: accessor}
+	return accessor, restore
+}
+
+func signaturesValid(t *testing.T, backend *BackupBackend) []bool {
+	t.Helper()
+	fullBackups, err := backend.FullBackupList(&model.TimeBounds{})
+	require.NoError(t, err)
+	incrementalBackups, err := backend.IncrementalBackupList(&model.TimeBounds{})
+	require.NoError(t, err)
+	var valid []bool
+	for _, backup := range append(fullBackups, incrementalBackups...) {
+		require.NotNil(t, backup.SignatureValid)
+		valid = append(valid, *backup.SignatureValid)
+	}
+	return valid
+}
+
+func TestSignedBackups(t *testing.T) {
+	accessor, restore := signedBackups(t, signingConfig(t, "key"))
+	backend, _ := restore.backends.Get("routine")
+
+	assert.Equal(t, []bool{true, true, true, true}, signaturesValid(t, backend))
+
+	// the hold does not invalidate the signature
+	require.NoError(t, backend.PlaceHold(1000, true, model.BackupHold{Reason: "audit"}))
+	assert.Equal(t, []bool{true, true, true, true}, signaturesValid(t, backend))
+
+	// the tampered manifest invalidates the signature, the listings verify
+	// the cached manifests while the details of the backup read it again
+	path := "backups/routine/backup/1000/data/ns1/" + manifestFile
+	manifest, err := accessor.read(path)
+	require.NoError(t, err)
+	require.NoError(t, accessor.write(path, bytes.Replace(manifest, []byte("checksum: "), []byte("checksum: 0"), 1)))
+	details, err := backend.BackupDetails(1000, true)
+	require.NoError(t, err)
+	var valid []bool
+	for _, backup := range details {
+		valid = append(valid, *backup.SignatureValid)
+	}
+	assert.Equal(t, 1, countFalse(valid), valid)
+
+	report := VerifyBackups(backend, "routine", VerificationFilter{})
+	assert.Equal(t, "missing or invalid signature",
+		failureReasons(report)["memory:///backups/routine/backup/1000/data/ns1"])
+}
+
+func countFalse(values []bool) int {
+	count := 0
+	for _, value := range values {
+		if !value {
+			count++
+		}
+	}
+	return count
+}
+
+func TestSignedBackups_KeyRotation(t *testing.T) {
+	signing := signingConfig(t, "old", "new")
+	_, restore := signedBackups(t, signing)
+	backend, _ := restore.backends.Get("routine")
+
+	// the new key is made active, while the public key of the old one is kept
+	signing.KeyID = "new"
+	signing.Keys["old"].PrivateKeyFile = nil
+	var err error
+	backend.signer, err = loadSigner(restore.config)
+	require.NoError(t, err)
+	assert.Equal(t, []bool{true, true, true, true}, signaturesValid(t, backend))
+
+	// the old key is removed
+	delete(signing.Keys, "old")
+	backend.signer, err = loadSigner(restore.config)
+	require.NoError(t, err)
+	assert.Equal(t, []bool{false, false, false, false}, signaturesValid(t, backend))
+}
+
+func TestRestoreByTime_RequireSignature(t *testing.T) {
+	signing := signingConfig(t, "key")
+	signing.RequireSignature = util.Ptr(true)
+	accessor, restore := signedBackups(t, signing)
+	request := &model.RestoreTimestampRequest{
+		DestinationCuster: &model.AerospikeCluster{},
+		Policy:            &model.RestorePolicy{},
+		Time:              5000,
+		Routine:           "routine",
+	}
+
+	jobID, err := restore.RestoreByTime(request)
+	require.NoError(t, err)
+	status := waitForRestore(t, restore, jobID)
+	assert.Equal(t, model.JobStatusDone, status.Status, status.Error)
+
+	path := "backups/routine/incremental/2000/data/ns2/" + metadataFile
+	metadata, err := accessor.read(path)
+	require.NoError(t, err)
+	require.NoError(t, accessor.write(path, append(metadata, []byte("udf-count: 1\n")...)))
+	_, err = restore.RestoreByTime(request)
+	assert.ErrorIs(t, err, ErrBackupSignature)
+	assert.ErrorContains(t, err, "incremental/2000/data/ns2")
+}
+
+func TestRestore_RequireSignature(t *testing.T) {
+	signing := signingConfig(t, "key")
+	signing.RequireSignature = util.Ptr(true)
+	config := flowConfig()
+	config.ServiceConfig.Signing = signing
+	restore, err := NewRestoreMemory(&BackendHolderImpl{}, config)
+	require.NoError(t, err)
+	folder := t.TempDir()
+	backend := &BackupBackend{StorageAccessor: NewOSDiskAccessor(), signer: restore.signer}
+	storage := &model.Storage{Type: model.Local, Path: &folder}
+
+	metadata := model.BackupMetadata{Created: time.UnixMilli(1000), Namespace: "ns1", RecordCount: 10}
+	require.NoError(t, backend.writeBackupMetadata(folder, metadata))
+	assert.ErrorIs(t, restore.checkStoredSignature(storage), ErrBackupSignature)
+
+	require.NoError(t, backend.signBackup(folder, &metadata))
+	require.NoError(t, backend.writeBackupMetadata(folder, metadata))
+	assert.NoError(t, restore.checkStoredSignature(storage))
+
+	assert.ErrorIs(t, restore.checkStoredSignature(&model.Storage{Type: model.Local, Path: util.Ptr(t.TempDir())}),
+		ErrBackupSignature)
+}
+
+func TestApplyNewConfig_UnreadableSigningKey(t *testing.T) {
+	accessor := newMemoryAccessor()
+	useFakeBackup(t, &fakeBackup{accessor: accessor, records: 100})
+	config := flowConfig()
+	backends := flowBackends(accessor)
+	backend, _ := backends.Get("routine")
+	require.NoError(t, backend.writeState(&model.BackupState{LastFullRun: time.Now()}))
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	scheduler, err := ScheduleBackup(ctx, config, backends)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = scheduler.Clear() })
+
+	signing := signingConfig(t, "key")
+	signing.Keys["key"].PrivateKeyFile = util.Ptr(filepath.Join(t.TempDir(), "missing.pem"))
+	config.ServiceConfig.Signing = signing
+	err = ApplyNewConfig(scheduler, config, backends)
+	assert.ErrorIs(t, err, ErrSigningKeys)
+
+	// the routines stay scheduled with the previous configuration
+	status, err := GetRoutineStatus(scheduler, "routine")
+	require.NoError(t, err)
+	assert.NotNil(t, status.NextFullRun)
+	_, err = NewRestoreMemory(backends, config)
+	assert.ErrorIs(t, err, ErrSigningKeys)
+}
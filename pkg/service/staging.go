@@ -0,0 +1,146 @@
+package service
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+)
+
+// objectStorage is the part of a StorageAccessor of an object storage required
+// for staging.
+type objectStorage interface {
+	open(path string) (io.ReadCloser, error)
+	create(path string) (streamWriter, error)
+	lsFiles(path string) ([]string, error)
+	// deleteFile deletes a single object.
+	deleteFile(path string) error
+}
+
+// localStaging implements the stagedStorage interface for the object storages
+// the shared library cannot access natively.
+type localStaging struct {
+	storage objectStorage
+	root    string
+}
+
+// newLocalStaging returns a new localStaging, the files are staged in a
+// subfolder of the temporary directory with the given name.
+func newLocalStaging(storage objectStorage, name string) *localStaging {
+	return &localStaging{
+		storage: storage,
+		root:    filepath.Join(os.TempDir(), "aerospike-backup-staging", name),
+	}
+}
+
+// stagingFolder returns the local staging folder of the path.
+func (s *localStaging) stagingFolder(path string) string {
+	return filepath.Join(s.root, path)
+}
+
+// createStagingFolder creates the local staging folder of the path.
+func (s *localStaging) createStagingFolder(path string) {
+	if err := os.MkdirAll(s.stagingFolder(path), 0744); err != nil {
+		slog.Warn("Couldn't create staging folder", "path", path, "err", err)
+	}
+}
+
+// upload uploads the files staged for the given path, replacing the
+// previous content of the folder.
+func (s *localStaging) upload(path string) error {
+	previousFiles, err := s.storage.lsFiles(path)
+	if err != nil {
+		return err
+	}
+	uploaded := make(map[string]bool)
+	localDir := s.stagingFolder(path)
+	err = filepath.WalkDir(localDir, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		relativePath, err := filepath.Rel(localDir, file)
+		if err != nil {
+			return err
+		}
+		objectPath := filepath.Join(path, relativePath)
+		uploaded[objectPath] = true
+		return s.uploadFile(file, objectPath)
+	})
+	if err != nil {
+		return err
+	}
+	// the files of an overwritten backup are removed
+	for _, file := range previousFiles {
+		if !uploaded[file] {
+			if err := s.storage.deleteFile(file); err != nil {
+				slog.Debug("Couldn't delete file", "path", file, "err", err)
+			}
+		}
+	}
+	return nil
+}
+
+// uploadFile streams the local file to the object path.
+func (s *localStaging) uploadFile(file string, objectPath string) error {
+	localFile, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer localFile.Close()
+	writer, err := s.storage.create(objectPath)
+	if err != nil {
+		return err
+	}
+	return copyStream(writer, localFile)
+}
+
+// removeStaged removes the local staging folder of the given path.
+func (s *localStaging) removeStaged(path string) {
+	if err := os.RemoveAll(s.stagingFolder(path)); err != nil {
+		slog.Warn("Couldn't remove staging folder", "path", path, "err", err)
+	}
+}
+
+// download downloads the files of the given path into the local folder.
+func (s *localStaging) download(path string, localDir string) error {
+	files, err := s.storage.lsFiles(path)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("given path %s not exist", path)
+	}
+	for _, file := range files {
+		relativePath, err := filepath.Rel(path, file)
+		if err != nil {
+			return err
+		}
+		if err := s.downloadFile(file, filepath.Join(localDir, relativePath)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// downloadFile streams the object to the local file.
+func (s *localStaging) downloadFile(objectPath string, file string) error {
+	reader, err := s.storage.open(objectPath)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	if err := os.MkdirAll(filepath.Dir(file), 0744); err != nil {
+		return err
+	}
+	localFile, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(localFile, reader)
+	if closeErr := localFile.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
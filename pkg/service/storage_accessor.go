@@ -1,6 +1,13 @@
 package service
 
-import "github.com/aerospike/backup/pkg/model"
+import (
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/aerospike/backup/pkg/model"
+)
 
 type StorageAccessor interface {
 	// readBackupState reads backup state for a backup.
@@ -8,13 +15,22 @@ type StorageAccessor interface {
 	// readBackupDetails returns backup details for a backup.
 	readBackupDetails(path string, useCache bool) (model.BackupDetails, error)
 	// read reads given file.
+	// It holds the whole content in memory, the backup files are read with open.
 	read(path string) ([]byte, error)
 	// write writes the given byte array to a file
+	// It holds the whole content in memory, the backup files are written with create.
 	write(filePath string, v []byte) error
+	// open opens the given file for reading as a stream. The reader must be closed by the caller.
+	open(path string) (io.ReadCloser, error)
+	// create creates the given file for writing as a stream.
+	// The file is stored when the writer is closed.
+	create(path string) (streamWriter, error)
 	// lsDir lists all subdirectories in the given path.
 	lsDir(path string) ([]string, error)
 	// lsDir lists all files in the given path.
 	lsFiles(path string) ([]string, error)
+	// fileSize returns the size of the file in bytes.
+	fileSize(path string) (int64, error)
 	// DeleteFolder removes the folder and all its contents at the specified path.
 	DeleteFolder(path string) error
 	// CreateFolder creates a folder at the specified path.
@@ -22,3 +38,75 @@ type StorageAccessor interface {
 	// wrapWithPrefix combines path with bucket name. This is the opposite of url.parse, required for asbackup library.
 	wrapWithPrefix(path string) *string
 }
+
+// stagedStorage is implemented by the storage accessors the shared library cannot
+// access natively. The backup files are staged in a local folder returned by
+// wrapWithPrefix and uploaded after the backup.
+type stagedStorage interface {
+	// upload uploads the files staged for the given path.
+	upload(path string) error
+	// removeStaged removes the local staging folder of the given path.
+	removeStaged(path string)
+	// download downloads the files of the given path into the local folder.
+	download(path string, localDir string) error
+}
+
+// lockedStorage is implemented by the storage accessors that can lock the written
+// objects against deletion.
+type lockedStorage interface {
+	// lockedUntil returns the time the objects written at the given time are locked until,
+	// or nil if the objects are not locked.
+	lockedUntil(now time.Time) *time.Time
+	// writeLockedUntil writes the file locked until the given time, or unlocked if it is
+	// nil or has passed, such as the updated metadata of a backup locked when created.
+	writeLockedUntil(path string, data []byte, lockedUntil *time.Time) error
+}
+
+var _ lockedStorage = (*S3Context)(nil)
+
+// stagedStorageTypes are the storage types with a stagedStorage accessor.
+var stagedStorageTypes = []model.StorageType{model.GcpGCS, model.AzureBlob, model.SFTP}
+
+// isStaged returns true if the storage has a stagedStorage accessor.
+// The S3 object options and explicit credentials are not supported by the shared library.
+func isStaged(storage *model.Storage) bool {
+	return slices.Contains(stagedStorageTypes, storage.Type) || storage.IsStagedS3()
+}
+
+// newStorageAccessor returns the StorageAccessor for the storage,
+// along with the root path of the backups within it.
+func newStorageAccessor(storage *model.Storage) (StorageAccessor, string, error) {
+	switch storage.Type {
+	case model.Local:
+		return NewOSDiskAccessor(), *storage.Path, nil
+	case model.S3:
+		s3Context, err := NewS3Context(storage)
+		if err != nil {
+			return nil, "", err
+		}
+		if storage.IsStagedS3() {
+			return newStagedS3Context(s3Context), s3Context.path, nil
+		}
+		return s3Context, s3Context.path, nil
+	case model.GcpGCS:
+		gcsContext, err := NewGcsContext(storage)
+		if err != nil {
+			return nil, "", err
+		}
+		return gcsContext, gcsContext.path, nil
+	case model.AzureBlob:
+		azureContext, err := NewAzureContext(storage)
+		if err != nil {
+			return nil, "", err
+		}
+		return azureContext, azureContext.path, nil
+	case model.SFTP:
+		sftpContext, err := NewSftpContext(storage)
+		if err != nil {
+			return nil, "", err
+		}
+		return sftpContext, sftpContext.path, nil
+	default:
+		return nil, "", fmt.Errorf("unsupported storage type: %v", storage.Type)
+	}
+}
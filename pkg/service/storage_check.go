@@ -0,0 +1,133 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aerospike/backup/pkg/model"
+)
+
+// storageCheckFile is the name of the temporary file written by the storage check.
+// It is not locked by the storage, so it can be deleted right away.
+const storageCheckFile = "storage-check.txt"
+
+var storageCheckData = []byte("aerospike backup service storage check")
+
+// CheckStorage checks the connectivity and permissions of the storage.
+// It writes a temporary file, reads it back, lists it and deletes it.
+func CheckStorage(storage *model.Storage) *model.StorageCheck {
+	check := &model.StorageCheck{Steps: []model.StorageCheckStep{}}
+	var accessor StorageAccessor
+	var root string
+	connected := runCheckStep(check, model.StorageCheckConnect, func() (err error) {
+		accessor, root, err = newStorageAccessor(storage)
+		return err
+	})
+	if connected {
+		checkStorageAccessor(check, accessor, root)
+	}
+	check.Success = !slices.ContainsFunc(check.Steps, func(step model.StorageCheckStep) bool {
+		return step.Error != ""
+	})
+	return check
+}
+
+// checkStorageAccessor runs the steps of the check with a temporary folder in the root path.
+func checkStorageAccessor(check *model.StorageCheck, accessor StorageAccessor, root string) {
+	folder := filepath.Join(root, fmt.Sprintf(".storage-check-%d", time.Now().UnixNano()))
+	path := filepath.Join(folder, storageCheckFile)
+
+	written := runCheckStep(check, model.StorageCheckWrite, func() error {
+		accessor.CreateFolder(folder)
+		return accessor.write(path, storageCheckData)
+	})
+	if !written {
+		return
+	}
+	// the file is deleted even if reading or listing it fails
+	defer runCheckStep(check, model.StorageCheckDelete, func() error {
+		return accessor.DeleteFolder(folder)
+	})
+
+	read := runCheckStep(check, model.StorageCheckRead, func() error {
+		content, err := accessor.read(path)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(content, storageCheckData) {
+			return errors.New("read content differs from the written one")
+		}
+		return nil
+	})
+	if !read {
+		return
+	}
+	runCheckStep(check, model.StorageCheckList, func() error {
+		files, err := accessor.lsFiles(folder)
+		if err != nil {
+			return err
+		}
+		if !slices.ContainsFunc(files, func(file string) bool {
+			return filepath.Base(file) == storageCheckFile
+		}) {
+			return fmt.Errorf("written file not found in %s", folder)
+		}
+		return nil
+	})
+}
+
+// runCheckStep runs a step of the check and records its duration and error.
+// Returns true if the step succeeded.
+func runCheckStep(check *model.StorageCheck, name string, step func() error) bool {
+	start := time.Now()
+	err := step()
+	result := model.StorageCheckStep{Name: name, DurationMillis: time.Since(start).Milliseconds()}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	check.Steps = append(check.Steps, result)
+	return err == nil
+}
+
+// CheckChangedStorages checks the storages of the configuration that were added or
+// changed since the applied ones. Returns an error listing the failed checks.
+func CheckChangedStorages(applied map[string]model.Storage, storages map[string]*model.Storage) error {
+	var failed []string
+	for name, storage := range storages {
+		if previous, found := applied[name]; found && reflect.DeepEqual(previous, *storage) {
+			continue
+		}
+		check := CheckStorage(storage)
+		if check.Success {
+			slog.Info("Storage check succeeded", "storage", name)
+			continue
+		}
+		for _, step := range check.Steps {
+			if step.Error != "" {
+				slog.Warn("Storage check failed", "storage", name, "step", step.Name, "err", step.Error)
+				failed = append(failed, fmt.Sprintf("%s: %s: %s", name, step.Name, step.Error))
+			}
+		}
+	}
+	if len(failed) > 0 {
+		slices.Sort(failed)
+		return fmt.Errorf("storage check failed: %s", strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+// SnapshotStorages returns a copy of the storages to detect the changed ones on the next apply.
+func SnapshotStorages(storages map[string]*model.Storage) map[string]model.Storage {
+	snapshot := make(map[string]model.Storage, len(storages))
+	for name, storage := range storages {
+		snapshot[name] = *storage
+	}
+	return snapshot
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aerospike/backup/pkg/model"
+	"github.com/aerospike/backup/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckStorage(t *testing.T) {
+	root := t.TempDir()
+	check := CheckStorage(&model.Storage{Type: model.Local, Path: util.Ptr(root)})
+
+	assert.True(t, check.Success)
+	var steps []string
+	for _, step := range check.Steps {
+		steps = append(steps, step.Name)
+		assert.Empty(t, step.Error)
+	}
+	assert.Equal(t, []string{model.StorageCheckConnect, model.StorageCheckWrite, model.StorageCheckRead,
+		model.StorageCheckList, model.StorageCheckDelete}, steps)
+	entries, _ := os.ReadDir(root)
+	assert.Empty(t, entries)
+}
+
+func TestCheckStorage_Failed(t *testing.T) {
+	// a regular file in place of the root folder
+	root := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(root, []byte("data"), 0600))
+
+	check := CheckStorage(&model.Storage{Type: model.Local, Path: util.Ptr(root)})
+
+	assert.False(t, check.Success)
+	require.Len(t, check.Steps, 2)
+	assert.Equal(t, model.StorageCheckWrite, check.Steps[1].Name)
+	assert.NotEmpty(t, check.Steps[1].Error)
+}
+
+func TestCheckChangedStorages(t *testing.T) {
+	invalidRoot := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(invalidRoot, []byte("data"), 0600))
+	storages := map[string]*model.Storage{
+		"valid":   {Type: model.Local, Path: util.Ptr(t.TempDir())},
+		"invalid": {Type: model.Local, Path: util.Ptr(invalidRoot)},
+	}
+
+	// the unchanged storages are not checked
+	assert.NoError(t, CheckChangedStorages(SnapshotStorages(storages), storages))
+
+	err := CheckChangedStorages(map[string]model.Storage{}, storages)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid: write")
+	assert.NotContains(t, err.Error(), ";") // only the invalid storage failed
+}
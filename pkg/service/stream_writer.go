@@ -0,0 +1,64 @@
+package service
+
+import (
+	"io"
+)
+
+// streamWriter writes a file as a stream, without holding its content in memory.
+// The file is stored when the writer is closed, and discarded by CloseWithError.
+type streamWriter interface {
+	io.WriteCloser
+	// CloseWithError discards the file, the upload fails with the given error.
+	CloseWithError(err error) error
+}
+
+// pipeWriter is a streamWriter feeding the upload function through a pipe.
+// The upload runs in its own goroutine until the writer is closed.
+type pipeWriter struct {
+	*io.PipeWriter
+	done chan struct{}
+	err  error
+}
+
+var _ streamWriter = (*pipeWriter)(nil)
+
+// newPipeWriter starts the upload of the content written to the returned writer.
+// The upload must read the reader until it fails or returns io.EOF.
+func newPipeWriter(upload func(reader io.Reader) error) *pipeWriter {
+	reader, writer := io.Pipe()
+	w := &pipeWriter{
+		PipeWriter: writer,
+		done:       make(chan struct{}),
+	}
+	go func() {
+		defer close(w.done)
+		w.err = upload(reader)
+		// unblock the writer if the upload stopped reading
+		_ = reader.CloseWithError(w.err)
+	}()
+	return w
+}
+
+// Close completes the upload and returns its error.
+func (w *pipeWriter) Close() error {
+	_ = w.PipeWriter.Close()
+	<-w.done
+	return w.err
+}
+
+// CloseWithError aborts the upload and returns its error.
+func (w *pipeWriter) CloseWithError(err error) error {
+	_ = w.PipeWriter.CloseWithError(err)
+	<-w.done
+	return w.err
+}
+
+// copyStream copies the reader into the writer and stores the file.
+// The file is discarded if the copy fails.
+func copyStream(writer streamWriter, reader io.Reader) error {
+	if _, err := io.Copy(writer, reader); err != nil {
+		_ = writer.CloseWithError(err)
+		return err
+	}
+	return writer.Close()
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/aerospike/backup/pkg/model"
+)
+
+// applyTiering moves the backup chains older than the tiering threshold to the
+// tiering storage. The latest chain is never moved, as incremental backups are
+// still added to it.
+func (h *BackupHandler) applyTiering(now time.Time) {
+	policy := h.backupFullPolicy.Tiering
+	if policy == nil || h.backend.tier == nil {
+		return
+	}
+	allTime := &model.TimeBounds{}
+	fullBackups, err := h.backend.fullBackupList(allTime)
+	if err != nil {
+		slog.Error("Could not read full backup list", "name", h.routineName, "err", err)
+		return
+	}
+	incrementalBackups, err := h.backend.incrementalBackupList(allTime)
+	if err != nil {
+		slog.Error("Could not read incremental backup list", "name", h.routineName, "err", err)
+		return
+	}
+	chains, _ := buildBackupChains(fullBackups, incrementalBackups)
+	if len(chains) == 0 {
+		return
+	}
+
+	threshold := now.AddDate(0, 0, -*policy.AfterDays)
+	for _, chain := range chains[:len(chains)-1] {
+		if chain.created.After(threshold) {
+			break
+		}
+		if err := h.backend.moveChain(chain); err != nil {
+			slog.Error("Could not move backup to tiering storage", "name", h.routineName,
+				"created", chain.created, "storage", *policy.Storage, "err", err)
+			continue
+		}
+		slog.Info("Moved backup to tiering storage", "name", h.routineName,
+			"created", chain.created, "storage", *policy.Storage,
+			"incrementalBackups", len(chain.incrementals))
+	}
+}
+
+// moveChain moves the full backup and its incremental backups to the tiering storage.
+// The backups are deleted from the storage only after all of them are copied.
+func (b *BackupBackend) moveChain(chain *backupChain) error {
+	fullBackupFolder := filepath.Join(b.fullBackupsPath, timeSuffix(chain.created))
+	if err := b.copyToTier(fullBackupFolder); err != nil {
+		return err
+	}
+	// incremental backups of all namespaces created at the same time share the same folder
+	var incrementalBackups []time.Time
+	for _, backup := range chain.incrementals {
+		if !slices.ContainsFunc(incrementalBackups, backup.Created.Equal) {
+			incrementalBackups = append(incrementalBackups, backup.Created)
+		}
+	}
+	for _, created := range incrementalBackups {
+		if err := b.copyToTier(filepath.Join(b.incrementalBackupsPath, timeSuffix(created))); err != nil {
+			return err
+		}
+	}
+
+	for _, created := range incrementalBackups {
+		if err := b.deleteIncrementalBackup(created); err != nil {
+			return err
+		}
+	}
+	return b.deleteFullBackup(chain.created)
+}
+
+// copyToTier copies the backup folder, with the backups of all the namespaces
+// and the backed up cluster configuration, to the tiering storage.
+// The metadata is copied last, so that an incomplete copy is never listed as a backup.
+func (b *BackupBackend) copyToTier(folder string) error {
+	namespaces, err := b.lsDir(filepath.Join(folder, model.DataDirectory))
+	if err != nil {
+		return err
+	}
+	for _, namespacePath := range namespaces {
+		targetPath, _, err := copyFiles(context.Background(), b, b.tier, namespacePath)
+		if err != nil {
+			return err
+		}
+		details, err := b.readBackupDetails(namespacePath, false)
+		if err != nil {
+			return err
+		}
+		if err := b.tier.writeBackupMetadata(targetPath, details.BackupMetadata); err != nil {
+			return err
+		}
+	}
+
+	configPath := filepath.Join(folder, model.ConfigurationBackupDirectory)
+	if files, err := b.lsFiles(configPath); err == nil && len(files) > 0 {
+		if _, _, err := copyFiles(context.Background(), b, b.tier, configPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
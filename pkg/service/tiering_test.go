@@ -0,0 +1,109 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aerospike/backup/pkg/model"
+	"github.com/aerospike/backup/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func tieringConfig(t *testing.T) *model.Config {
+	t.Helper()
+	return &model.Config{
+		Storage: map[string]*model.Storage{
+			"local": {Type: model.Local, Path: util.Ptr(t.TempDir())},
+			"cold":  {Type: model.Local, Path: util.Ptr(t.TempDir())},
+		},
+		BackupPolicies: map[string]*model.BackupPolicy{
+			"policy": {Tiering: &model.TieringPolicy{AfterDays: util.Ptr(1), Storage: util.Ptr("cold")}},
+		},
+		BackupRoutines: map[string]*model.BackupRoutine{
+			"routine": {Storage: "local", BackupPolicy: "policy"},
+		},
+	}
+}
+
+func TestApplyTiering(t *testing.T) {
+	config := tieringConfig(t)
+	backend := newBackend(config, "routine", nil)
+	write := func(path string, created int64) {
+		backend.CreateFolder(path)
+		require.NoError(t, backend.write(filepath.Join(path, "ns1_0.asb"), []byte("data")))
+		require.NoError(t, backend.writeBackupMetadata(path,
+			model.BackupMetadata{Created: time.UnixMilli(created), Namespace: "ns1"}))
+	}
+	for _, created := range []int64{100, 200} {
+		write(getFullPath(backend.fullBackupsPath, &model.BackupPolicy{}, "ns1", time.UnixMilli(created)), created)
+		write(getIncrementalPath(backend.incrementalBackupsPath, "ns1", time.UnixMilli(created+50)), created+50)
+	}
+	configPath := getConfigurationPath(backend.fullBackupsPath, &model.BackupPolicy{}, time.UnixMilli(100))
+	require.NoError(t, backend.write(filepath.Join(configPath, "aerospike_0.conf"), []byte("conf")))
+
+	handler := &BackupHandler{
+		backend:          backend,
+		backupFullPolicy: config.BackupPolicies["policy"],
+		routineName:      "routine",
+	}
+	handler.applyTiering(time.UnixMilli(200).AddDate(0, 0, 1))
+
+	allTime := &model.TimeBounds{}
+	storedFullBackups, _ := backend.fullBackupList(allTime)
+	assert.ElementsMatch(t, []int64{200}, createdMillis(storedFullBackups))
+	storedIncrementalBackups, _ := backend.incrementalBackupList(allTime)
+	assert.ElementsMatch(t, []int64{250}, createdMillis(storedIncrementalBackups))
+
+	fullBackups, _ := backend.FullBackupList(allTime)
+	assert.ElementsMatch(t, []int64{100, 200}, createdMillis(fullBackups))
+	incrementalBackups, _ := backend.IncrementalBackupList(allTime)
+	assert.ElementsMatch(t, []int64{150, 250}, createdMillis(incrementalBackups))
+	for _, backup := range append(fullBackups, incrementalBackups...) {
+		expectedStorage := "local"
+		if backup.Created.UnixMilli() < 200 {
+			expectedStorage = "cold"
+		}
+		assert.Equal(t, expectedStorage, *backup.Storage)
+	}
+
+	// the configuration path is calculated from the key of the tiered backup
+	tieredConfigPath := getConfigurationPath(backend.tier.fullBackupsPath, &model.BackupPolicy{}, time.UnixMilli(100))
+	configuration, err := backend.ReadClusterConfiguration(tieredConfigPath)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, configuration)
+
+	restore := newRestoreMemory(nil, config, nil)
+	request := restore.toRestoreRequest(&model.RestoreTimestampRequest{Routine: "routine"}, util.Ptr("cold"))
+	assert.Equal(t, config.Storage["cold"], request.SourceStorage)
+
+	require.NoError(t, backend.PlaceHold(150, false, model.BackupHold{Reason: "audit"}))
+	require.NoError(t, backend.ReleaseHold(150, false, "auditor"))
+	require.NoError(t, backend.DeleteFullBackup(100, true))
+	fullBackups, _ = backend.FullBackupList(allTime)
+	assert.ElementsMatch(t, []int64{200}, createdMillis(fullBackups))
+	_, err = os.Stat(filepath.Join(backend.tier.fullBackupsPath, "100"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestNewBackend_TierUnreachable(t *testing.T) {
+	config := tieringConfig(t)
+	config.Storage["cold"] = &model.Storage{
+		Type:               model.SFTP,
+		Path:               util.Ptr("/backups"),
+		SftpHost:           util.Ptr("localhost"),
+		SftpKnownHostsFile: util.Ptr(filepath.Join(t.TempDir(), "known_hosts")),
+	}
+
+	backend := newBackend(config, "routine", nil)
+
+	assert.Nil(t, backend.tier)
+	handler := &BackupHandler{
+		backend:          backend,
+		backupFullPolicy: config.BackupPolicies["policy"],
+		routineName:      "routine",
+	}
+	handler.applyTiering(time.Now()) // tiering is skipped
+}
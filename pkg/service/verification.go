@@ -0,0 +1,338 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aerospike/backup/pkg/model"
+	"github.com/reugn/go-quartz/quartz"
+)
+
+const quartzGroupVerification = "verification"
+
+// VerificationFilter selects the backups to verify.
+// All the backups of the routine are verified if no field is set.
+type VerificationFilter struct {
+	// The creation time of the backups to verify (epoch millis).
+	Timestamp *int64
+	// The key of the backup to verify.
+	Key *string
+}
+
+// matches returns true if the backup created at the given time with one of the given keys is selected.
+func (f VerificationFilter) matches(created time.Time, keys ...string) bool {
+	if f.Timestamp != nil && created.UnixMilli() != *f.Timestamp {
+		return false
+	}
+	return f.Key == nil || slices.Contains(keys, *f.Key)
+}
+
+// verificationReports holds the reports of the verification jobs,
+// and the latest report of each routine.
+var verificationReports = struct {
+	sync.Mutex
+	jobs   map[int]*model.VerificationReport
+	latest map[string]*model.VerificationReport
+}{
+	jobs:   make(map[int]*model.VerificationReport),
+	latest: make(map[string]*model.VerificationReport),
+}
+
+// StartVerification starts an asynchronous verification of the selected backups of the routine.
+// Returns the id of the job to get the report with.
+func StartVerification(backend *BackupBackend, routineName string, filter VerificationFilter) int {
+	jobID := rand.Int()
+	verificationReports.Lock()
+	verificationReports.jobs[jobID] = newVerificationReport(routineName)
+	verificationReports.Unlock()
+
+	go func() {
+		report := VerifyBackups(backend, routineName, filter)
+		verificationReports.Lock()
+		defer verificationReports.Unlock()
+		verificationReports.jobs[jobID] = report
+	}()
+	return jobID
+}
+
+// VerificationStatus returns the report of the verification job.
+func VerificationStatus(jobID int) (*model.VerificationReport, error) {
+	verificationReports.Lock()
+	defer verificationReports.Unlock()
+	report, found := verificationReports.jobs[jobID]
+	if !found {
+		return nil, fmt.Errorf("job with ID %d not found", jobID)
+	}
+	reportCopy := *report
+	return &reportCopy, nil
+}
+
+// LastVerificationReport returns the report of the latest completed verification
+// of the routine, or nil if the routine backups were not verified yet.
+func LastVerificationReport(routineName string) *model.VerificationReport {
+	verificationReports.Lock()
+	defer verificationReports.Unlock()
+	report, found := verificationReports.latest[routineName]
+	if !found {
+		return nil
+	}
+	reportCopy := *report
+	return &reportCopy
+}
+
+func newVerificationReport(routineName string) *model.VerificationReport {
+	return &model.VerificationReport{
+		Routine:  routineName,
+		Status:   model.JobStatusRunning,
+		Started:  currentTime(),
+		Failures: []model.VerificationFailure{},
+	}
+}
+
+// VerifyBackups re-reads the files of the selected backups of the routine and compares
+// their sizes and checksums with the manifests. Missing metadata files, invalid signatures
+// and gaps in the incremental backup chains are reported as failures too.
+func VerifyBackups(backend *BackupBackend, routineName string, filter VerificationFilter) *model.VerificationReport {
+	report := newVerificationReport(routineName)
+	backend.verifyStoredBackups(filter, report)
+	if backend.tier != nil {
+		backend.tier.verifyStoredBackups(filter, report)
+	}
+
+	if report.BackupCount == 0 {
+		report.Status = model.JobStatusFailed
+		report.Error = ErrBackupNotFound.Error()
+	} else {
+		backend.verifyChains(filter, report)
+		report.Status = model.JobStatusDone
+		report.Success = len(report.Failures) == 0
+		// the gauge reflects the verification of all the backups of the routine
+		if filter == (VerificationFilter{}) {
+			verificationFailuresGauge.WithLabelValues(routineName).Set(float64(len(report.Failures)))
+		}
+	}
+	finished := currentTime()
+	report.Finished = &finished
+
+	if report.Success {
+		slog.Info("Backups verified", "name", routineName,
+			"backups", report.BackupCount, "files", report.FileCount)
+	} else {
+		slog.Warn("Backup verification failed", "name", routineName,
+			"backups", report.BackupCount, "failures", len(report.Failures), "err", report.Error)
+	}
+
+	verificationReports.Lock()
+	defer verificationReports.Unlock()
+	verificationReports.latest[routineName] = report
+	return report
+}
+
+// verifyStoredBackups verifies the selected backups located in the storage.
+func (b *BackupBackend) verifyStoredBackups(filter VerificationFilter, report *model.VerificationReport) {
+	for _, isFullBackup := range []bool{true, false} {
+		for _, path := range b.namespaceFolders(isFullBackup) {
+			details, err := b.readBackupDetails(path, false)
+			hasMetadata := err == nil
+			// the folder of a running backup has no metadata yet
+			if !hasMetadata && b.backupInProgress(isFullBackup) {
+				continue
+			}
+			key, created := path, details.Created
+			if hasMetadata && details.Key != nil {
+				key = *details.Key
+			}
+			if !hasMetadata {
+				created = backupFolderTime(path)
+			}
+			if !filter.matches(created, key, path) {
+				continue
+			}
+
+			report.BackupCount++
+			if !hasMetadata {
+				addVerificationFailure(report, key, "", "missing metadata")
+			} else if b.signer != nil {
+				details.Manifest, _ = b.readManifest(path)
+				if !b.signer.verify(details.BackupMetadata, details.Manifest) {
+					addVerificationFailure(report, key, "", "missing or invalid signature")
+				}
+			}
+			b.verifyFiles(path, key, report)
+		}
+	}
+}
+
+// backupInProgress returns true if a full or incremental backup is running.
+func (b *BackupBackend) backupInProgress(isFullBackup bool) bool {
+	if isFullBackup {
+		return b.fullBackupInProgress.Load()
+	}
+	return b.incrementalInProgress.Load()
+}
+
+// backupFolderTime returns the creation time of the backup at the given path,
+// parsed from the name of its timestamp folder.
+func backupFolderTime(path string) time.Time {
+	// path is <backup folder>/data/<namespace>
+	timestamp, err := strconv.ParseInt(filepath.Base(filepath.Dir(filepath.Dir(path))), 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.UnixMilli(timestamp)
+}
+
+// namespaceFolders returns the namespace folders of the full or incremental backups.
+func (b *BackupBackend) namespaceFolders(isFullBackup bool) []string {
+	var folders []string
+	switch {
+	case isFullBackup && b.removeFullBackup:
+		folders = []string{b.fullBackupsPath}
+	case isFullBackup:
+		folders = b.listFolders(b.fullBackupsPath)
+	default:
+		folders = b.listFolders(b.incrementalBackupsPath)
+	}
+	var namespaces []string
+	for _, folder := range folders {
+		namespaces = append(namespaces, b.listFolders(filepath.Join(folder, model.DataDirectory))...)
+	}
+	return namespaces
+}
+
+func (b *BackupBackend) listFolders(path string) []string {
+	folders, err := b.lsDir(path)
+	if err != nil {
+		slog.Warn("Cannot list backup dir", "path", path, "err", err)
+	}
+	return folders
+}
+
+// verifyFiles compares the files of the backup at the given path with its manifest.
+func (b *BackupBackend) verifyFiles(path, key string, report *model.VerificationReport) {
+	manifest, err := b.readManifest(path)
+	if err != nil {
+		addVerificationFailure(report, key, "", "missing manifest")
+		return
+	}
+	root := filepath.Dir(filepath.Dir(path))
+	inManifest := make(map[string]bool, len(manifest.Files))
+	for _, file := range manifest.Files {
+		inManifest[file.Path] = true
+		report.FileCount++
+		actual, err := b.checksum(filepath.Join(root, file.Path))
+		switch {
+		case err != nil:
+			addVerificationFailure(report, key, file.Path, fmt.Sprintf("cannot read file: %v", err))
+		case actual.Size != file.Size:
+			addVerificationFailure(report, key, file.Path,
+				fmt.Sprintf("size mismatch: expected %d bytes, found %d", file.Size, actual.Size))
+		case actual.Checksum != file.Checksum:
+			addVerificationFailure(report, key, file.Path, "checksum mismatch")
+		}
+	}
+
+	files, err := b.manifestFiles(backupDataFiles(path))
+	if err != nil {
+		addVerificationFailure(report, key, "", err.Error())
+		return
+	}
+	for _, file := range files {
+		relativePath, err := filepath.Rel(root, file)
+		if err == nil && !inManifest[relativePath] {
+			addVerificationFailure(report, key, relativePath, "file not in manifest")
+		}
+	}
+}
+
+// verifyChains reports the selected incremental backups without a preceding full backup,
+// and the ones that do not start where the previous backup of the namespace ends.
+func (b *BackupBackend) verifyChains(filter VerificationFilter, report *model.VerificationReport) {
+	allTime := &model.TimeBounds{}
+	fullBackups, err := b.FullBackupList(allTime)
+	if err != nil {
+		addVerificationFailure(report, b.routinePath(), "", err.Error())
+		return
+	}
+	incrementalBackups, err := b.IncrementalBackupList(allTime)
+	if err != nil {
+		addVerificationFailure(report, b.routinePath(), "", err.Error())
+		return
+	}
+
+	for _, issue := range chainIssues(fullBackups, incrementalBackups) {
+		if !filter.matches(issue.Created, issue.Key) {
+			continue
+		}
+		switch issue.Type {
+		case model.ChainMissingFull:
+			addVerificationFailure(report, issue.Key, "", "no preceding full backup")
+		case model.ChainGap:
+			addVerificationFailure(report, issue.Key, "",
+				"incremental chain gap since "+issue.Previous.Format(time.RFC3339))
+		}
+	}
+}
+
+// byNamespace groups the backups by namespace.
+func byNamespace(backups []model.BackupDetails) map[string][]model.BackupDetails {
+	result := make(map[string][]model.BackupDetails)
+	for _, backup := range backups {
+		result[backup.Namespace] = append(result[backup.Namespace], backup)
+	}
+	return result
+}
+
+func backupKey(backup model.BackupDetails) string {
+	if backup.Key == nil {
+		return ""
+	}
+	return *backup.Key
+}
+
+func addVerificationFailure(report *model.VerificationReport, path, file, reason string) {
+	report.Failures = append(report.Failures, model.VerificationFailure{
+		Path:   path,
+		File:   file,
+		Reason: reason,
+	})
+}
+
+// verificationJob implements the quartz.Job interface.
+type verificationJob struct {
+	backend     *BackupBackend
+	routineName string
+}
+
+var _ quartz.Job = (*verificationJob)(nil)
+
+// Execute is called by a Scheduler when the Trigger associated with this job fires.
+func (j *verificationJob) Execute(_ context.Context) error {
+	VerifyBackups(j.backend, j.routineName, VerificationFilter{})
+	return nil
+}
+
+// Description returns the description of the verification job.
+func (j *verificationJob) Description() string {
+	return "backup verification job"
+}
+
+func scheduleVerification(scheduler quartz.Scheduler, backend *BackupBackend,
+	routine *model.BackupRoutine, routineName string) error {
+	trigger, err := quartz.NewCronTrigger(routine.VerifyCron)
+	if err != nil {
+		return err
+	}
+	jobDetail := quartz.NewJobDetail(
+		&verificationJob{backend: backend, routineName: routineName},
+		quartz.NewJobKeyWithGroup(routineName, quartzGroupVerification),
+	)
+	return scheduler.ScheduleJob(jobDetail, trigger)
+}
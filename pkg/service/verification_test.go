@@ -0,0 +1,140 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aerospike/backup/pkg/model"
+	"github.com/aerospike/backup/pkg/util"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// verifiedBackend returns a backend with a full backup at 1000 and incremental backups
+// at 2000, 3000 and 4000 of the namespaces ns1 and ns2.
+func verifiedBackend(t *testing.T) (*memoryAccessor, *BackupBackend) {
+	t.Helper()
+	accessor := newMemoryAccessor()
+	useFakeBackup(t, &fakeBackup{accessor: accessor, records: 100, incrementalRecords: 10})
+	config := flowConfig()
+	backends := flowBackends(accessor)
+	backend, _ := backends.Get("routine")
+	handler, err := newBackupHandler(config, "routine", backend, backends)
+	require.NoError(t, err)
+
+	handler.runFullBackup(time.UnixMilli(1000))
+	for _, created := range []int64{2000, 3000, 4000} {
+		handler.runIncrementalBackup(time.UnixMilli(created))
+	}
+	return accessor, backend
+}
+
+func failureReasons(report *model.VerificationReport) map[string]string {
+	reasons := make(map[string]string, len(report.Failures))
+	for _, failure := range report.Failures {
+		key := failure.Path
+		if failure.File != "" {
+			key += "/" + failure.File
+		}
+		reasons[key] = failure.Reason
+	}
+	return reasons
+}
+
+func TestVerifyBackups_Intact(t *testing.T) {
+	_, backend := verifiedBackend(t)
+
+	report := VerifyBackups(backend, "routine", VerificationFilter{})
+
+	assert.Equal(t, model.JobStatusDone, report.Status)
+	assert.True(t, report.Success, report.Failures)
+	assert.Equal(t, 8, report.BackupCount)
+	assert.Equal(t, 10, report.FileCount, "2 full backups with configuration, 6 incremental backups")
+	assert.NotNil(t, report.Finished)
+	assert.Equal(t, float64(0), testutil.ToFloat64(verificationFailuresGauge.WithLabelValues("routine")))
+}
+
+func TestVerifyBackups_Failures(t *testing.T) {
+	accessor, backend := verifiedBackend(t)
+	full := "memory:///backups/routine/backup/1000/data/"
+	incremental := "memory:///backups/routine/incremental/"
+	// corrupted, truncated, missing and unexpected files
+	corrupted, err := accessor.read("backups/routine/backup/1000/data/ns1/ns1_0.asb")
+	require.NoError(t, err)
+	corrupted[0] ^= 1
+	require.NoError(t, accessor.write("backups/routine/backup/1000/data/ns1/ns1_0.asb", corrupted))
+	require.NoError(t, accessor.write("backups/routine/backup/1000/configuration/aerospike_0.conf", []byte("")))
+	delete(accessor.files, "backups/routine/incremental/2000/data/ns1/ns1_0.asb")
+	require.NoError(t, accessor.write("backups/routine/incremental/2000/data/ns2/ns2_1.asb", []byte("unexpected")))
+	// missing metadata
+	delete(accessor.files, "backups/routine/incremental/4000/data/ns2/"+metadataFile)
+	// chain gap
+	require.NoError(t, backend.DeleteFolder("backups/routine/incremental/3000/data/ns1"))
+
+	report := VerifyBackups(backend, "routine", VerificationFilter{})
+
+	assert.Equal(t, model.JobStatusDone, report.Status)
+	assert.False(t, report.Success)
+	assert.Equal(t, 7, report.BackupCount)
+	reasons := failureReasons(report)
+	assert.Equal(t, "checksum mismatch", reasons[full+"ns1/data/ns1/ns1_0.asb"])
+	assert.Contains(t, reasons[full+"ns1/configuration/aerospike_0.conf"], "size mismatch")
+	assert.Contains(t, reasons[full+"ns2/configuration/aerospike_0.conf"], "size mismatch")
+	assert.Contains(t, reasons[incremental+"2000/data/ns1/data/ns1/ns1_0.asb"], "cannot read file")
+	assert.Equal(t, "file not in manifest", reasons[incremental+"2000/data/ns2/data/ns2/ns2_1.asb"])
+	assert.Equal(t, "missing metadata", reasons["backups/routine/incremental/4000/data/ns2"])
+	assert.Contains(t, reasons[incremental+"4000/data/ns1"], "incremental chain gap since")
+	assert.Len(t, reasons, 7, report.Failures)
+	assert.Equal(t, float64(7), testutil.ToFloat64(verificationFailuresGauge.WithLabelValues("routine")))
+}
+
+func TestVerifyBackups_Filter(t *testing.T) {
+	accessor, backend := verifiedBackend(t)
+	require.NoError(t, accessor.write("backups/routine/backup/1000/data/ns1/ns1_0.asb", []byte("corrupted")))
+	// the filtered verifications do not update the gauge of the routine
+	verificationFailuresGauge.WithLabelValues("routine").Set(0)
+
+	report := VerifyBackups(backend, "routine", VerificationFilter{Timestamp: util.Ptr[int64](3000)})
+	assert.True(t, report.Success, report.Failures)
+	assert.Equal(t, 2, report.BackupCount)
+
+	report = VerifyBackups(backend, "routine",
+		VerificationFilter{Key: util.Ptr("memory:///backups/routine/backup/1000/data/ns1")})
+	assert.False(t, report.Success)
+	assert.Equal(t, 1, report.BackupCount)
+	assert.Len(t, report.Failures, 1)
+
+	report = VerifyBackups(backend, "routine", VerificationFilter{Timestamp: util.Ptr[int64](5000)})
+	assert.Equal(t, model.JobStatusFailed, report.Status)
+	assert.Equal(t, ErrBackupNotFound.Error(), report.Error)
+	assert.Equal(t, float64(0), testutil.ToFloat64(verificationFailuresGauge.WithLabelValues("routine")))
+}
+
+func TestVerifyBackups_IncrementalInProgress(t *testing.T) {
+	accessor, backend := verifiedBackend(t)
+	// the running incremental backup has no metadata yet
+	delete(accessor.files, "backups/routine/incremental/4000/data/ns2/"+metadataFile)
+	backend.incrementalInProgress.Store(true)
+
+	report := VerifyBackups(backend, "routine", VerificationFilter{})
+
+	assert.True(t, report.Success, report.Failures)
+	assert.Equal(t, 7, report.BackupCount)
+}
+
+func TestStartVerification(t *testing.T) {
+	_, backend := verifiedBackend(t)
+
+	jobID := StartVerification(backend, "routine", VerificationFilter{})
+
+	var report *model.VerificationReport
+	require.Eventually(t, func() bool {
+		report, _ = VerificationStatus(jobID)
+		return report.Status != model.JobStatusRunning
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.True(t, report.Success, report.Failures)
+	assert.Equal(t, report, LastVerificationReport("routine"))
+	_, err := VerificationStatus(jobID + 1)
+	assert.Error(t, err)
+}
@@ -0,0 +1,89 @@
+package shared
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aerospike/backup/pkg/model"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// LoadS3Config returns the AWS SDK configuration of the S3 storage, with the
+// credentials of the S3 profile or the explicit credentials of the storage.
+func LoadS3Config(ctx context.Context, storage *model.Storage) (aws.Config, error) {
+	s3Credentials := storage.S3Credentials
+	options := []func(*config.LoadOptions) error{config.WithRegion(*storage.S3Region)}
+	// the explicit credentials do not require a shared config profile
+	if s3Credentials == nil || storage.S3Profile != nil {
+		storage.SetDefaultProfile()
+		options = append(options, config.WithSharedConfigProfile(*storage.S3Profile))
+	}
+	cfg, err := config.LoadDefaultConfig(ctx, options...)
+	if err != nil {
+		return aws.Config{}, err
+	}
+	if s3Credentials == nil {
+		return cfg, nil
+	}
+
+	if s3Credentials.HasStaticKeys() {
+		accessKeyID, err := ReadSecret(s3Credentials.AccessKeyIDEnv, s3Credentials.AccessKeyIDFile)
+		if err != nil {
+			return aws.Config{}, fmt.Errorf("failed to read s3 access key id: %w", err)
+		}
+		secretAccessKey, err := ReadSecret(s3Credentials.SecretAccessKeyEnv, s3Credentials.SecretAccessKeyFile)
+		if err != nil {
+			return aws.Config{}, fmt.Errorf("failed to read s3 secret access key: %w", err)
+		}
+		cfg.Credentials = credentials.NewStaticCredentialsProvider(accessKeyID, secretAccessKey, "")
+	}
+
+	if s3Credentials.RoleArn != nil {
+		stsClient := sts.NewFromConfig(cfg, func(o *sts.Options) {
+			if s3Credentials.StsEndpointOverride != nil && *s3Credentials.StsEndpointOverride != "" {
+				o.BaseEndpoint = aws.String(*s3Credentials.StsEndpointOverride)
+			}
+		})
+		sessionName := aws.ToString(s3Credentials.RoleSessionName)
+		if s3Credentials.WebIdentityTokenFile != nil {
+			cfg.Credentials = aws.NewCredentialsCache(stscreds.NewWebIdentityRoleProvider(stsClient,
+				*s3Credentials.RoleArn, stscreds.IdentityTokenFile(*s3Credentials.WebIdentityTokenFile),
+				func(o *stscreds.WebIdentityRoleOptions) {
+					o.RoleSessionName = sessionName
+				}))
+		} else {
+			cfg.Credentials = aws.NewCredentialsCache(stscreds.NewAssumeRoleProvider(stsClient,
+				*s3Credentials.RoleArn, func(o *stscreds.AssumeRoleOptions) {
+					o.RoleSessionName = sessionName
+					o.ExternalID = s3Credentials.ExternalID
+				}))
+		}
+	}
+	return cfg, nil
+}
+
+// ReadSecret reads a secret from the given environment variable or file.
+func ReadSecret(env, file *string) (string, error) {
+	if env != nil {
+		value, found := os.LookupEnv(*env)
+		if !found || value == "" {
+			return "", fmt.Errorf("environment variable %s is not set", *env)
+		}
+		return value, nil
+	}
+	content, err := os.ReadFile(*file)
+	if err != nil {
+		return "", err
+	}
+	value := strings.TrimSpace(string(content))
+	if value == "" {
+		return "", fmt.Errorf("file %s is empty", *file)
+	}
+	return value, nil
+}
@@ -0,0 +1,94 @@
+package shared
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/aerospike/backup/pkg/model"
+	"github.com/aws/smithy-go/ptr"
+)
+
+func TestLoadS3Config_StaticKeys(t *testing.T) {
+	secretFile := filepath.Join(t.TempDir(), "secret-access-key")
+	if err := os.WriteFile(secretFile, []byte("secret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TEST_ACCESS_KEY_ID", "key-id")
+	storage := &model.Storage{
+		Type:     model.S3,
+		S3Region: ptr.String("eu-central-1"),
+		S3Credentials: &model.S3Credentials{
+			AccessKeyIDEnv:      ptr.String("TEST_ACCESS_KEY_ID"),
+			SecretAccessKeyFile: &secretFile,
+		},
+	}
+
+	cfg, err := LoadS3Config(context.Background(), storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	credentials, err := cfg.Credentials.Retrieve(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if credentials.AccessKeyID != "key-id" || credentials.SecretAccessKey != "secret" {
+		t.Errorf("Expected static credentials, got %v", credentials)
+	}
+}
+
+func TestLoadS3Config_MissingSecret(t *testing.T) {
+	storage := &model.Storage{
+		Type:     model.S3,
+		S3Region: ptr.String("eu-central-1"),
+		S3Credentials: &model.S3Credentials{
+			AccessKeyIDEnv:      ptr.String("TEST_MISSING_ACCESS_KEY_ID"),
+			SecretAccessKeyFile: ptr.String(filepath.Join(t.TempDir(), "missing")),
+		},
+	}
+	if _, err := LoadS3Config(context.Background(), storage); err == nil {
+		t.Error("Expected error on missing credentials")
+	}
+}
+
+func TestLoadS3Config_StsEndpointOverride(t *testing.T) {
+	var requests atomic.Int32
+	sts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "text/xml")
+		_, _ = w.Write([]byte(`<AssumeRoleResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+<AssumeRoleResult><Credentials><AccessKeyId>role-key-id</AccessKeyId><SecretAccessKey>role-secret</SecretAccessKey>
+<SessionToken>token</SessionToken><Expiration>2100-01-01T00:00:00Z</Expiration></Credentials>
+</AssumeRoleResult></AssumeRoleResponse>`))
+	}))
+	defer sts.Close()
+	t.Setenv("TEST_ACCESS_KEY_ID", "key-id")
+	t.Setenv("TEST_SECRET_ACCESS_KEY", "secret")
+	storage := &model.Storage{
+		Type:               model.S3,
+		S3Region:           ptr.String("eu-central-1"),
+		S3EndpointOverride: ptr.String("http://s3.invalid"),
+		S3Credentials: &model.S3Credentials{
+			AccessKeyIDEnv:      ptr.String("TEST_ACCESS_KEY_ID"),
+			SecretAccessKeyEnv:  ptr.String("TEST_SECRET_ACCESS_KEY"),
+			RoleArn:             ptr.String("arn:aws:iam::111122223333:role/backup"),
+			StsEndpointOverride: ptr.String(sts.URL),
+		},
+	}
+
+	cfg, err := LoadS3Config(context.Background(), storage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	credentials, err := cfg.Credentials.Retrieve(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if credentials.AccessKeyID != "role-key-id" || requests.Load() != 1 {
+		t.Errorf("Expected the role credentials from the STS endpoint, got %v", credentials)
+	}
+}
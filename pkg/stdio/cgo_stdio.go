@@ -15,6 +15,8 @@ import (
 
 type CgoStdio interface {
 	Capture(f func()) string
+	// Exclusive returns true if the captured functions run one at a time.
+	Exclusive() bool
 }
 
 type CgoStdioImpl struct {
@@ -31,21 +33,27 @@ func NewCgoStdio(capture bool) *CgoStdioImpl {
 	}
 }
 
+// Exclusive returns true if the shared library logs are captured.
+func (c *CgoStdioImpl) Exclusive() bool {
+	return c.capture
+}
+
 // Stderr log capturer.
 var Stderr CgoStdio
 
 // Capture captures and returns the stderr output produced by the
-// given function f.
+// given function f. The stderr of the process is redirected during the capture,
+// so the captured functions run one at a time; the others run concurrently.
 func (c *CgoStdioImpl) Capture(f func()) string {
-	c.Lock()
-	defer c.Unlock()
-
 	// don't capture shared library logs
 	if !c.capture {
 		f()
 		return ""
 	}
 
+	c.Lock()
+	defer c.Unlock()
+
 	output, executed := ExecuteAndCapture(f)
 	if !executed {
 		f()
@@ -16,3 +16,8 @@ func (c *CgoStdioMock) Capture(f func()) string {
 	f()
 	return ""
 }
+
+// Exclusive returns false, as nothing is captured.
+func (c *CgoStdioMock) Exclusive() bool {
+	return false
+}
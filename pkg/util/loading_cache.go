@@ -50,6 +50,25 @@ func (c *LoadingCache[K, T]) Get(key K) (T, error) {
 	return loadedValue, nil
 }
 
+// Invalidate removes the value for the specified key, so that it will
+// be loaded again on the next Get.
+func (c *LoadingCache[K, T]) Invalidate(key K) {
+	c.Lock()
+	defer c.Unlock()
+	delete(c.data, key)
+}
+
+// InvalidateIf removes the values for all the keys matching the predicate.
+func (c *LoadingCache[K, T]) InvalidateIf(predicate func(K) bool) {
+	c.Lock()
+	defer c.Unlock()
+	for key := range c.data {
+		if predicate(key) {
+			delete(c.data, key)
+		}
+	}
+}
+
 func (c *LoadingCache[T, K]) startCleanup() {
 	ticker := time.NewTicker(time.Hour)
 	defer ticker.Stop()
@@ -36,3 +36,42 @@ func TestLoadingCache_Error(t *testing.T) {
 		t.Error("Error must not be nil")
 	}
 }
+
+func TestLoadingCache_Invalidate(t *testing.T) {
+	loads := 0
+	cache := NewLoadingCache(context.Background(), func(key string) (int, error) {
+		loads++
+		return strconv.Atoi(key)
+	})
+	_, _ = cache.Get("1")
+	_, _ = cache.Get("1")
+	if loads != 1 {
+		t.Errorf("The value is expected to be loaded once, loaded %d times", loads)
+	}
+	cache.Invalidate("1")
+	_, _ = cache.Get("1")
+	if loads != 2 {
+		t.Errorf("The value is expected to be reloaded after invalidation, loaded %d times", loads)
+	}
+}
+
+func TestLoadingCache_InvalidateIf(t *testing.T) {
+	loads := 0
+	cache := NewLoadingCache(context.Background(), func(key string) (int, error) {
+		loads++
+		return strconv.Atoi(key)
+	})
+	_, _ = cache.Get("10")
+	_, _ = cache.Get("11")
+	_, _ = cache.Get("20")
+	cache.InvalidateIf(func(key string) bool { return key[0] == '1' })
+	_, _ = cache.Get("20")
+	if loads != 3 {
+		t.Errorf("The value of the unmatched key is expected to stay cached, loaded %d times", loads)
+	}
+	_, _ = cache.Get("10")
+	_, _ = cache.Get("11")
+	if loads != 5 {
+		t.Errorf("The values of the matched keys are expected to be reloaded, loaded %d times", loads)
+	}
+}